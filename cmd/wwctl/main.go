@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -16,6 +17,10 @@ func main() {
 		if wwctl.DebugFlag {
 			fmt.Printf("\nSTACK TRACE: %+v\n", err)
 		}
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(255)
 	}
 }
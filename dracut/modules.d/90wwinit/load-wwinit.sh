@@ -1,20 +1,55 @@
 #!/bin/bash
 
-info "Mounting tmpfs at $NEWROOT"
-mount -t tmpfs -o mpol=interleave ${wwinit_tmpfs_size_option} tmpfs "$NEWROOT"
+load_archive() {
+    archive="$1"
+    info "Loading ${archive}"
+    # Load runtime overlay from a static privledged port.
+    # Others use default settings.
+    localport=""
+    if [[ "${archive}" == "${wwinit_runtime}" ]]
+    then
+        localport="--local-port 1-1023"
+    fi
+    (curl --retry 60 --retry-delay 1 --silent ${localport} -L "${archive}" | gzip -d | cpio -im --directory="${NEWROOT}") || die "Unable to load ${archive}"
+}
+
+if [ "${wwinit_format}" = "squashfs" ] || [ "${wwinit_format}" = "erofs" ]
+then
+    # Only the compressed image is kept in memory and mounted read-only;
+    # changes go to an overlayfs upper layer in tmpfs. /run/initramfs
+    # is moved into the new root, so the mounts stay visible there.
+    imagedir=/run/initramfs/wwinit
+    mkdir -p "${imagedir}"
+    mount -t tmpfs -o mode=0700 tmpfs "${imagedir}" || die "Unable to mount tmpfs at ${imagedir}"
+    info "Loading ${wwinit_container}"
+    curl --retry 60 --retry-delay 1 --silent -L -o "${imagedir}/image" "${wwinit_container}" || die "Unable to load ${wwinit_container}"
+    mkdir -p "${imagedir}/lower"
+    mount -t "${wwinit_format}" -o loop,ro "${imagedir}/image" "${imagedir}/lower" || die "Unable to mount the ${wwinit_format} image"
+
+    if [ "${wwinit_overlayfs}" = "0" ]
+    then
+        info "Mounting the ${wwinit_format} image read-only at $NEWROOT"
+        mount -o bind,ro "${imagedir}/lower" "$NEWROOT" || die "Unable to mount the image at $NEWROOT"
+        warn "wwinit.overlayfs=0: the system and runtime overlays are not applied"
+        return 0
+    fi
+
+    info "Mounting overlayfs with a tmpfs upper layer at $NEWROOT"
+    mkdir -p "${imagedir}/rw"
+    mount -t tmpfs -o mpol=interleave ${wwinit_tmpfs_size_option} tmpfs "${imagedir}/rw" || die "Unable to mount tmpfs at ${imagedir}/rw"
+    mkdir -p "${imagedir}/rw/upper" "${imagedir}/rw/work"
+    mount -t overlay overlay -o "lowerdir=${imagedir}/lower,upperdir=${imagedir}/rw/upper,workdir=${imagedir}/rw/work" "$NEWROOT" || die "Unable to mount overlayfs at $NEWROOT"
+    archives="${wwinit_system} ${wwinit_runtime}"
+else
+    info "Mounting tmpfs at $NEWROOT"
+    mount -t tmpfs -o mpol=interleave ${wwinit_tmpfs_size_option} tmpfs "$NEWROOT"
+    archives="${wwinit_container} ${wwinit_system} ${wwinit_runtime}"
+fi
 
-for archive in "${wwinit_container}" "${wwinit_system}" "${wwinit_runtime}"
+for archive in ${archives}
 do
     if [ -n "${archive}" ]
     then
-        info "Loading ${archive}"
-        # Load runtime overlay from a static privledged port.
-        # Others use default settings.
-        localport=""
-        if [[ "${archive}" == "${wwinit_runtime}" ]]
-        then
-            localport="--local-port 1-1023"
-        fi
-        (curl --retry 60 --retry-delay 1 --silent ${localport} -L "${archive}" | gzip -d | cpio -im --directory="${NEWROOT}") || die "Unable to load ${archive}"
+        load_archive "${archive}"
     fi
 done
@@ -15,6 +15,7 @@ depends() {
 
 install() {
     inst_multiple cpio curl dmidecode
+    instmods loop overlay squashfs erofs
     inst_hook cmdline 30 "$moddir/parse-wwinit.sh"
     inst_hook pre-mount 30 "$moddir/load-wwinit.sh"
 }
@@ -9,7 +9,18 @@ then
     uuid=$(dmidecode -s system-uuid)
     assetkey=$(dmidecode -s chassis-asset-tag | sed -E -e 's/(^ +| +$)//g' -e 's/^(Unknown|Not Specified)$//g' -e 's/ /_/g')
     wwinit_uri="$(getarg wwinit.uri)?assetkey=${assetkey}&uuid=${uuid}"
-    export wwinit_container="${wwinit_uri}&stage=container&compress=gz"; info "wwinit_container=${wwinit_container}"
+    wwinit_format=$(getarg wwinit.format=)
+    [ -z "${wwinit_format}" ] && wwinit_format=cpio
+    export wwinit_format; info "wwinit.format=${wwinit_format}"
+    if [ "${wwinit_format}" = "cpio" ]
+    then
+        export wwinit_container="${wwinit_uri}&stage=container&compress=gz"
+    else
+        # squashfs and erofs images are compressed already
+        export wwinit_container="${wwinit_uri}&stage=container"
+    fi
+    info "wwinit_container=${wwinit_container}"
+    export wwinit_overlayfs=$(getarg wwinit.overlayfs=)
     export wwinit_system="${wwinit_uri}&stage=system&compress=gz"; info "wwinit_system=${wwinit_system}"
     export wwinit_runtime="${wwinit_uri}&stage=runtime&compress=gz"; info "wwinit_runtime=${wwinit_runtime}"
 
@@ -314,6 +314,17 @@ func (s *apiServer) NodeSet(ctx context.Context, request *wwapiv1.ConfSetParamet
 	return s.nodeListInternal(request.ConfList)
 }
 
+// NodeCheck checks the consistency of the node database and returns the
+// problems found.
+func (s *apiServer) NodeCheck(ctx context.Context, request *emptypb.Empty) (response *wwapiv1.NodeCheckResponse, err error) {
+
+	results, err := apinode.NodeCheck()
+	if err != nil {
+		return
+	}
+	return apinode.CheckResponse(results), nil
+}
+
 func (s *apiServer) NodeStatus(ctx context.Context, request *wwapiv1.NodeNames) (response *wwapiv1.NodeStatusResponse, err error) {
 
 	// Parameter checks. request.NodeNames can be nil.
@@ -52,6 +52,9 @@ curl http://localhost:9871/v1/nodestatus
 
 curl http://localhost:9871/v1/nodestatus?nodeNames=testApiNode0
 
+# node check
+curl http://localhost:9871/v1/nodecheck
+
 # node delete single node
 curl -X DELETE http://localhost:9871/v1/node?nodeNames=testApiNode0
 curl -X DELETE http://localhost:9871/v1/node?nodeNames=testApiNode1
@@ -52,3 +52,20 @@ func Containers(cmd *cobra.Command, args []string, toComplete string) ([]string,
 	sources, _ := container.ListSources()
 	return sources, cobra.ShellCompDirectiveNoFileComp
 }
+
+// ContainerSnapshots completes a container as the first argument and one
+// of its snapshots as the second one
+func ContainerSnapshots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return Containers(cmd, args, toComplete)
+	case 1:
+		snapshots, _ := container.Snapshots(args[0])
+		var names []string
+		for _, snapshot := range snapshots {
+			names = append(names, snapshot.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
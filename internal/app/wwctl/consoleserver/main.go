@@ -0,0 +1,79 @@
+package consoleserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/console"
+	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/power"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %s", err)
+		}
+
+		nodes, err := nodeDB.FindAllNodes()
+		if err != nil {
+			return fmt.Errorf("could not get node list: %s", err)
+		}
+
+		args = hostlist.Expand(args)
+		if len(args) > 0 {
+			nodes, err = node.FilterNodeListByName(nodes, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		conf := warewulfconf.Get()
+		server := console.NewServer(conf.Paths.ConsoleDir(), vars.MaxSize*1024*1024, vars.Keep)
+		captured := 0
+		for _, n := range captureNodes(nodes, len(args) > 0) {
+			ipmi := *n.Ipmi
+			server.Add(n.Id(), func() (io.ReadWriteCloser, error) {
+				bmc, err := power.New(ipmi, false)
+				if err != nil {
+					return nil, err
+				}
+				return bmc.OpenConsole()
+			})
+			wwlog.Verbose("%s: capturing console", n.Id())
+			captured++
+		}
+		if captured == 0 {
+			return fmt.Errorf("no nodes with an IPMI IP address found")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		wwlog.Info("Capturing the consoles of %d nodes in %s", captured, conf.Paths.ConsoleDir())
+		return server.Run(ctx)
+	}
+}
+
+// captureNodes returns the nodes with an IPMI address. If the nodes were
+// named explicitly, a warning is logged for the others.
+func captureNodes(nodes []node.Node, named bool) (captured []node.Node) {
+	for _, n := range nodes {
+		if n.Ipmi == nil || n.Ipmi.Ipaddr == nil || n.Ipmi.Ipaddr.IsUnspecified() {
+			if named {
+				wwlog.Warn("%s: no IPMI IP address, console not captured", n.Id())
+			}
+			continue
+		}
+		captured = append(captured, n)
+	}
+	return captured
+}
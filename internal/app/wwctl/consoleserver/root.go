@@ -0,0 +1,38 @@
+package consoleserver
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+type variables struct {
+	MaxSize int64
+	Keep    int
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "console-server [OPTIONS] [PATTERN]",
+		Short:                 "Capture the serial consoles of nodes",
+		Long: "Keep the serial-over-LAN consoles of the nodes matching PATTERN, or of all\n" +
+			"nodes with an IPMI address, open and write their output to timestamped log\n" +
+			"files. Use \"wwctl node console\" to attach to a captured console or to show\n" +
+			"its log with --log.",
+		RunE: CobraRunE(&vars),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			nodeDB, _ := node.New()
+			nodes, _ := nodeDB.FindAllNodes()
+			var node_names []string
+			for _, node := range nodes {
+				node_names = append(node_names, node.Id())
+			}
+			return node_names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	baseCmd.PersistentFlags().Int64Var(&vars.MaxSize, "max-size", 10, "rotate a console log when it exceeds this size in MB, 0 to never rotate")
+	baseCmd.PersistentFlags().IntVar(&vars.Keep, "keep", 5, "number of rotated console logs to keep")
+	return baseCmd
+}
@@ -0,0 +1,66 @@
+package consoleserver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_ConsoleServer(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n01: {}
+  n02:
+    ipmi:
+      username: admin`)
+
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"all nodes": {
+			args: []string{},
+		},
+		"named nodes": {
+			args:     []string{"n01", "n02"},
+			expected: "WARN   : n01: no IPMI IP address, console not captured\nWARN   : n02: no IPMI IP address, console not captured\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			baseCmd := GetCommand()
+			baseCmd.SilenceUsage = true
+			baseCmd.SilenceErrors = true
+			baseCmd.SetArgs(tt.args)
+			buf := new(bytes.Buffer)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			wwlog.SetLogWriter(buf)
+			err := baseCmd.Execute()
+			assert.EqualError(t, err, "no nodes with an IPMI IP address found")
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func Test_captureNodes(t *testing.T) {
+	withAddress := node.NewNode("n01")
+	withAddress.Ipmi.Ipaddr = []byte{10, 10, 10, 10}
+	unspecified := node.NewNode("n02")
+	unspecified.Ipmi.Ipaddr = []byte{0, 0, 0, 0}
+	noAddress := node.NewNode("n03")
+	noIpmi := node.NewNode("n04")
+	noIpmi.Ipmi = nil
+
+	captured := captureNodes([]node.Node{withAddress, unspecified, noAddress, noIpmi}, false)
+	assert.Len(t, captured, 1)
+	assert.Equal(t, "n01", captured[0].Id())
+}
@@ -1,16 +1,35 @@
 package build
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
-	"github.com/warewulf/warewulf/internal/pkg/api/container"
+	apicontainer "github.com/warewulf/warewulf/internal/pkg/api/container"
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/container"
 )
 
-func CobraRunE(cmd *cobra.Command, args []string) error {
+func CobraRunE(cmd *cobra.Command, args []string) (err error) {
+	if BuildFormat != "" {
+		names := args
+		if BuildAll {
+			if names, err = container.ListSources(); err != nil {
+				return err
+			}
+		}
+		for _, name := range names {
+			if !container.ValidSource(name) {
+				return fmt.Errorf("VNFS name does not exist: %s", name)
+			}
+			if err := container.SetFormat(name, BuildFormat); err != nil {
+				return err
+			}
+		}
+	}
 	cbp := &wwapiv1.ContainerBuildParameter{
 		ContainerNames: args,
 		Force:          BuildForce,
 		All:            BuildAll,
 	}
-	return container.ContainerBuild(cbp)
+	return apicontainer.ContainerBuild(cbp)
 }
@@ -1,6 +1,10 @@
 package build
 
 import (
+	"fmt"
+	"log"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/warewulf/warewulf/internal/pkg/container"
 )
@@ -10,9 +14,12 @@ var (
 		DisableFlagsInUseLine: true,
 		Use:                   "build [OPTIONS] CONTAINER [...]",
 		Short:                 "(Re)build a bootable VNFS image",
-		Long:                  "This command will build a bootable VNFS image from imported CONTAINER image(s).",
-		RunE:                  CobraRunE,
-		Args:                  cobra.ArbitraryArgs,
+		Long: `This command will build a bootable VNFS image from imported CONTAINER image(s).
+The image is a cpio archive, which the node unpacks into memory, or a squashfs
+or erofs image, which the dracut boot path mounts. The format given with
+--format is kept for later builds of the container.`,
+		RunE: CobraRunE,
+		Args: cobra.ArbitraryArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
 				return nil, cobra.ShellCompDirectiveNoFileComp
@@ -21,13 +28,20 @@ var (
 			return list, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	BuildForce bool
-	BuildAll   bool
+	BuildForce  bool
+	BuildAll    bool
+	BuildFormat string
 )
 
 func init() {
 	baseCmd.PersistentFlags().BoolVarP(&BuildAll, "all", "a", false, "(re)Build all VNFS images for all nodes")
 	baseCmd.PersistentFlags().BoolVarP(&BuildForce, "force", "f", false, "Force rebuild, even if it isn't necessary")
+	baseCmd.PersistentFlags().StringVar(&BuildFormat, "format", "", fmt.Sprintf("Set the image format (%s)", strings.Join(container.Formats, ", ")))
+	if err := baseCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return container.Formats, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		log.Println(err)
+	}
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -0,0 +1,49 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		var diff *container.Diff
+		if vars.sinceBuild {
+			diff, err = container.DiffSinceBuild(args[0])
+		} else {
+			diff, err = container.DiffContainers(args[0], args[1])
+		}
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if len(diff.Files) == 0 && len(diff.Packages) == 0 {
+			fmt.Fprintln(out, "No changes")
+			return nil
+		}
+		if len(diff.Packages) > 0 {
+			t := table.New(out)
+			t.AddHeader("PACKAGE", "FROM", "TO")
+			for _, p := range diff.Packages {
+				t.AddLine(table.Prep([]string{p.Name, strings.Join(p.From, ", "), strings.Join(p.To, ", ")})...)
+			}
+			t.Print()
+			if len(diff.Files) > 0 {
+				fmt.Fprintln(out)
+			}
+		}
+		for _, f := range diff.Files {
+			if len(f.Details) > 0 {
+				fmt.Fprintf(out, "%s %s (%s)\n", f.Change, f.Path, strings.Join(f.Details, ", "))
+			} else {
+				fmt.Fprintf(out, "%s %s\n", f.Change, f.Path)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Diff(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/etc/hostname", "a")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/etc/removed", "")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/var/lib/dpkg/status", `Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.1-6
+`)
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/etc/hostname", "b")
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/var/lib/dpkg/status", `Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.1-7
+`)
+
+	run := func(args ...string) (string, error) {
+		baseCmd := GetCommand()
+		baseCmd.SilenceUsage = true
+		baseCmd.SilenceErrors = true
+		buf := new(bytes.Buffer)
+		baseCmd.SetOut(buf)
+		baseCmd.SetErr(buf)
+		wwlog.SetLogWriter(buf)
+		baseCmd.SetArgs(args)
+		err := baseCmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, `PACKAGE     FROM   TO
+-------     ----   --
+bash:amd64  5.1-6  5.1-7
+
+M /etc/hostname
+D /etc/removed
+M /var/lib/dpkg/status
+`, out)
+
+	out, err = run("a", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "No changes\n", out)
+
+	_, err = run("a")
+	assert.EqualError(t, err, "diff compares two containers, 1 given")
+	_, err = run("--since-build", "a", "b")
+	assert.EqualError(t, err, "--since-build compares one container, 2 given")
+	_, err = run("--since-build", "a")
+	assert.EqualError(t, err, "no build of a is recorded, build it first")
+}
@@ -0,0 +1,44 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+type variables struct {
+	sinceBuild bool
+}
+
+// GetCommand returns the diff command
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "diff [OPTIONS] CONTAINER [CONTAINER]",
+		Short:                 "Show the changes between containers",
+		Long: `This command compares the rootfs of two containers or snapshots, given as
+CONTAINER@SNAPSHOT, and shows the packages which were added, removed or
+changed, and the files which were added (A), removed (D) or modified (M),
+including changes of their mode and owner. Packages are read from the
+dpkg status file or with rpm, if it is installed.
+
+With --since-build, the rootfs of CONTAINER is compared with its state
+when its image was last built.`,
+		Example: `wwctl container diff rocky-9@pre-update rocky-9
+wwctl container diff --since-build rocky-9`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if vars.sinceBuild && len(args) != 1 {
+				return fmt.Errorf("--since-build compares one container, %d given", len(args))
+			} else if !vars.sinceBuild && len(args) != 2 {
+				return fmt.Errorf("diff compares two containers, %d given", len(args))
+			}
+			return nil
+		},
+		RunE:              CobraRunE(&vars),
+		ValidArgsFunction: completions.Containers,
+	}
+	baseCmd.PersistentFlags().BoolVar(&vars.sinceBuild, "since-build", false, "Compare the container with its last build")
+	return baseCmd
+}
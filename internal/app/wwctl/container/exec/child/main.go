@@ -97,8 +97,8 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 		if err != nil {
 			return fmt.Errorf("could not get node list: %s", err)
 		}
-		filteredNodes := node.FilterNodeListByName(allNodes, []string{nodename})
-		if len(filteredNodes) != 1 {
+		filteredNodes, err := node.FilterNodeListByName(allNodes, []string{nodename})
+		if err != nil || len(filteredNodes) != 1 {
 			return fmt.Errorf("no single node idendified with %s", nodename)
 		}
 		overlays := filteredNodes[0].SystemOverlay
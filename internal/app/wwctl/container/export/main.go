@@ -0,0 +1,19 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		switch {
+		case vars.ociArchive != "":
+			return container.Export(args[0], container.ExportOciArchive, vars.ociArchive, vars.platform)
+		case vars.ociDir != "":
+			return container.Export(args[0], container.ExportOciDir, vars.ociDir, vars.platform)
+		default:
+			return container.Export(args[0], container.ExportDockerArchive, vars.dockerArchive, vars.platform)
+		}
+	}
+}
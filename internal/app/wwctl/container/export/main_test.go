@@ -0,0 +1,37 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Export(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/rocky/rootfs/etc/os-release", "9.4")
+
+	run := func(args ...string) error {
+		baseCmd := GetCommand()
+		baseCmd.SilenceUsage = true
+		baseCmd.SilenceErrors = true
+		buf := new(bytes.Buffer)
+		baseCmd.SetOut(buf)
+		baseCmd.SetErr(buf)
+		wwlog.SetLogWriter(buf)
+		baseCmd.SetArgs(args)
+		return baseCmd.Execute()
+	}
+
+	assert.ErrorContains(t, run("rocky"), "at least one of the flags")
+	assert.ErrorContains(t, run("rocky", "--oci-dir", env.GetPath("oci"), "--oci-archive", env.GetPath("rocky.tar")), "were all set")
+	assert.ErrorContains(t, run("rocky", "--oci-archive", env.GetPath("rocky.tar")), "could not determine the architecture of rocky")
+	assert.NoError(t, run("rocky", "--platform", "arm64", "--oci-archive", env.GetPath("rocky.tar")))
+	assert.FileExists(t, env.GetPath("rocky.tar"))
+	assert.NoError(t, run("rocky", "--platform", "arm64", "--oci-dir", env.GetPath("oci")))
+	assert.FileExists(t, env.GetPath("oci/index.json"))
+	assert.EqualError(t, run("missing", "--oci-dir", env.GetPath("oci")), "container does not exist: missing")
+}
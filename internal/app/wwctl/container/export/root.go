@@ -0,0 +1,41 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+type variables struct {
+	ociArchive    string
+	ociDir        string
+	dockerArchive string
+	platform      string
+}
+
+// GetCommand returns the export command
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "export CONTAINER (--oci-archive FILE | --oci-dir DIR | --docker-archive FILE)",
+		Short:                 "Export a container as an OCI image",
+		Long: `This command writes the rootfs of CONTAINER, or of a snapshot given as
+CONTAINER@SNAPSHOT, as an image with a single layer, e.g. to run it with
+podman or to import it at another site. The image is tagged as
+CONTAINER:latest, or CONTAINER:SNAPSHOT for a snapshot. The architecture
+of the image is read from /bin/sh in the container, unless it is set with
+--platform.`,
+		Example: `wwctl container export rocky-9 --oci-archive rocky-9.tar
+podman load -i rocky-9.tar`,
+		Args:              cobra.ExactArgs(1),
+		RunE:              CobraRunE(&vars),
+		ValidArgsFunction: completions.Containers,
+	}
+	baseCmd.PersistentFlags().StringVar(&vars.ociArchive, "oci-archive", "", "Write an OCI archive to FILE")
+	baseCmd.PersistentFlags().StringVar(&vars.ociDir, "oci-dir", "", "Write an OCI layout to DIR")
+	baseCmd.PersistentFlags().StringVar(&vars.dockerArchive, "docker-archive", "", "Write a docker archive to FILE")
+	baseCmd.PersistentFlags().StringVar(&vars.platform, "platform", "", "Set the architecture of the image, e.g. amd64 or arm64")
+	baseCmd.MarkFlagsMutuallyExclusive("oci-archive", "oci-dir", "docker-archive")
+	baseCmd.MarkFlagsOneRequired("oci-archive", "oci-dir", "docker-archive")
+	return baseCmd
+}
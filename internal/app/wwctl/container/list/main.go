@@ -71,6 +71,23 @@ func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err err
 					)
 				}
 			}
+		} else if vars.source {
+			t.AddHeader("CONTAINER NAME", "SOURCE", "DIGEST", "IMPORTED")
+			list, err := container.ListSources()
+			if err != nil {
+				return err
+			}
+			for _, cont := range list {
+				source, err := container.GetSource(cont)
+				if err != nil {
+					return err
+				}
+				if source == nil {
+					t.AddLine(cont, "--", "--", "--")
+					continue
+				}
+				t.AddLine(cont, source.URI, source.Digest, source.Imported.Format(time.RFC822))
+			}
 		} else {
 			t.AddHeader("CONTAINER NAME")
 			list, _ := container.ListSources()
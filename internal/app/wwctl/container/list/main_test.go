@@ -74,3 +74,31 @@ nodes:
 		})
 	}
 }
+
+func Test_ListSource(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `nodes: {}`)
+	env.WriteFile("var/lib/warewulf/chroots/local/rootfs/bin/sh", "")
+	env.WriteFile("var/lib/warewulf/chroots/rocky/rootfs/bin/sh", "")
+	env.WriteFile("var/lib/warewulf/chroots/rocky/source.json", `{
+  "uri": "docker://example.org/rocky:9",
+  "digest": "sha256:1111",
+  "imported": "2024-01-02T03:04:05Z"
+}`)
+
+	buf := new(bytes.Buffer)
+	baseCmd := GetCommand()
+	baseCmd.SetArgs([]string{"--source"})
+	baseCmd.SetOut(buf)
+	baseCmd.SetErr(buf)
+	wwlog.SetLogWriter(buf)
+	assert.NoError(t, baseCmd.Execute())
+	assert.Equal(t, strings.TrimSpace(`
+CONTAINER NAME  SOURCE                        DIGEST       IMPORTED
+--------------  ------                        ------       --------
+local           --                            --           --
+rocky           docker://example.org/rocky:9  sha256:1111  02 Jan 24 03:04 UTC
+`), strings.TrimSpace(buf.String()))
+}
@@ -10,6 +10,7 @@ type variables struct {
 	kernel     bool
 	chroot     bool
 	compressed bool
+	source     bool
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -28,5 +29,6 @@ func GetCommand() *cobra.Command {
 	baseCmd.PersistentFlags().BoolVarP(&vars.size, "size", "s", false, "show size information")
 	baseCmd.PersistentFlags().BoolVarP(&vars.chroot, "chroot", "c", false, "show size of chroot")
 	baseCmd.PersistentFlags().BoolVar(&vars.compressed, "compressed", false, "show size of the compressed image")
+	baseCmd.PersistentFlags().BoolVar(&vars.source, "source", false, "show the OCI image the container was imported from")
 	return baseCmd
 }
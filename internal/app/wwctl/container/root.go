@@ -5,14 +5,18 @@ import (
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/build"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/copy"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/delete"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/diff"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/exec"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/export"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/imprt"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/kernels"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/list"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/rename"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/shell"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/show"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/snapshot"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container/syncuser"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/update"
 )
 
 var baseCmd = &cobra.Command{
@@ -37,6 +41,10 @@ func init() {
 	baseCmd.AddCommand(copy.GetCommand())
 	baseCmd.AddCommand(rename.GetCommand())
 	baseCmd.AddCommand(kernels.GetCommand())
+	baseCmd.AddCommand(snapshot.GetCommand())
+	baseCmd.AddCommand(update.GetCommand())
+	baseCmd.AddCommand(export.GetCommand())
+	baseCmd.AddCommand(diff.GetCommand())
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
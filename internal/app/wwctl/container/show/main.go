@@ -2,9 +2,11 @@ package show
 
 import (
 	"fmt"
+	"time"
 
-	"github.com/warewulf/warewulf/internal/pkg/api/container"
+	apicontainer "github.com/warewulf/warewulf/internal/pkg/api/container"
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/container"
 
 	"github.com/spf13/cobra"
 )
@@ -16,7 +18,7 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	var r *wwapiv1.ContainerShowResponse
-	r, err = container.ContainerShow(csp)
+	r, err = apicontainer.ContainerShow(csp)
 	if err != nil {
 		return
 	}
@@ -31,6 +33,16 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 		fmt.Printf("Name: %s\n", r.Name)
 		fmt.Printf("KernelVersion: %s\n", kernelVersion)
 		fmt.Printf("Rootfs: %s\n", r.Rootfs)
+		fmt.Printf("ImageFormat: %s\n", container.Format(r.Name))
+		source, err := container.GetSource(r.Name)
+		if err != nil {
+			return err
+		}
+		if source != nil {
+			fmt.Printf("Source: %s\n", source.URI)
+			fmt.Printf("Digest: %s\n", source.Digest)
+			fmt.Printf("Imported: %s\n", source.Imported.Format(time.RFC822))
+		}
 		fmt.Printf("Nr nodes: %d\n", len(r.Nodes))
 		fmt.Printf("Nodes: %s\n", r.Nodes)
 	}
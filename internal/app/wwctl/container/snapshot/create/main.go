@@ -0,0 +1,23 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		snapshot := ""
+		if len(args) > 1 {
+			snapshot = args[1]
+		}
+		snapshot, err := container.CreateSnapshot(args[0], snapshot, vars.comment)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), container.SnapshotName(args[0], snapshot))
+		return nil
+	}
+}
@@ -0,0 +1,27 @@
+package create
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+type variables struct {
+	comment string
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "create [OPTIONS] CONTAINER [SNAPSHOT]",
+		Short:                 "Create a snapshot of a container",
+		Long: `This command keeps the current rootfs and images of CONTAINER as SNAPSHOT,
+named after the current time by default. Unchanged files are shared
+with the previous snapshot.`,
+		Args:              cobra.RangeArgs(1, 2),
+		RunE:              CobraRunE(&vars),
+		ValidArgsFunction: completions.Containers,
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.comment, "comment", "c", "", "Describe the snapshot")
+	return baseCmd
+}
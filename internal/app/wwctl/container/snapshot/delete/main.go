@@ -0,0 +1,54 @@
+package delete
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apiutil "github.com/warewulf/warewulf/internal/pkg/api/util"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %w", err)
+		}
+		used := make(map[string]string)
+		nodes, err := nodeDB.FindAllNodes()
+		if err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			used[n.ContainerName] = "node " + n.Id()
+		}
+		profiles, err := nodeDB.FindAllProfiles()
+		if err != nil {
+			return err
+		}
+		for _, p := range profiles {
+			used[p.ContainerName] = "profile " + p.Id()
+		}
+
+		for _, snapshot := range args[1:] {
+			ref := container.SnapshotName(args[0], snapshot)
+			if user, ok := used[ref]; ok {
+				return fmt.Errorf("%s is used by %s", ref, user)
+			}
+		}
+
+		if !vars.yes {
+			yes := apiutil.ConfirmationPrompt(fmt.Sprintf("Are you sure you want to delete the snapshots %s of %s", args[1:], args[0]))
+			if !yes {
+				return nil
+			}
+		}
+		for _, snapshot := range args[1:] {
+			if err := container.DeleteSnapshot(args[0], snapshot); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
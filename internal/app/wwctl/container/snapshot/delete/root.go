@@ -0,0 +1,27 @@
+package delete
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+type variables struct {
+	yes bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "delete [OPTIONS] CONTAINER SNAPSHOT [...]",
+		Aliases:               []string{"rm", "remove", "del"},
+		Short:                 "Delete snapshots of a container",
+		Long: `This command deletes SNAPSHOTs of CONTAINER and their images. Snapshots which
+nodes or profiles use are kept.`,
+		Args:              cobra.MinimumNArgs(2),
+		RunE:              CobraRunE(&vars),
+		ValidArgsFunction: completions.ContainerSnapshots,
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.yes, "yes", "y", false, "Set 'yes' to all questions asked")
+	return baseCmd
+}
@@ -0,0 +1,31 @@
+package list
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+)
+
+func CobraRunE(cmd *cobra.Command, args []string) error {
+	if container.IsSnapshot(args[0]) || !container.ValidSource(args[0]) {
+		return fmt.Errorf("container does not exist: %s", args[0])
+	}
+	snapshots, err := container.Snapshots(args[0])
+	if err != nil {
+		return err
+	}
+	t := table.New(cmd.OutOrStdout())
+	t.AddHeader("SNAPSHOT", "CREATED", "FORMAT", "COMMENT")
+	for _, snapshot := range snapshots {
+		t.AddLine(
+			container.SnapshotName(args[0], snapshot.Name),
+			snapshot.Created.Format(time.RFC822),
+			container.Format(container.SnapshotName(args[0], snapshot.Name)),
+			snapshot.Comment)
+	}
+	t.Print()
+	return nil
+}
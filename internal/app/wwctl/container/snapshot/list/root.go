@@ -0,0 +1,20 @@
+package list
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+func GetCommand() *cobra.Command {
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "list [OPTIONS] CONTAINER",
+		Aliases:               []string{"ls"},
+		Short:                 "List the snapshots of a container",
+		Long:                  "This command lists the snapshots of CONTAINER, the oldest first.",
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  CobraRunE,
+		ValidArgsFunction:     completions.Containers,
+	}
+	return baseCmd
+}
@@ -0,0 +1,19 @@
+package restore
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func CobraRunE(cmd *cobra.Command, args []string) error {
+	if err := container.RestoreSnapshot(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := warewulfd.DaemonReload(); err != nil {
+		return fmt.Errorf("failed to reload warewulf daemon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package restore
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+func GetCommand() *cobra.Command {
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "restore [OPTIONS] CONTAINER SNAPSHOT",
+		Short:                 "Restore a snapshot of a container",
+		Long: `This command replaces the rootfs and images of CONTAINER with the ones of
+SNAPSHOT. The current state is lost unless a snapshot of it was created.`,
+		Args:              cobra.ExactArgs(2),
+		RunE:              CobraRunE,
+		ValidArgsFunction: completions.ContainerSnapshots,
+	}
+	return baseCmd
+}
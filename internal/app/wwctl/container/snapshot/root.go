@@ -0,0 +1,25 @@
+package snapshot
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/snapshot/create"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/snapshot/delete"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/snapshot/list"
+	"github.com/warewulf/warewulf/internal/app/wwctl/container/snapshot/restore"
+)
+
+func GetCommand() *cobra.Command {
+	command := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "snapshot COMMAND [OPTIONS]",
+		Short:                 "Manage snapshots of containers",
+		Long: `Snapshots keep earlier states of the rootfs of a container together with its
+images. A node or profile can boot a snapshot directly by setting its
+container to CONTAINER@SNAPSHOT.`,
+	}
+	command.AddCommand(create.GetCommand())
+	command.AddCommand(list.GetCommand())
+	command.AddCommand(restore.GetCommand())
+	command.AddCommand(delete.GetCommand())
+	return command
+}
@@ -0,0 +1,69 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Snapshot(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodeprofiles:
+  default:
+    container name: rocky9
+nodes:
+  n1:
+    container name: rocky9@good`)
+	env.WriteFile("var/lib/warewulf/chroots/rocky9/rootfs/etc/os-release", "9.4")
+
+	run := func(args ...string) (string, error) {
+		baseCmd := GetCommand()
+		baseCmd.SilenceUsage = true
+		baseCmd.SilenceErrors = true
+		buf := new(bytes.Buffer)
+		baseCmd.SetOut(buf)
+		baseCmd.SetErr(buf)
+		wwlog.SetLogWriter(buf)
+		baseCmd.SetArgs(args)
+		err := baseCmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("create", "--comment", "known good", "rocky9", "good")
+	require.NoError(t, err)
+	assert.Contains(t, out, "rocky9@good\n")
+	out, err = run("create", "rocky9")
+	require.NoError(t, err)
+	assert.Regexp(t, `rocky9@\d{8}-\d{6}\n`, out)
+	_, err = run("create", "missing")
+	assert.EqualError(t, err, "container does not exist: missing")
+
+	out, err = run("list", "rocky9")
+	require.NoError(t, err)
+	assert.Regexp(t, `rocky9@good +\S.* +cpio +known good\n`, out)
+	assert.Regexp(t, `rocky9@\d{8}-\d{6} `, out)
+
+	env.WriteFile("var/lib/warewulf/chroots/rocky9/rootfs/etc/os-release", "9.5")
+	_, err = run("restore", "rocky9", "good")
+	require.NoError(t, err)
+	assert.Equal(t, "9.4", env.ReadFile("var/lib/warewulf/chroots/rocky9/rootfs/etc/os-release"))
+
+	_, err = run("delete", "--yes", "rocky9", "good")
+	assert.EqualError(t, err, "rocky9@good is used by node n1")
+	assert.DirExists(t, env.GetPath("var/lib/warewulf/chroots/rocky9/snapshots/good"))
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n1:
+    container name: rocky9`)
+	_, err = run("delete", "--yes", "rocky9", "good")
+	require.NoError(t, err)
+	assert.NoDirExists(t, env.GetPath("var/lib/warewulf/chroots/rocky9/snapshots/good"))
+}
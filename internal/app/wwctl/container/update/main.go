@@ -0,0 +1,30 @@
+package update
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apicontainer "github.com/warewulf/warewulf/internal/pkg/api/container"
+	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		snapshot, err := apicontainer.ContainerUpdate(&wwapiv1.ContainerImportParameter{
+			Name:        args[0],
+			Build:       vars.build,
+			OciNoHttps:  vars.ociNoHttps,
+			OciUsername: vars.ociUsername,
+			OciPassword: vars.ociPassword,
+			Platform:    vars.platform,
+		})
+		if err != nil {
+			return err
+		}
+		if snapshot != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), container.SnapshotName(args[0], snapshot))
+		}
+		return nil
+	}
+}
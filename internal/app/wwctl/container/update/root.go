@@ -0,0 +1,39 @@
+package update
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/completions"
+)
+
+type variables struct {
+	build       bool
+	ociNoHttps  bool
+	ociUsername string
+	ociPassword string
+	platform    string
+}
+
+// GetCommand returns the update command
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "update [OPTIONS] CONTAINER",
+		Short:                 "Pull the OCI image of a container again into a snapshot",
+		Long: `This command checks the OCI image which CONTAINER was imported from. If
+its digest changed since CONTAINER or any of its snapshots was pulled,
+the image is pulled into a new snapshot of CONTAINER, which is printed.
+CONTAINER itself isn't changed: set nodes to the snapshot, or restore it,
+to use the update.`,
+		Example:           "wwctl container update --build rockylinux-9",
+		Args:              cobra.ExactArgs(1),
+		RunE:              CobraRunE(&vars),
+		ValidArgsFunction: completions.Containers,
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.build, "build", "b", false, "Build the snapshot after pulling")
+	baseCmd.PersistentFlags().BoolVar(&vars.ociNoHttps, "nohttps", false, "Ignore wrong TLS certificates, superseedes env WAREWULF_OCI_NOHTTPS")
+	baseCmd.PersistentFlags().StringVar(&vars.ociUsername, "username", "", "Set username for the access to the registry, superseedes env WAREWULF_OCI_USERNAME")
+	baseCmd.PersistentFlags().StringVar(&vars.ociPassword, "password", "", "Set password for the access to the registry, superseedes env WAREWULF_OCI_PASSWORD")
+	baseCmd.PersistentFlags().StringVar(&vars.platform, "platform", "", "Set other hardware platform e.g. amd64 or arm64, superseedes env WAREWULF_OCI_PLATFORM")
+	return baseCmd
+}
@@ -0,0 +1,33 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	apihistory "github.com/warewulf/warewulf/internal/pkg/api/history"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		var ids []int
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid revision: %s", arg)
+			}
+			ids = append(ids, id)
+		}
+		to := 0
+		if len(ids) > 1 {
+			to = ids[1]
+		}
+		diff, err := apihistory.HistoryDiff(vars.file, ids[0], to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), diff)
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	file string
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "diff [OPTIONS] REVISION [REVISION]",
+		Short:                 "Compare revisions",
+		Long: `This command shows the differences between two recorded revisions. If only one
+REVISION is given, it is compared against the current configuration file.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.file, "file", "f", "nodes.conf", "Configuration file (nodes.conf or warewulf.conf)")
+	return baseCmd
+}
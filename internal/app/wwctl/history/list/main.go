@@ -0,0 +1,32 @@
+package list
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	apihistory "github.com/warewulf/warewulf/internal/pkg/api/history"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		revisions, err := apihistory.HistoryList(vars.file)
+		if err != nil {
+			return err
+		}
+		t := table.New(cmd.OutOrStdout())
+		t.AddHeader("REVISION", "TIME", "USER", "COMMAND")
+		for _, rev := range revisions {
+			t.AddLine(table.Prep([]string{
+				strconv.Itoa(rev.Id),
+				rev.Time.Format(time.RFC822),
+				rev.User,
+				rev.Command,
+			})...)
+		}
+		t.Print()
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package list
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	file string
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "list [OPTIONS]",
+		Short:                 "List recorded revisions",
+		Long:                  "This command lists the recorded revisions of a configuration file.",
+		Args:                  cobra.NoArgs,
+		RunE:                  CobraRunE(&vars),
+		Aliases:               []string{"ls"},
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.file, "file", "f", "nodes.conf", "Configuration file (nodes.conf or warewulf.conf)")
+	return baseCmd
+}
@@ -0,0 +1,34 @@
+package rollback
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	apihistory "github.com/warewulf/warewulf/internal/pkg/api/history"
+	"github.com/warewulf/warewulf/internal/pkg/api/util"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		id := 0
+		if len(args) > 0 {
+			id, err = strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid revision: %s", args[0])
+			}
+		}
+		if !vars.yes {
+			label := fmt.Sprintf("Are you sure you want to undo the last change of %s", vars.file)
+			if id != 0 {
+				label = fmt.Sprintf("Are you sure you want to restore %s to revision %d", vars.file, id)
+			}
+			if !util.ConfirmationPrompt(label) {
+				return nil
+			}
+		}
+		_, err = apihistory.HistoryRollback(vars.file, id)
+		return err
+	}
+}
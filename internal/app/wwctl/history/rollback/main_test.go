@@ -0,0 +1,41 @@
+package rollback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/node/set"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_Rollback(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	inDB := `nodeprofiles: {}
+nodes:
+  n01:
+    comment: before
+`
+	env.WriteFile("etc/warewulf/nodes.conf", inDB)
+	warewulfd.SetNoDaemon()
+
+	setCmd := set.GetCommand()
+	setCmd.SetArgs([]string{"--comment=after", "--yes", "n01"})
+	assert.NoError(t, setCmd.Execute())
+	assert.YAMLEq(t, `nodeprofiles: {}
+nodes:
+  n01:
+    comment: after
+`, env.ReadFile("etc/warewulf/nodes.conf"))
+
+	baseCmd := GetCommand()
+	baseCmd.SetArgs([]string{"--yes"})
+	buf := new(bytes.Buffer)
+	baseCmd.SetOut(buf)
+	baseCmd.SetErr(buf)
+	assert.NoError(t, baseCmd.Execute())
+	assert.YAMLEq(t, inDB, env.ReadFile("etc/warewulf/nodes.conf"))
+}
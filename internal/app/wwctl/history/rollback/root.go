@@ -0,0 +1,27 @@
+package rollback
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	file string
+	yes  bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "rollback [OPTIONS] [REVISION]",
+		Short:                 "Restore a recorded revision",
+		Long: `This command restores the configuration file to the content of REVISION. If no
+REVISION is given, the last recorded change is undone. The rollback itself
+is recorded as a new revision.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.file, "file", "f", "nodes.conf", "Configuration file (nodes.conf or warewulf.conf)")
+	baseCmd.PersistentFlags().BoolVarP(&vars.yes, "yes", "y", false, "Set 'yes' to all questions asked")
+	return baseCmd
+}
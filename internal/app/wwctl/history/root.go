@@ -0,0 +1,26 @@
+package history
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/history/diff"
+	"github.com/warewulf/warewulf/internal/app/wwctl/history/list"
+	"github.com/warewulf/warewulf/internal/app/wwctl/history/rollback"
+	"github.com/warewulf/warewulf/internal/app/wwctl/history/show"
+)
+
+func GetCommand() *cobra.Command {
+	command := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "history COMMAND [OPTIONS]",
+		Short:                 "Configuration history",
+		Long: `Every change to nodes.conf and warewulf.conf made by Warewulf is recorded as
+a revision. These commands list, show and compare revisions and roll a
+configuration file back to a previous revision.`,
+	}
+	command.AddCommand(list.GetCommand())
+	command.AddCommand(show.GetCommand())
+	command.AddCommand(diff.GetCommand())
+	command.AddCommand(rollback.GetCommand())
+	return command
+}
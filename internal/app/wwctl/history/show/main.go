@@ -0,0 +1,37 @@
+package show
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apihistory "github.com/warewulf/warewulf/internal/pkg/api/history"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid revision: %s", args[0])
+		}
+		rev, err := apihistory.HistoryGet(vars.file, id)
+		if err != nil {
+			return err
+		}
+		if vars.content {
+			fmt.Fprint(cmd.OutOrStdout(), rev.Content)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Revision: %d\n", rev.Id)
+		fmt.Fprintf(cmd.OutOrStdout(), "File: %s\n", rev.File)
+		fmt.Fprintf(cmd.OutOrStdout(), "Time: %s\n", rev.Time.Format(time.RFC822))
+		fmt.Fprintf(cmd.OutOrStdout(), "User: %s\n", rev.User)
+		fmt.Fprintf(cmd.OutOrStdout(), "Command: %s\n", rev.Command)
+		if rev.Diff != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s", rev.Diff)
+		}
+		return nil
+	}
+}
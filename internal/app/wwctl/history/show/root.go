@@ -0,0 +1,26 @@
+package show
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	file    string
+	content bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "show [OPTIONS] REVISION",
+		Short:                 "Show a recorded revision",
+		Long: `This command shows who made the change recorded as REVISION, when and with which
+command, and the diff against the previous revision.`,
+		Args: cobra.ExactArgs(1),
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.file, "file", "f", "nodes.conf", "Configuration file (nodes.conf or warewulf.conf)")
+	baseCmd.PersistentFlags().BoolVarP(&vars.content, "content", "c", false, "Show the complete file content of the revision instead of the diff")
+	return baseCmd
+}
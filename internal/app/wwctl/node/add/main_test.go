@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 	"github.com/warewulf/warewulf/internal/pkg/testenv"
 	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
@@ -305,3 +306,55 @@ nodes:
 		})
 	}
 }
+
+func Test_Add_Pool(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `nodeprofiles: {}
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.0.0.2
+`)
+	conf := warewulfconf.Get()
+	conf.AddressPools = map[string]*warewulfconf.AddressPool{
+		"cluster": {Network: "10.0.0.0/24", Reserved: []string{"10.0.0.3-10.0.0.4"}},
+	}
+	defer func() { conf.AddressPools = nil }()
+
+	baseCmd := GetCommand()
+	baseCmd.SetArgs([]string{"--pool=cluster", "n[02-03]"})
+	buf := new(bytes.Buffer)
+	baseCmd.SetOut(buf)
+	baseCmd.SetErr(buf)
+	assert.NoError(t, baseCmd.Execute())
+
+	config, err := node.New()
+	assert.NoError(t, err)
+	dumpBytes, _ := config.Dump()
+	assert.YAMLEq(t, `nodeprofiles: {}
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.0.0.2
+  n02:
+    profiles:
+    - default
+    network devices:
+      default:
+        ipaddr: 10.0.0.1
+        pool: cluster
+        netmask: 255.255.255.0
+  n03:
+    profiles:
+    - default
+    network devices:
+      default:
+        ipaddr: 10.0.0.5
+        pool: cluster
+        netmask: 255.255.255.0
+`, string(dumpBytes))
+}
@@ -0,0 +1,52 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		results, err := apinode.NodeCheck()
+		if err != nil {
+			return err
+		}
+		if results == nil {
+			results = []apinode.CheckResult{}
+		}
+		if vars.showJson {
+			buf, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+		} else if vars.showYaml {
+			buf, err := yaml.Marshal(results)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(buf))
+		} else if len(results) > 0 {
+			t := table.New(cmd.OutOrStdout())
+			t.AddHeader("SEVERITY", "NODE/PROFILE", "FIELD", "MESSAGE")
+			for _, result := range results {
+				id := result.Node
+				if result.Profile != "" {
+					id = "profile:" + result.Profile
+				}
+				t.AddLine(table.Prep([]string{result.Severity, id, result.Field, result.Message})...)
+			}
+			t.Print()
+		}
+		if count := apinode.CheckErrors(results); count > 0 {
+			return fmt.Errorf("%d error(s) found in the node database", count)
+		}
+		return nil
+	}
+}
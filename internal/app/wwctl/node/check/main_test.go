@@ -0,0 +1,223 @@
+package check
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		nodes   string
+		wantErr bool
+		stdout  string
+	}{
+		{
+			name: "consistent database",
+			args: []string{"--json"},
+			nodes: `
+nodeprofiles:
+  default:
+    container name: rockylinux-9
+    system overlay:
+      - wwinit
+nodes:
+  n01:
+    profiles:
+      - default
+    network devices:
+      default:
+        device: eth0
+        hwaddr: 00:00:00:00:00:01
+        ipaddr: 192.168.0.1
+  n02:
+    profiles:
+      - default
+    network devices:
+      default:
+        device: eth0
+        hwaddr: 00:00:00:00:00:02
+        ipaddr: 192.168.0.2
+`,
+			wantErr: false,
+			stdout:  "[]\n",
+		},
+		{
+			name: "duplicate addresses",
+			args: []string{"--json"},
+			nodes: `
+nodeprofiles:
+  default: {}
+nodes:
+  n01:
+    profiles:
+      - default
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:01
+        ipaddr: 192.168.0.1
+    ipmi:
+      ipaddr: 192.168.1.1
+  n02:
+    profiles:
+      - default
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:01
+        ipaddr: 192.168.0.2
+    ipmi:
+      ipaddr: 192.168.1.1
+`,
+			wantErr: true,
+			stdout: `[
+  {
+    "severity": "error",
+    "node": "n01",
+    "field": "Ipmi.Ipaddr",
+    "message": "duplicate IP address 192.168.1.1, also used by n02:Ipmi.Ipaddr"
+  },
+  {
+    "severity": "error",
+    "node": "n01",
+    "field": "NetDevs[default].Hwaddr",
+    "message": "duplicate hardware address 00:00:00:00:00:01, also used by n02:NetDevs[default].Hwaddr"
+  },
+  {
+    "severity": "error",
+    "node": "n02",
+    "field": "Ipmi.Ipaddr",
+    "message": "duplicate IP address 192.168.1.1, also used by n01:Ipmi.Ipaddr"
+  },
+  {
+    "severity": "error",
+    "node": "n02",
+    "field": "NetDevs[default].Hwaddr",
+    "message": "duplicate hardware address 00:00:00:00:00:01, also used by n01:NetDevs[default].Hwaddr"
+  }
+]
+`,
+		},
+		{
+			name: "missing references",
+			args: []string{},
+			nodes: `
+nodeprofiles:
+  default:
+    profiles:
+      - missing
+nodes:
+  n01:
+    profiles:
+      - default
+      - other
+    container name: missing
+    primary network: eth1
+    state: broken
+    runtime overlay:
+      - missing
+    network devices:
+      eth0:
+        ipaddr: 10.0.0.1
+`,
+			wantErr: true,
+			stdout: `SEVERITY  NODE/PROFILE     FIELD           MESSAGE
+--------  ------------     -----           -------
+error     profile:default  Profiles        profile does not exist: missing
+error     n01              ContainerName   container does not exist: missing
+error     n01              PrimaryNetDev   primary network device does not exist: eth1
+error     n01              Profiles        profile does not exist: other
+error     n01              RuntimeOverlay  overlay does not exist: missing
+error     n01              State           invalid state broken, must be one of active, maintenance, quarantined, retired
+`,
+		},
+		{
+			name: "profile cycle and no network",
+			args: []string{"--yaml"},
+			nodes: `
+nodeprofiles:
+  p1:
+    profiles:
+      - p2
+  p2:
+    profiles:
+      - p1
+nodes:
+  n01:
+    profiles:
+      - p1
+`,
+			wantErr: true,
+			stdout: `- severity: error
+  profile: p1
+  field: Profiles
+  message: 'profile cycle: p1 -> p2 -> p1'
+- severity: error
+  profile: p2
+  field: Profiles
+  message: 'profile cycle: p2 -> p1 -> p2'
+- severity: warning
+  node: n01
+  field: PrimaryNetDev
+  message: no network devices configured
+`,
+		},
+		{
+			name: "address outside of network",
+			args: []string{"--json"},
+			nodes: `
+nodeprofiles: {}
+nodes:
+  n01:
+    network devices:
+      default:
+        network: cluster
+        ipaddr: 10.1.0.1
+`,
+			wantErr: true,
+			stdout: `[
+  {
+    "severity": "error",
+    "node": "n01",
+    "field": "NetDevs[default].Ipaddr",
+    "message": "address 10.1.0.1 is outside of network cluster (10.0.0.0/24)"
+  }
+]
+`,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			conf := warewulfconf.Get()
+			conf.Networks = map[string]*warewulfconf.NetworkConf{
+				"cluster": {Network: "10.0.0.0/24"},
+			}
+			defer func() { conf.Networks = nil }()
+			env.WriteFile("etc/warewulf/nodes.conf", tt.nodes)
+			env.CreateFile("var/lib/warewulf/chroots/rockylinux-9/rootfs/boot/vmlinuz-5.14.0-427.el9.x86_64")
+			env.MkdirAll("var/lib/warewulf/overlays/wwinit")
+
+			buf := new(bytes.Buffer)
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(tt.args)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, buf.String(), tt.stdout)
+		})
+	}
+}
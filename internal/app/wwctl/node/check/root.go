@@ -0,0 +1,30 @@
+package check
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	showYaml bool
+	showJson bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "check [OPTIONS]",
+		Short:                 "Check the node database for consistency",
+		Long: "This command checks the consistency of all nodes and profiles. It reports\n" +
+			"duplicate hardware and IP addresses, missing profiles, containers, overlays\n" +
+			"and kernels, nodes without a primary network device, addresses outside of\n" +
+			"their network, invalid bonds, VLANs and bridges, and profile cycles. The\n" +
+			"command exits with an error if any errors are found.",
+		RunE: CobraRunE(&vars),
+		Args: cobra.NoArgs,
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.showYaml, "yaml", "y", false, "Show yaml format")
+	baseCmd.PersistentFlags().BoolVarP(&vars.showJson, "json", "j", false, "Show json format")
+
+	return baseCmd
+}
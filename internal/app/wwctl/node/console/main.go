@@ -1,58 +1,114 @@
 package console
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/console"
 	"github.com/warewulf/warewulf/internal/pkg/hostlist"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 	"github.com/warewulf/warewulf/internal/pkg/power"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+	"golang.org/x/term"
 )
 
-func CobraRunE(cmd *cobra.Command, args []string) error {
-	var returnErr error = nil
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var returnErr error = nil
 
-	nodeDB, err := node.New()
-	if err != nil {
-		return fmt.Errorf("could not open node configuration: %s", err)
-	}
-
-	nodes, err := nodeDB.FindAllNodes()
-	if err != nil {
-		return fmt.Errorf("could not get node list: %s", err)
-	}
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %s", err)
+		}
 
-	args = hostlist.Expand(args)
+		nodes, err := nodeDB.FindAllNodes()
+		if err != nil {
+			return fmt.Errorf("could not get node list: %s", err)
+		}
 
-	if len(args) > 0 {
-		nodes = node.FilterNodeListByName(nodes, args)
-	} else {
-		//nolint:errcheck
-		cmd.Usage()
-		os.Exit(1)
-	}
+		args = hostlist.Expand(args)
 
-	if len(nodes) == 0 {
-		return fmt.Errorf("no nodes found")
-	}
+		if len(args) > 0 {
+			nodes, err = node.FilterNodeListByName(nodes, args)
+			if err != nil {
+				return err
+			}
+		} else {
+			//nolint:errcheck
+			cmd.Usage()
+			os.Exit(1)
+		}
 
-	for _, node := range nodes {
+		if len(nodes) == 0 {
+			return fmt.Errorf("no nodes found")
+		}
 
-		if node.Ipmi.Ipaddr.IsUnspecified() {
-			wwlog.Error("%s: No IPMI IP address", node.Id())
-			continue
+		consoleDir := warewulfconf.Get().Paths.ConsoleDir()
+		if vars.Log {
+			var since time.Time
+			if vars.Since > 0 {
+				since = time.Now().Add(-vars.Since)
+			}
+			for _, n := range nodes {
+				if len(nodes) > 1 {
+					fmt.Fprintf(cmd.OutOrStdout(), "==> %s <==\n", n.Id())
+				}
+				if err := console.ReadLog(consoleDir, n.Id(), since, cmd.OutOrStdout()); err != nil {
+					wwlog.Error("%s: could not read console log: %s", n.Id(), err)
+					returnErr = err
+				}
+			}
+			return returnErr
 		}
-		ipmiCmd := power.IPMI{IpmiConf: *node.Ipmi}
-		err := ipmiCmd.Console()
-		if err != nil {
-			wwlog.Error("%s: Console problem", node.Id())
-			returnErr = err
-			continue
+
+		for _, node := range nodes {
+
+			if node.Ipmi == nil || node.Ipmi.Ipaddr == nil || node.Ipmi.Ipaddr.IsUnspecified() {
+				wwlog.Error("%s: No IPMI IP address", node.Id())
+				continue
+			}
+			err = attach(consoleDir, node.Id())
+			if err == nil {
+				continue
+			} else if !errors.Is(err, console.ErrNoServer) && !errors.Is(err, console.ErrNotCaptured) {
+				wwlog.Error("%s: Console problem: %s", node.Id(), err)
+				returnErr = err
+				continue
+			}
+			wwlog.Debug("%s: %s, connecting directly", node.Id(), err)
+			bmc, err := power.New(*node.Ipmi, false)
+			if err != nil {
+				wwlog.Error("%s: %s", node.Id(), err)
+				returnErr = err
+				continue
+			}
+			err = bmc.Console()
+			if err != nil {
+				wwlog.Error("%s: Console problem: %s", node.Id(), err)
+				returnErr = err
+				continue
+			}
+
 		}
 
+		return returnErr
 	}
+}
 
-	return returnErr
+// attach attaches the terminal to the console of the node captured by
+// the console server
+func attach(consoleDir, node string) error {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+	}
+	return console.Attach(consoleDir, node, os.Stdin, os.Stdout)
 }
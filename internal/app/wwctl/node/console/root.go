@@ -1,21 +1,31 @@
 package console
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
-var (
-	powerCmd = &cobra.Command{
+type variables struct {
+	Log   bool
+	Since time.Duration
+}
+
+// GetRootCommand returns the root cobra.Command for the application.
+func GetCommand() *cobra.Command {
+	vars := variables{}
+
+	powerCmd := &cobra.Command{
 		DisableFlagsInUseLine: true,
 		Use:                   "console [OPTIONS] NODENAME",
 		Short:                 "Connect to IPMI console",
-		Long:                  "Start a new IPMI console for NODENAME.",
-		Args:                  cobra.MinimumNArgs(1),
-		RunE:                  CobraRunE,
+		Long: "Start a new IPMI console for NODENAME. If the console is captured by\n" +
+			"\"wwctl console-server\", attach to the running session instead; press\n" +
+			"Ctrl-] to detach. With --log, show the captured console output.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: CobraRunE(&vars),
 	}
-)
-
-// GetRootCommand returns the root cobra.Command for the application.
-func GetCommand() *cobra.Command {
+	powerCmd.PersistentFlags().BoolVar(&vars.Log, "log", false, "show the console output captured by the console server")
+	powerCmd.PersistentFlags().DurationVar(&vars.Since, "since", 0, "with --log, only show the output of this time span, e.g. 1h")
 	return powerCmd
 }
@@ -0,0 +1,66 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_ConsoleLog(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n01:
+    ipmi:
+      ipaddr: 10.10.10.10
+  n02:
+    ipmi:
+      ipaddr: 10.10.10.11
+  n03: {}`)
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	env.WriteFile("var/local/warewulf/console/n01.log.1", old+" Booting\n")
+	env.WriteFile("var/local/warewulf/console/n01.log", recent+" n01 login: \n")
+
+	tests := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"log": {
+			args:     []string{"--log", "n01"},
+			expected: old + " Booting\n" + recent + " n01 login: \n",
+		},
+		"since": {
+			args:     []string{"--log", "--since", "1h", "n01"},
+			expected: recent + " n01 login: \n",
+		},
+		"several nodes": {
+			args:     []string{"--log", "--since", "1h", "n01", "n02"},
+			expected: "==> n01 <==\n" + recent + " n01 login: \n==> n02 <==\n",
+		},
+		"no address": {
+			args:     []string{"n03"},
+			expected: "ERROR  : n03: No IPMI IP address\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(tt.args)
+			buf := new(bytes.Buffer)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			wwlog.SetLogWriter(buf)
+			err := baseCmd.Execute()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -37,7 +37,10 @@ func CobraRunE(cmd *cobra.Command, args []string) error {
 	filterList := wwapiv1.NodeList{
 		Output: args,
 	}
-	nodeListMsg := apinode.FilteredNodes(&filterList)
+	nodeListMsg, err := apinode.FilteredNodes(&filterList)
+	if err != nil {
+		return err
+	}
 	nodeMap := make(map[string]*node.Node)
 	// got proper yaml back
 	_ = yaml.Unmarshal([]byte(nodeListMsg.NodeConfMapYaml), nodeMap)
@@ -110,16 +113,11 @@ func CobraRunE(cmd *cobra.Command, args []string) error {
 			}
 			yes := apiutil.ConfirmationPrompt(fmt.Sprintf("Are you sure you want to modify %d nodes", len(modifiedNodeMap)))
 			if yes {
-				err = apinode.NodeDelete(&wwapiv1.NodeDeleteParameter{NodeNames: nodeList, Force: true})
-				if err != nil {
-					wwlog.Verbose("Problem deleting nodes before modification %s", err)
-				}
 				buffer, _ = yaml.Marshal(modifiedNodeMap)
-				newHash := apinode.Hash()
-				err = apinode.NodeAddFromYaml(&wwapiv1.NodeYaml{
+				err = apinode.NodeReplaceFromYaml(&wwapiv1.NodeYaml{
 					NodeConfMapYaml: string(buffer),
-					Hash:            newHash.Hash,
-				})
+					Hash:            nodeListMsg.Hash,
+				}, nodeList, Force)
 				if err != nil {
 					return fmt.Errorf("got following problem when writing back yaml: %s", err)
 				}
@@ -28,10 +28,12 @@ var (
 		},
 	}
 	NoHeader bool
+	Force    bool
 )
 
 func init() {
 	baseCmd.PersistentFlags().BoolVar(&NoHeader, "noheader", false, "Do not print header")
+	baseCmd.PersistentFlags().BoolVarP(&Force, "force", "f", false, "Write the nodes even if the node database was changed while editing")
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
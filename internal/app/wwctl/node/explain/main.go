@@ -0,0 +1,72 @@
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		field := ""
+		if len(args) > 1 {
+			field = args[1]
+		}
+		explanation, err := apinode.NodeExplain(args[0], field)
+		if err != nil {
+			return err
+		}
+		if vars.showJson {
+			buf, err := json.MarshalIndent(explanation, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+			return nil
+		} else if vars.showYaml {
+			buf, err := yaml.Marshal(explanation)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(buf))
+			return nil
+		}
+
+		profiles := strings.Join(explanation.Profiles, " -> ")
+		if len(explanation.Excluded) > 0 {
+			profiles += fmt.Sprintf(" (excluded: %s)", strings.Join(explanation.Excluded, ", "))
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Node: %s\n", explanation.Node)
+		fmt.Fprintf(cmd.OutOrStdout(), "Profiles: %s\n\n", profiles)
+
+		t := table.New(cmd.OutOrStdout())
+		t.AddHeader("FIELD", "LEVEL", "VALUE", "RESULT")
+		for _, explained := range explanation.Fields {
+			name := explained.Field
+			for _, level := range explained.Levels {
+				result := "overridden"
+				if explained.List {
+					result = "appended"
+				} else if level.Used {
+					result = "used"
+				}
+				if len(level.Negated) > 0 {
+					result += fmt.Sprintf(", negates %s", strings.Join(level.Negated, ","))
+				}
+				t.AddLine(table.Prep([]string{name, level.Source, level.Value, result})...)
+				name = ""
+			}
+			if explained.List || len(explained.Levels) == 0 {
+				t.AddLine(table.Prep([]string{name, "(merged)", explained.Value, "used"})...)
+			}
+		}
+		t.Print()
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package explain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_Explain(t *testing.T) {
+	nodesConf := `
+nodeprofiles:
+  default:
+    kernel:
+      args: quiet crashkernel=no
+    runtime overlay:
+    - generic
+  gpu:
+    kernel:
+      args: quiet nouveau.modeset=0
+    runtime overlay:
+    - nvidia
+    - ~generic
+nodes:
+  n01:
+    profiles:
+    - default
+    - gpu
+    - ~legacy
+    kernel:
+      args: debug
+`
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		stdout  string
+	}{
+		{
+			name: "kernel fields",
+			args: []string{"n01", "kernel"},
+			stdout: `Node: n01
+Profiles: default -> gpu (excluded: legacy)
+
+FIELD        LEVEL    VALUE                    RESULT
+-----        -----    -----                    ------
+Kernel.Args  default  quiet crashkernel=no     overridden
+--           gpu      quiet nouveau.modeset=0  overridden
+--           n01      debug                    used
+`,
+		},
+		{
+			name: "list field",
+			args: []string{"n01", "RuntimeOverlay"},
+			stdout: `Node: n01
+Profiles: default -> gpu (excluded: legacy)
+
+FIELD           LEVEL     VALUE                    RESULT
+-----           -----     -----                    ------
+RuntimeOverlay  default   generic                  appended
+--              gpu       nvidia,~generic          appended, negates generic
+--              (merged)  generic,nvidia,~generic  used
+`,
+		},
+		{
+			name: "json",
+			args: []string{"--json", "n01", "Kernel.Args"},
+			stdout: `{
+  "node": "n01",
+  "profiles": [
+    "default",
+    "gpu"
+  ],
+  "excluded": [
+    "legacy"
+  ],
+  "fields": [
+    {
+      "field": "Kernel.Args",
+      "value": "debug",
+      "source": "SUPERSEDED",
+      "levels": [
+        {
+          "source": "default",
+          "value": "quiet crashkernel=no",
+          "used": false
+        },
+        {
+          "source": "gpu",
+          "value": "quiet nouveau.modeset=0",
+          "used": false
+        },
+        {
+          "source": "n01",
+          "value": "debug",
+          "used": true
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "unset field",
+			args:    []string{"n01", "ContainerName"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown node",
+			args:    []string{"n02"},
+			wantErr: true,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/nodes.conf", nodesConf)
+
+			buf := new(bytes.Buffer)
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(tt.args)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.stdout, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package explain
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+type variables struct {
+	showYaml bool
+	showJson bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "explain [OPTIONS] NODENAME [FIELD]",
+		Short:                 "Explain how the fields of a node are resolved",
+		Long: "This command shows the resolution chain of the fields of a node: every\n" +
+			"profile in the order it is merged, the value of the field at each level,\n" +
+			"negations with '~' and which level provided the resulting value. If FIELD\n" +
+			"is given (e.g. Kernel.Args, Kernel or NetDevs[default]), only that field\n" +
+			"and the fields nested below it are shown.",
+		RunE: CobraRunE(&vars),
+		Args: cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			nodeDB, _ := node.New()
+			nodes, _ := nodeDB.FindAllNodes()
+			var node_names []string
+			for _, node := range nodes {
+				node_names = append(node_names, node.Id())
+			}
+			return node_names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.showYaml, "yaml", "y", false, "Show yaml format")
+	baseCmd.PersistentFlags().BoolVarP(&vars.showJson, "json", "j", false, "Show json format")
+
+	return baseCmd
+}
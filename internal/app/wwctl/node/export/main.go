@@ -1,25 +1,67 @@
 package export
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
 	"github.com/spf13/cobra"
 	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/node"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 )
 
-func CobraRunE(cmd *cobra.Command, args []string) error {
-	if len(args) == 0 {
-		args = append(args, ".*")
-	}
-	filterList := wwapiv1.NodeList{
-		Output: args,
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if !vars.exportCSV && !vars.exportJSON {
+			if len(args) == 0 {
+				args = append(args, ".*")
+			}
+			filterList := wwapiv1.NodeList{
+				Output: args,
+			}
+			nodeListMsg, err := apinode.FilteredNodes(&filterList)
+			if err != nil {
+				return err
+			}
+			wwlog.Info(nodeListMsg.NodeConfMapYaml)
+			return nil
+		}
+
+		nodes, err := apinode.NodeExport(args)
+		if err != nil {
+			return err
+		}
+		if vars.exportJSON {
+			records := make(map[string]node.NodeRecord)
+			for _, n := range nodes {
+				records[n.Id()] = n.Record()
+			}
+			buffer, err := json.MarshalIndent(records, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(buffer))
+			return nil
+		}
+
+		fields := node.RecordFields(nodes...)
+		writer := csv.NewWriter(cmd.OutOrStdout())
+		if err := writer.Write(append([]string{"node"}, fields...)); err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			record := n.Record()
+			line := []string{n.Id()}
+			for _, field := range fields {
+				line = append(line, record[field])
+			}
+			if err := writer.Write(line); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
 	}
-	nodeListMsg := apinode.FilteredNodes(&filterList)
-	/*
-		nodeMap := make(map[string]*node.NodeConf)
-		// got proper yaml back
-		_ = yaml.Unmarshal([]byte(nodeListMsg.NodeConfMapYaml), nodeMap)
-	*/
-	wwlog.Info(nodeListMsg.NodeConfMapYaml)
-	return nil
 }
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_Export(t *testing.T) {
+	nodesConf := `
+nodeprofiles:
+  default:
+    comment: profile comment
+nodes:
+  n01:
+    profiles:
+    - default
+    comment: first node
+    ipmi:
+      ipaddr: 192.168.1.1
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:01
+        ipaddr: 10.0.0.1
+    tags:
+      rack: r1
+  n02:
+    profiles:
+    - default
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:02
+    disks:
+      /dev/vda:
+        wipe_table: true
+`
+	tests := []struct {
+		name   string
+		args   []string
+		stdout string
+	}{
+		{
+			name: "csv",
+			args: []string{"--csv"},
+			stdout: `node,Profiles,Comment,Ipmi.Ipaddr,NetDevs[default].Hwaddr,NetDevs[default].Ipaddr,Tags[rack],Disks[/dev/vda].WipeTable
+n01,default,first node,192.168.1.1,00:00:00:00:00:01,10.0.0.1,r1,
+n02,default,,,00:00:00:00:00:02,,,true
+`,
+		},
+		{
+			name: "json with pattern",
+			args: []string{"--json", "n02"},
+			stdout: `{
+  "n02": {
+    "Disks[/dev/vda].WipeTable": "true",
+    "NetDevs[default].Hwaddr": "00:00:00:00:00:02",
+    "Profiles": "default"
+  }
+}
+`,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/nodes.conf", nodesConf)
+
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(tt.args)
+			stdout := bytes.NewBufferString("")
+			baseCmd.SetOut(stdout)
+			baseCmd.SetErr(bytes.NewBufferString(""))
+			assert.NoError(t, baseCmd.Execute())
+			assert.Equal(t, tt.stdout, stdout.String())
+		})
+	}
+}
@@ -5,13 +5,24 @@ import (
 	"github.com/warewulf/warewulf/internal/pkg/node"
 )
 
-var (
-	baseCmd = &cobra.Command{
+type variables struct {
+	exportCSV  bool
+	exportJSON bool
+}
+
+// GetRootCommand returns the root cobra.Command for the application.
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
 		DisableFlagsInUseLine: true,
-		Use:                   "export  NODENAME",
-		Short:                 "Export nodes as yaml to stdout",
-		Long:                  "This command exports the given nodes as yaml to stdout.",
-		RunE:                  CobraRunE,
+		Use:                   "export [OPTIONS] [PATTERN ...]",
+		Short:                 "Export nodes as yaml, csv or json to stdout",
+		Long: "This command exports the given nodes as yaml to stdout. With --csv or --json\n" +
+			"the nodes are exported in a flat representation, with a column or key for\n" +
+			"every field as listed by \"wwctl node list -a\", which can be edited and\n" +
+			"imported again with \"wwctl node import\". Only the values set on the nodes\n" +
+			"are exported, not the values inherited from profiles.",
+		RunE: CobraRunE(&vars),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
 				return nil, cobra.ShellCompDirectiveNoFileComp
@@ -26,10 +37,8 @@ var (
 			return node_names, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	NoHeader bool
-)
-
-// GetRootCommand returns the root cobra.Command for the application.
-func GetCommand() *cobra.Command {
+	baseCmd.PersistentFlags().BoolVarP(&vars.exportCSV, "csv", "c", false, "Export as csv")
+	baseCmd.PersistentFlags().BoolVarP(&vars.exportJSON, "json", "j", false, "Export as json")
+	baseCmd.MarkFlagsMutuallyExclusive("csv", "json")
 	return baseCmd
 }
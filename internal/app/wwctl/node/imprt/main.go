@@ -3,84 +3,101 @@ package imprt
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
-	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
 	apiutil "github.com/warewulf/warewulf/internal/pkg/api/util"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 	"gopkg.in/yaml.v3"
 )
 
-func CobraRunE(cmd *cobra.Command, args []string) error {
-	file, err := os.Open(args[0])
-	if err != nil {
-		return fmt.Errorf("could not open file: %s", err)
-	}
-	defer file.Close()
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		buffer, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("could not read file: %s", err)
+		}
 
-	importMap := make(map[string]*node.Node)
-	buffer, err := io.ReadAll(file)
-	if err != nil {
-		return fmt.Errorf("could not read: %s", err)
-	}
-	if !ImportCVS {
-		err = yaml.Unmarshal(buffer, importMap)
-		if err == nil {
-			yes := apiutil.ConfirmationPrompt(fmt.Sprintf("Are you sure you want to modify %d nodes", len(importMap)))
-			if yes {
-				err = apinode.NodeAddFromYaml(&wwapiv1.NodeYaml{NodeConfMapYaml: string(buffer)})
-				if err != nil {
-					return fmt.Errorf("got following problem when writing back yaml: %s", err)
-				}
-			}
-		} else {
-			return fmt.Errorf("could not parse import file: %s", err)
+		var records map[string]node.NodeRecord
+		switch {
+		case vars.importCSV:
+			records, err = readCSV(buffer)
+		case vars.importJSON:
+			err = json.Unmarshal(buffer, &records)
+		default:
+			records, err = readYAML(buffer)
 		}
-	} else {
-		// reading from buffer is a bit overshot
-		csvReader := csv.NewReader(bytes.NewReader(buffer))
-		records, err := csvReader.ReadAll()
 		if err != nil {
 			return fmt.Errorf("could not parse %s: %s", args[0], err)
 		}
-		if len(records) < 1 || len(records[0]) < 1 {
+		if len(records) == 0 {
 			return fmt.Errorf("did not find any data in %s", args[0])
 		}
-		if !(records[0][0] == "node" || records[0][0] == "nodename") {
-			Usage()
-			os.Exit(1)
+
+		plan, err := apinode.NodeImport(records, vars.mode)
+		if err != nil {
+			return err
 		}
-		argsLen := len(records[0])
-		for i, line := range records[1:] {
-			if len(line) != argsLen {
-				return fmt.Errorf("wrong number of fields in lube %d", i+1)
-			}
-			for j := range line {
-				if j == 0 {
-					continue
-				}
-				if importMap[line[0]] == nil {
-					importMap[line[0]] = new(node.Node)
-				}
-			}
+		if plan.Empty() {
+			fmt.Fprintln(cmd.OutOrStdout(), "No changes")
+			return nil
 		}
-		yes := apiutil.ConfirmationPrompt(fmt.Sprintf("Are you sure you want to import %d nodes", len(importMap)))
-		if yes {
-			// create second buffer an marshall nodeMap to it
-			buffer, err = yaml.Marshal(importMap)
-			if err != nil {
-				return fmt.Errorf("got following problem when creating yaml: %s", err)
-			}
-			err = apinode.NodeAddFromYaml(&wwapiv1.NodeYaml{NodeConfMapYaml: string(buffer)})
-			if err != nil {
-				return fmt.Errorf("got following problem when writing back yaml: %s", err)
+		fmt.Fprint(cmd.OutOrStdout(), plan.Diff())
+		if !vars.yes {
+			if !apiutil.ConfirmationPrompt(fmt.Sprintf("Are you sure you want to import %d nodes", len(records))) {
+				return nil
 			}
 		}
+		return plan.Apply()
+	}
+}
+
+// readYAML reads nodes in the format of nodes.conf
+func readYAML(buffer []byte) (records map[string]node.NodeRecord, err error) {
+	importMap := make(map[string]*node.Node)
+	if err = yaml.Unmarshal(buffer, importMap); err != nil {
+		return nil, err
+	}
+	records = make(map[string]node.NodeRecord)
+	for id, n := range importMap {
+		if n == nil {
+			n = new(node.Node)
+		}
+		records[id] = n.Record()
 	}
+	return records, nil
+}
 
-	return nil
+// readCSV reads a csv file with the node name in the first column and
+// field names in the header
+func readCSV(buffer []byte) (records map[string]node.NodeRecord, err error) {
+	lines, err := csv.NewReader(bytes.NewReader(buffer)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < 1 || len(lines[0]) < 1 {
+		return nil, nil
+	}
+	header := lines[0]
+	if !(header[0] == "node" || header[0] == "nodename") {
+		return nil, fmt.Errorf("the first column must be 'node'\n\n%s", csvUsage)
+	}
+	records = make(map[string]node.NodeRecord)
+	for _, line := range lines[1:] {
+		if line[0] == "" {
+			continue
+		}
+		if _, ok := records[line[0]]; ok {
+			return nil, fmt.Errorf("node %s is listed more than once", line[0])
+		}
+		record := make(node.NodeRecord)
+		for i, value := range line[1:] {
+			record[header[i+1]] = value
+		}
+		records[line[0]] = record
+	}
+	return records, nil
 }
@@ -0,0 +1,156 @@
+package imprt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_Import(t *testing.T) {
+	nodesConf := `
+nodes:
+  n01:
+    comment: first node
+    kernel:
+      args: quiet
+    tags:
+      rack: r1
+`
+	tests := []struct {
+		name    string
+		args    []string
+		file    string
+		wantErr bool
+		stdout  string
+		records map[string]node.NodeRecord
+	}{
+		{
+			name: "csv update",
+			args: []string{"--csv", "--mode", "update"},
+			file: `node,Comment,NetDevs[default].Ipaddr,Tags[rack],Kernel.Args
+n01,,10.0.0.1,UNDEF,
+n02,second node,10.0.0.2,r2,debug
+`,
+			stdout: `+ n02
+- n01 Tags[rack]: r1
++ n01 NetDevs[default].Ipaddr: 10.0.0.1
++ n02 Comment: second node
++ n02 Kernel.Args: debug
++ n02 NetDevs[default].Ipaddr: 10.0.0.2
++ n02 Tags[rack]: r2
+`,
+			records: map[string]node.NodeRecord{
+				"n01": {"Comment": "first node", "Kernel.Args": "quiet", "NetDevs[default].Ipaddr": "10.0.0.1"},
+				"n02": {"Comment": "second node", "Kernel.Args": "debug", "NetDevs[default].Ipaddr": "10.0.0.2", "Tags[rack]": "r2"},
+			},
+		},
+		{
+			name: "csv replace",
+			args: []string{"--csv"},
+			file: `node,Comment
+n01,replaced
+`,
+			stdout: `~ n01 Comment: first node -> replaced
+- n01 Kernel.Args: quiet
+- n01 Tags[rack]: r1
+`,
+			records: map[string]node.NodeRecord{
+				"n01": {"Comment": "replaced"},
+			},
+		},
+		{
+			name: "json create",
+			args: []string{"--json", "--mode", "create"},
+			file: `{"n02": {"Ipmi.Ipaddr": "192.168.1.2", "Disks[/dev/vda].WipeTable": "true"}}`,
+			stdout: `+ n02
++ n02 Ipmi.Ipaddr: 192.168.1.2
++ n02 Disks[/dev/vda].WipeTable: true
+`,
+			records: map[string]node.NodeRecord{
+				"n01": {"Comment": "first node", "Kernel.Args": "quiet", "Tags[rack]": "r1"},
+				"n02": {"Ipmi.Ipaddr": "192.168.1.2", "Disks[/dev/vda].WipeTable": "true"},
+			},
+		},
+		{
+			name: "yaml",
+			args: []string{"--mode", "update"},
+			file: `
+n01:
+  comment: from yaml
+`,
+			stdout: `~ n01 Comment: first node -> from yaml
+`,
+			records: map[string]node.NodeRecord{
+				"n01": {"Comment": "from yaml", "Kernel.Args": "quiet", "Tags[rack]": "r1"},
+			},
+		},
+		{
+			name:   "no changes",
+			args:   []string{"--csv", "--mode", "update"},
+			file:   "node,Comment\nn01,first node\n",
+			stdout: "No changes\n",
+		},
+		{
+			name:    "create existing node",
+			args:    []string{"--csv", "--mode", "create"},
+			file:    "node,Comment\nn01,again\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			args:    []string{"--csv"},
+			file:    "node,Unknown\nn01,value\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid address",
+			args:    []string{"--csv"},
+			file:    "node,NetDevs[default].Ipaddr\nn01,10.0.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			args:    []string{"--csv", "--mode", "merge"},
+			file:    "node,Comment\nn01,value\n",
+			wantErr: true,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/nodes.conf", nodesConf)
+			env.WriteFile("import", tt.file)
+
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(append(tt.args, "--yes", env.GetPath("import")))
+			stdout := bytes.NewBufferString("")
+			baseCmd.SetOut(stdout)
+			baseCmd.SetErr(bytes.NewBufferString(""))
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.stdout, stdout.String())
+
+			if tt.records != nil {
+				nodeDB, err := node.New()
+				assert.NoError(t, err)
+				assert.Len(t, nodeDB.Nodes, len(tt.records))
+				for id, record := range tt.records {
+					n, err := nodeDB.GetNodeOnly(id)
+					assert.NoError(t, err)
+					assert.Equal(t, record, n.Record())
+				}
+			}
+		})
+	}
+}
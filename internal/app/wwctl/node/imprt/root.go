@@ -2,34 +2,58 @@ package imprt
 
 import (
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/spf13/cobra"
+	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
 )
 
-var (
-	baseCmd = &cobra.Command{
-		DisableFlagsInUseLine: true,
-		Use:                   "import [OPTIONS] NODENAME",
-		Short:                 "Import node(s) from yaml file",
-		Long:                  "This command imports all the nodes defined in a file. It will overwrite nodes with same name.",
-		RunE:                  CobraRunE,
-		Args:                  cobra.MinimumNArgs(1),
-		Aliases:               []string{"import"},
-	}
-	ImportCVS bool
-)
-
-func init() {
-	baseCmd.PersistentFlags().BoolVarP(&ImportCVS, "cvs", "c", false, "Import CVS file")
+type variables struct {
+	importCSV  bool
+	importJSON bool
+	mode       string
+	yes        bool
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
 func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "import [OPTIONS] FILE",
+		Short:                 "Import node(s) from a yaml, csv or json file",
+		Long: "This command imports all the nodes defined in a file. The file is read as\n" +
+			"yaml, as written by \"wwctl node export\", or with --csv or --json as the\n" +
+			"flat representation written by \"wwctl node export --csv|--json\".\n\n" +
+			"In the 'create' mode all nodes must be new, in the 'update' mode the given\n" +
+			"fields are set on existing nodes and in the 'replace' mode existing nodes\n" +
+			"are replaced. The changes are shown before they are written.\n\n" +
+			csvUsage,
+		RunE: CobraRunE(&vars),
+		Args: cobra.ExactArgs(1),
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.importCSV, "csv", "c", false, "Import csv file")
+	baseCmd.PersistentFlags().BoolVar(&vars.importCSV, "cvs", false, "Import csv file")
+	_ = baseCmd.PersistentFlags().MarkDeprecated("cvs", "use --csv instead")
+	baseCmd.PersistentFlags().BoolVarP(&vars.importJSON, "json", "j", false, "Import json file")
+	baseCmd.PersistentFlags().StringVarP(&vars.mode, "mode", "m", apinode.ImportReplace,
+		fmt.Sprintf("Import mode (%s)", strings.Join(apinode.ImportModes, ", ")))
+	baseCmd.PersistentFlags().BoolVarP(&vars.yes, "yes", "y", false, "Set 'yes' to all questions asked")
+	if err := baseCmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return apinode.ImportModes, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		log.Println(err)
+	}
 	return baseCmd
 }
 
-func Usage() {
-	fmt.Println(`The csv file must be structured in following way:
-node,option1,option2,net.netname1.netopt
-node01,value1,value2,net.netname1,netvalue`)
-}
+const csvUsage = `The first column of a csv file is the node name, the other columns are
+fields as listed by "wwctl node list -a", e.g.:
+
+  node,Comment,Kernel.Args,NetDevs[default].Hwaddr,NetDevs[default].Ipaddr,Tags[rack]
+  n01,compute,quiet,00:00:00:00:00:01,10.0.0.1,r1
+
+In the 'update' mode, empty cells leave a field unchanged and UNDEF removes
+it. In the 'create' and 'replace' modes, nodes only get the fields of the
+cells which are not empty.`
@@ -440,6 +440,27 @@ nodes:
   n01:
     profiles:
     - default
+`,
+		},
+		{
+			name: "single node list yaml output with secret",
+			args: []string{"-y"},
+			stdout: `
+- profiles:
+    - default
+  kernel: {}
+  ipmi:
+    password: '********'
+`,
+			inDb: `
+nodeprofiles:
+  default: {}
+nodes:
+  n01:
+    profiles:
+    - default
+    ipmi:
+      password: wwsecret:v1:0123abcd:AAAA
 `,
 		},
 		{
@@ -450,6 +471,9 @@ nodes:
   {
     "Discoverable": "",
     "AssetKey": "",
+    "State": "",
+    "StateReason": "",
+    "StateChanged": "",
     "Profiles": [
       "default"
     ],
@@ -464,6 +488,7 @@ nodes:
       "UserName": "",
       "Password": "",
       "Ipaddr": "",
+      "Pool": "",
       "Gateway": "",
       "Netmask": "",
       "Port": "",
@@ -471,12 +496,16 @@ nodes:
       "EscapeChar": "",
       "Write": "",
       "Template": "",
+      "Backend": "",
+      "Insecure": "",
+      "RedfishPort": "",
       "Tags": {}
     },
     "Init": "",
     "Root": "",
     "NetDevs": {},
     "Tags": {},
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
@@ -500,6 +529,9 @@ nodes:
   {
     "Discoverable": "",
     "AssetKey": "",
+    "State": "",
+    "StateReason": "",
+    "StateChanged": "",
     "Profiles": [
       "default"
     ],
@@ -514,6 +546,7 @@ nodes:
       "UserName": "",
       "Password": "",
       "Ipaddr": "",
+      "Pool": "",
       "Gateway": "",
       "Netmask": "",
       "Port": "",
@@ -521,12 +554,16 @@ nodes:
       "EscapeChar": "",
       "Write": "",
       "Template": "",
+      "Backend": "",
+      "Insecure": "",
+      "RedfishPort": "",
       "Tags": {}
     },
     "Init": "",
     "Root": "",
     "NetDevs": {},
     "Tags": {},
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
@@ -534,6 +571,9 @@ nodes:
   {
     "Discoverable": "",
     "AssetKey": "",
+    "State": "",
+    "StateReason": "",
+    "StateChanged": "",
     "Profiles": [
       "default"
     ],
@@ -548,6 +588,7 @@ nodes:
       "UserName": "",
       "Password": "",
       "Ipaddr": "",
+      "Pool": "",
       "Gateway": "",
       "Netmask": "",
       "Port": "",
@@ -555,12 +596,16 @@ nodes:
       "EscapeChar": "",
       "Write": "",
       "Template": "",
+      "Backend": "",
+      "Insecure": "",
+      "RedfishPort": "",
       "Tags": {}
     },
     "Init": "",
     "Root": "",
     "NetDevs": {},
     "Tags": {},
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
@@ -3,14 +3,17 @@ package node
 import (
 	"github.com/spf13/cobra"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/add"
+	"github.com/warewulf/warewulf/internal/app/wwctl/node/check"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/console"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/delete"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/edit"
+	"github.com/warewulf/warewulf/internal/app/wwctl/node/explain"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/export"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/imprt"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/list"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/sensors"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node/set"
+	"github.com/warewulf/warewulf/internal/app/wwctl/node/state"
 	nodestatus "github.com/warewulf/warewulf/internal/app/wwctl/node/status"
 )
 
@@ -37,6 +40,9 @@ func init() {
 	baseCmd.AddCommand(edit.GetCommand())
 	baseCmd.AddCommand(imprt.GetCommand())
 	baseCmd.AddCommand(export.GetCommand())
+	baseCmd.AddCommand(check.GetCommand())
+	baseCmd.AddCommand(explain.GetCommand())
+	baseCmd.AddCommand(state.GetCommand())
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -1,15 +1,20 @@
 package sensors
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"regexp"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/warewulf/warewulf/internal/pkg/batch"
+	"github.com/warewulf/warewulf/internal/app/wwctl/table"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/hostlist"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 	"github.com/warewulf/warewulf/internal/pkg/power"
+	"github.com/warewulf/warewulf/internal/pkg/sensor"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 )
 
@@ -17,6 +22,14 @@ func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err err
 	return func(cmd *cobra.Command, args []string) error {
 		var returnErr error = nil
 
+		var filter *regexp.Regexp
+		if vars.Sensor != "" {
+			var err error
+			if filter, err = regexp.Compile(vars.Sensor); err != nil {
+				return fmt.Errorf("invalid sensor expression: %w", err)
+			}
+		}
+
 		nodeDB, err := node.New()
 		if err != nil {
 			return fmt.Errorf("could not open node configuration: %s", err)
@@ -30,7 +43,10 @@ func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err err
 		args = hostlist.Expand(args)
 
 		if len(args) > 0 {
-			nodes = node.FilterNodeListByName(nodes, args)
+			nodes, err = node.FilterNodeListByName(nodes, args)
+			if err != nil {
+				return err
+			}
 		} else {
 			//nolint:errcheck
 			cmd.Usage()
@@ -42,52 +58,126 @@ func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err err
 			os.Exit(1)
 		}
 
-		batchpool := batch.New(50)
-		jobcount := len(nodes)
-		results := make(chan power.IPMI, jobcount)
-
-		for _, node := range nodes {
-			if node.Ipmi.Ipaddr.IsUnspecified() {
-				wwlog.Error("%s: No IPMI IP address", node.Id())
-				continue
+		conf := warewulfconf.Get()
+		var readings []sensor.Reading
+		if vars.Since > 0 {
+			since := time.Now().Add(-vars.Since)
+			for _, n := range nodes {
+				history, err := sensor.History(conf.Paths.SensorsDir(), n.Id(), since)
+				if err != nil {
+					return fmt.Errorf("could not read sensor history of %s: %w", n.Id(), err)
+				}
+				readings = append(readings, history...)
+			}
+		} else {
+			operation := power.Operation{Call: power.BMC.SDRList, State: power.StateOK}
+			if vars.Full || vars.AlarmsOnly {
+				operation.Call = power.BMC.SensorList
 			}
-			ipmiCmd := power.IPMI{
-				IpmiConf: *node.Ipmi,
+			results := power.Run(nodes, operation, power.Options{
+				Fanout:   vars.Fanout,
+				Timeout:  vars.Timeout,
+				Retries:  vars.Retries,
 				ShowOnly: vars.Showcmd,
+			})
+			returnErr = power.Err(results)
+			now := time.Now().UTC().Truncate(time.Second)
+			for _, result := range results {
+				if result.Failed() {
+					wwlog.Error("%s: %s", result.Node, result.Reason)
+					continue
+				}
+				if vars.Showcmd {
+					wwlog.Info("%s: %s", result.Address, result.Output)
+					continue
+				}
+				parsed := sensor.Parse(result.Node, now, result.Output)
+				if len(parsed) == 0 {
+					wwlog.Warn("%s: no sensor readings found", result.Node)
+					wwlog.Verbose("%s: %s", result.Node, result.Output)
+				}
+				readings = append(readings, parsed...)
+			}
+			if vars.Showcmd {
+				return returnErr
 			}
-			batchpool.Submit(func() {
-				if vars.Full {
-					//nolint:errcheck
-					ipmiCmd.SensorList()
-				} else {
-					//nolint:errcheck
-					ipmiCmd.SDRList()
+			if vars.Record {
+				if err := sensor.Record(conf.Paths.SensorsDir(), readings, vars.Retention); err != nil {
+					return fmt.Errorf("could not record sensor readings: %w", err)
 				}
-				results <- ipmiCmd
-			})
-
+			}
 		}
 
-		batchpool.Run()
-
-		close(results)
-
-		for result := range results {
-			out, err := result.Result()
-
-			if err != nil {
-				for _, line := range strings.Split(out, "\n") {
-					wwlog.Error("%s: %s", result.Ipaddr, line)
-				}
-				returnErr = err
+		var shown []sensor.Reading
+		for _, reading := range readings {
+			if filter != nil && !filter.MatchString(reading.Name) {
+				continue
+			}
+			if vars.AlarmsOnly && reading.Alarm == "" {
 				continue
 			}
+			shown = append(shown, reading)
+		}
 
-			for _, line := range strings.Split(out, "\n") {
-				wwlog.Info("%s: %s", result.Ipaddr, line)
+		if vars.JSON {
+			if shown == nil {
+				shown = []sensor.Reading{}
+			}
+			buf, err := json.MarshalIndent(shown, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+		} else if vars.CSV {
+			if err := writeCSV(cmd, shown); err != nil {
+				return err
+			}
+		} else if len(shown) > 0 {
+			t := table.New(cmd.OutOrStdout())
+			if vars.Since > 0 {
+				t.AddHeader("TIME", "NODE", "SENSOR", "VALUE", "UNIT", "STATUS", "ALARM")
+			} else {
+				t.AddHeader("NODE", "SENSOR", "VALUE", "UNIT", "STATUS", "ALARM")
 			}
+			for _, reading := range shown {
+				line := []string{reading.Node, reading.Name, reading.FormatValue(), reading.Unit, reading.Status, reading.Alarm}
+				if vars.Since > 0 {
+					line = append([]string{reading.Time.Local().Format(time.DateTime)}, line...)
+				}
+				t.AddLine(table.Prep(line)...)
+			}
+			t.Print()
 		}
 
 		return returnErr
 	}
 }
+
+// writeCSV writes the readings as CSV with a header line
+func writeCSV(cmd *cobra.Command, readings []sensor.Reading) error {
+	writer := csv.NewWriter(cmd.OutOrStdout())
+	if err := writer.Write([]string{"node", "time", "name", "value", "unit", "status", "alarm", "lnr", "lcr", "lnc", "unc", "ucr", "unr"}); err != nil {
+		return err
+	}
+	threshold := func(value *float64) string {
+		if value == nil {
+			return ""
+		}
+		return sensor.Reading{Value: value}.FormatValue()
+	}
+	for _, reading := range readings {
+		record := []string{reading.Node, reading.Time.Format(time.RFC3339), reading.Name, reading.FormatValue(), reading.Unit, reading.Status, reading.Alarm}
+		thresholds := sensor.Thresholds{}
+		if reading.Thresholds != nil {
+			thresholds = *reading.Thresholds
+		}
+		record = append(record,
+			threshold(thresholds.LowerNonRecoverable), threshold(thresholds.LowerCritical), threshold(thresholds.LowerNonCritical),
+			threshold(thresholds.UpperNonCritical), threshold(thresholds.UpperCritical), threshold(thresholds.UpperNonRecoverable))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -1,14 +1,22 @@
 package sensors
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 )
 
 type variables struct {
-	Showcmd bool
-	Full    bool
-	Fanout  int
+	powercmd.Options
+	Full       bool
+	CSV        bool
+	AlarmsOnly bool
+	Sensor     string
+	Record     bool
+	Retention  time.Duration
+	Since      time.Duration
 }
 
 func GetCommand() *cobra.Command {
@@ -18,9 +26,11 @@ func GetCommand() *cobra.Command {
 		DisableFlagsInUseLine: true,
 		Use:                   "sensors [OPTIONS] PATTERN",
 		Short:                 "Show node IPMI sensor information",
-		Long:                  "Show IPMI sensor information for nodes matching PATTERN.",
-		Args:                  cobra.MinimumNArgs(1),
-		RunE:                  CobraRunE(&vars),
+		Long: "Show IPMI sensor information for nodes matching PATTERN.\n" +
+			"The readings are evaluated against their thresholds and can be recorded\n" +
+			"with --record, recorded readings are shown with --since.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: CobraRunE(&vars),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
 				return nil, cobra.ShellCompDirectiveNoFileComp
@@ -35,8 +45,15 @@ func GetCommand() *cobra.Command {
 			return node_names, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	powerCmd.PersistentFlags().BoolVarP(&vars.Full, "full", "F", false, "show detailed output.")
-	powerCmd.PersistentFlags().BoolVarP(&vars.Showcmd, "show", "s", false, "only show command which will be executed")
-	powerCmd.PersistentFlags().IntVar(&vars.Fanout, "fanout", 50, "how many command should be executed in parallel")
+	powerCmd.PersistentFlags().BoolVarP(&vars.Full, "full", "F", false, "show detailed output with thresholds.")
+	powercmd.AddFlags(powerCmd, &vars.Options, false)
+	powerCmd.PersistentFlags().BoolVarP(&vars.CSV, "csv", "c", false, "output the readings as CSV")
+	powerCmd.PersistentFlags().BoolVarP(&vars.AlarmsOnly, "alarms-only", "a", false, "only show readings which reached a threshold, implies --full")
+	powerCmd.PersistentFlags().StringVar(&vars.Sensor, "sensor", "", "only show sensors whose name matches the regular expression")
+	powerCmd.PersistentFlags().BoolVar(&vars.Record, "record", false, "add the readings to the sensor history")
+	powerCmd.PersistentFlags().DurationVar(&vars.Retention, "retention", 7*24*time.Hour, "remove recorded readings older than this, 0 to keep all")
+	powerCmd.PersistentFlags().DurationVar(&vars.Since, "since", 0, "show the recorded readings of this time span instead of querying the BMC, e.g. 1h")
+	powerCmd.MarkFlagsMutuallyExclusive("json", "csv")
+	powerCmd.MarkFlagsMutuallyExclusive("record", "since")
 	return powerCmd
 }
@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -27,8 +28,17 @@ nodes:
     profiles:
     - default
     ipmi:
-      ipaddr: 10.10.10.10`)
+      ipaddr: 10.10.10.10
+  n02:
+    ipmi:
+      ipaddr: 10.10.10.11
+      template: sensors.tmpl`)
 	env.ImportFile("usr/share/warewulf/bmc/ipmitool.tmpl", "../../../../../lib/warewulf/bmc/ipmitool.tmpl")
+	env.WriteFile("usr/share/warewulf/bmc/sensors.tmpl", `{{- if eq .Cmd "SensorList" -}}
+printf '%s\n' 'CPU Temp | 92.000 | degrees C | ok | na | 5.000 | 10.000 | 90.000 | 95.000 | 100.000' 'Fan1 | 600.000 | RPM | ok | na | 300.000 | 500.000 | na | na | na'
+{{- else -}}
+printf '%s\n' 'CPU Temp | 92 degrees C | ok' 'Fan1 | 600 RPM | ok'
+{{- end }}`)
 
 	tests := map[string]struct {
 		args     []string
@@ -36,11 +46,64 @@ nodes:
 	}{
 		"sensors": {
 			args:     []string{"--show", "n01"},
-			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P admin -e ~ sdr list",
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ sdr list",
+		},
+		"readings": {
+			args: []string{"n02"},
+			expected: `NODE  SENSOR    VALUE  UNIT       STATUS  ALARM
+----  ------    -----  ----       ------  -----
+n02   CPU Temp  92     degrees C  ok      --
+n02   Fan1      600    RPM        ok      --`,
+		},
+		"alarms only": {
+			args: []string{"--alarms-only", "--csv", "n02"},
+			expected: `node,time,name,value,unit,status,alarm,lnr,lcr,lnc,unc,ucr,unr
+n02,TIME,CPU Temp,92,degrees C,ok,warning,,5,10,90,95,100`,
+		},
+		"sensor filter": {
+			args: []string{"--full", "--sensor", "Fan.*", "--record", "n02"},
+			expected: `NODE  SENSOR  VALUE  UNIT  STATUS  ALARM
+----  ------  -----  ----  ------  -----
+n02   Fan1    600    RPM   ok      --`,
+		},
+		"history": {
+			args: []string{"--since", "1h", "--json", "n02"},
+			expected: `[
+  {
+    "node": "n02",
+    "time": "TIME",
+    "name": "CPU Temp",
+    "value": 92,
+    "unit": "degrees C",
+    "status": "ok",
+    "thresholds": {
+      "lcr": 5,
+      "lnc": 10,
+      "unc": 90,
+      "ucr": 95,
+      "unr": 100
+    },
+    "alarm": "warning"
+  },
+  {
+    "node": "n02",
+    "time": "TIME",
+    "name": "Fan1",
+    "value": 600,
+    "unit": "RPM",
+    "status": "ok",
+    "thresholds": {
+      "lcr": 300,
+      "lnc": 500
+    }
+  }
+]`,
 		},
 	}
+	timeRE := regexp.MustCompile(`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ`)
 
-	for name, tt := range tests {
+	for _, name := range []string{"sensors", "readings", "alarms only", "sensor filter", "history"} {
+		tt := tests[name]
 		t.Run(name, func(t *testing.T) {
 			baseCmd := GetCommand()
 			buf := new(bytes.Buffer)
@@ -50,7 +113,7 @@ nodes:
 			baseCmd.SetArgs(tt.args)
 			err := baseCmd.Execute()
 			assert.NoError(t, err)
-			assert.Equal(t, strings.TrimSpace(tt.expected), strings.TrimSpace(buf.String()))
+			assert.Equal(t, strings.TrimSpace(tt.expected), strings.TrimSpace(timeRE.ReplaceAllString(buf.String(), "TIME")))
 		})
 	}
 }
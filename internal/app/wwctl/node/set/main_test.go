@@ -239,6 +239,37 @@ nodes:
 	run_test(t, test)
 }
 
+func Test_Set_Selector(t *testing.T) {
+	test := test_description{
+		args:    []string{"--comment=gpu node", "n0[1-3]", "~@profile:gpu"},
+		wantErr: false,
+		stdout:  "",
+		inDB: `nodeprofiles:
+  gpu: {}
+nodes:
+  n01: {}
+  n02:
+    profiles:
+    - gpu
+  n03: {}
+  n04: {}
+`,
+		outDb: `nodeprofiles:
+  gpu: {}
+nodes:
+  n01:
+    comment: gpu node
+  n02:
+    profiles:
+    - gpu
+  n03:
+    comment: gpu node
+  n04: {}
+`,
+	}
+	run_test(t, test)
+}
+
 func Test_Multiple_Set_Tests(t *testing.T) {
 	tests := []test_description{
 		{
@@ -736,3 +767,80 @@ nodes:
 		run_test(t, tt)
 	}
 }
+
+func Test_Set_Custom_Fields(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		outDb   string
+	}{
+		{
+			name: "set custom fields",
+			args: []string{"--x-rack=r01", "--x-bmcvlan=42", "--x-licensed", "n01"},
+			outDb: `nodeprofiles: {}
+nodes:
+  n01:
+    fields:
+      bmcvlan: "42"
+      licensed: "true"
+      rack: r01
+      role: login
+`,
+		},
+		{
+			name: "unset custom field",
+			args: []string{"--x-role=UNSET", "n01"},
+			outDb: `nodeprofiles: {}
+nodes:
+  n01: {}
+`,
+		},
+		{
+			name:    "invalid uint",
+			args:    []string{"--x-bmcvlan=abc", "n01"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid enum",
+			args:    []string{"--x-role=storage", "n01"},
+			wantErr: true,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/fields.conf", `
+rack: {}
+role:
+  type: enum
+  values: [compute, login]
+bmcvlan:
+  type: uint
+licensed:
+  type: bool
+`)
+			env.WriteFile("etc/warewulf/nodes.conf", `nodeprofiles: {}
+nodes:
+  n01:
+    fields:
+      role: login
+`)
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(append(tt.args, "--yes"))
+			buf := new(bytes.Buffer)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.YAMLEq(t, tt.outDb, env.ReadFile("etc/warewulf/nodes.conf"))
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package state
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/warewulf/warewulf/internal/app/wwctl/node/state/set"
+)
+
+func GetCommand() *cobra.Command {
+	command := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "state COMMAND [OPTIONS]",
+		Short:                 "Node lifecycle state",
+		Long: `Nodes are in one of the lifecycle states active, maintenance, quarantined
+or retired. Quarantined and retired nodes are not provisioned, nodes in
+maintenance are skipped by the power commands unless they are forced.`,
+	}
+	command.AddCommand(set.GetCommand())
+	return command
+}
@@ -0,0 +1,22 @@
+package set
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) (err error) {
+		changed, err := apinode.NodeStateSet([]string{args[0]}, args[1], vars.reason)
+		if err != nil {
+			return err
+		}
+		for _, id := range changed {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", id, strings.ToLower(args[1]))
+		}
+		return nil
+	}
+}
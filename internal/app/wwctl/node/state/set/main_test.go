@@ -0,0 +1,89 @@
+package set
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+func Test_State_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		stdout  string
+		state   map[string]string
+		reason  string
+	}{
+		{
+			name:   "set maintenance",
+			args:   []string{"n0[1-2]", "maintenance", "--reason", "replace DIMM"},
+			stdout: "n01: maintenance\nn02: maintenance\n",
+			state:  map[string]string{"n01": node.StateMaintenance, "n02": node.StateMaintenance, "n03": node.StateActive},
+			reason: "replace DIMM",
+		},
+		{
+			name:   "set retired",
+			args:   []string{"n03", "Retired"},
+			stdout: "n03: retired\n",
+			state:  map[string]string{"n01": node.StateActive, "n03": node.StateRetired},
+		},
+		{
+			name:    "invalid state",
+			args:    []string{"n01", "broken"},
+			wantErr: true,
+			state:   map[string]string{"n01": node.StateActive},
+		},
+		{
+			name:    "unknown node",
+			args:    []string{"n99", "retired"},
+			wantErr: true,
+		},
+	}
+
+	warewulfd.SetNoDaemon()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n01: {}
+  n02: {}
+  n03: {}
+`)
+			before := time.Now().Add(-time.Second)
+			baseCmd := GetCommand()
+			baseCmd.SetArgs(tt.args)
+			stdout := bytes.NewBufferString("")
+			baseCmd.SetOut(stdout)
+			baseCmd.SetErr(bytes.NewBufferString(""))
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.stdout, stdout.String())
+			}
+
+			nodeDB, err := node.New()
+			assert.NoError(t, err)
+			for id, state := range tt.state {
+				n, err := nodeDB.GetNodeOnly(id)
+				assert.NoError(t, err)
+				assert.Equal(t, state, n.LifecycleState())
+				if state != node.StateActive {
+					assert.Equal(t, tt.reason, n.StateReason)
+					changed, err := time.Parse(time.RFC3339, n.StateChanged)
+					assert.NoError(t, err)
+					assert.True(t, changed.After(before))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package set
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+type variables struct {
+	reason string
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "set [OPTIONS] PATTERN STATE",
+		Short:                 "Set the lifecycle state of nodes",
+		Long: "This command sets the lifecycle state of the nodes matching PATTERN to\n" +
+			"STATE, which is one of active, maintenance, quarantined or retired. The\n" +
+			"reason and the time of the change are recorded with the state.",
+		RunE: CobraRunE(&vars),
+		Args: cobra.ExactArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				nodeDB, _ := node.New()
+				nodes, _ := nodeDB.FindAllNodes()
+				var node_names []string
+				for _, node := range nodes {
+					node_names = append(node_names, node.Id())
+				}
+				return node_names, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return node.NodeStates, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	baseCmd.PersistentFlags().StringVarP(&vars.reason, "reason", "r", "", "Reason for the change of state")
+
+	return baseCmd
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 	"golang.org/x/term"
 )
@@ -37,6 +38,15 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 			return err
 		}
 
+		states := make(map[string]string)
+		if nodeDB, err := node.New(); err != nil {
+			wwlog.Warn("Could not open node configuration: %s", err)
+		} else if nodes, err := nodeDB.FindAllNodes(); err == nil {
+			for _, n := range nodes {
+				states[n.Id()] = n.LifecycleState()
+			}
+		}
+
 		if SetWatch {
 			fmt.Print("\033[H\033[2J")
 			_, height, err = term.GetSize(0)
@@ -46,8 +56,8 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 			}
 		}
 
-		fmt.Printf("%-20s %-20s %-25s %-10s\n", "NODENAME", "STAGE", "SENT", "LASTSEEN (s)")
-		fmt.Printf("%s\n", strings.Repeat("=", 80))
+		fmt.Printf("%-20s %-12s %-20s %-25s %-10s\n", "NODENAME", "STATE", "STAGE", "SENT", "LASTSEEN (s)")
+		fmt.Printf("%s\n", strings.Repeat("=", 93))
 
 		wwlog.Verbose("Building sort index")
 		var statuses []*wwapiv1.NodeStatus
@@ -93,6 +103,10 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 		wwlog.Verbose("Printing results")
 		for i := 0; i < len(statuses); i++ {
 			o := statuses[i]
+			state := states[o.NodeName]
+			if state == "" {
+				state = "--"
+			}
 			if SetTime > 0 && o.Lastseen < SetTime {
 				continue
 			}
@@ -102,14 +116,14 @@ func CobraRunE(cmd *cobra.Command, args []string) (err error) {
 					continue
 				}
 				if rightnow-o.Lastseen >= int64(controller.Warewulf.UpdateInterval*2) {
-					color.Red("%-20s %-20s %-25s %-10d\n", o.NodeName, o.Stage, o.Sent, rightnow-o.Lastseen)
+					color.Red("%-20s %-12s %-20s %-25s %-10d\n", o.NodeName, state, o.Stage, o.Sent, rightnow-o.Lastseen)
 				} else if rightnow-o.Lastseen >= int64(controller.Warewulf.UpdateInterval+5) {
-					color.Yellow("%-20s %-20s %-25s %-10d\n", o.NodeName, o.Stage, o.Sent, rightnow-o.Lastseen)
+					color.Yellow("%-20s %-12s %-20s %-25s %-10d\n", o.NodeName, state, o.Stage, o.Sent, rightnow-o.Lastseen)
 				} else {
-					fmt.Printf("%-20s %-20s %-25s %-10d\n", o.NodeName, o.Stage, o.Sent, rightnow-o.Lastseen)
+					fmt.Printf("%-20s %-12s %-20s %-25s %-10d\n", o.NodeName, state, o.Stage, o.Sent, rightnow-o.Lastseen)
 				}
 			} else {
-				color.HiBlack("%-20s %-20s %-25s %-10s\n", o.NodeName, "--", "--", "--")
+				color.HiBlack("%-20s %-12s %-20s %-25s %-10s\n", o.NodeName, state, "--", "--", "--")
 			}
 			if count+4 >= height && SetWatch {
 				if count+1 != len(statuses) {
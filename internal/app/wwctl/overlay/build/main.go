@@ -26,9 +26,16 @@ func CobraRunE(cmd *cobra.Command, args []string) error {
 	var filteredNodes []node.Node
 	if len(args) > 0 {
 		args = hostlist.Expand(args)
-		filteredNodes = node.FilterNodeListByName(allNodes, args)
+		filteredNodes, err = node.FilterNodeListByName(allNodes, args)
+		if err != nil {
+			return err
+		}
 
-		if len(filteredNodes) < len(args) {
+		selectors := false
+		for _, arg := range args {
+			selectors = selectors || node.IsSelector(arg)
+		}
+		if len(filteredNodes) == 0 || (!selectors && len(filteredNodes) < len(args)) {
 			return errors.New("failed to find nodes")
 		}
 	} else {
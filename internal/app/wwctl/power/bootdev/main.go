@@ -0,0 +1,17 @@
+package bootdev
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
+	"github.com/warewulf/warewulf/internal/pkg/power"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) error {
+		operation, err := power.OperationBootDev(args[len(args)-1], vars.Persistent, vars.UEFI)
+		if err != nil {
+			return err
+		}
+		return powercmd.Run(cmd, args[:len(args)-1], operation, &vars.Options)
+	}
+}
@@ -0,0 +1,47 @@
+package bootdev
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/power"
+)
+
+type variables struct {
+	powercmd.Options
+	Persistent bool
+	UEFI       bool
+}
+
+// GetRootCommand returns the root cobra.Command for the application.
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	powerCmd := &cobra.Command{
+		Use:   "bootdev [OPTIONS] PATTERN [PATTERN ...] " + strings.Join(power.BootDevices, "|"),
+		Short: "Set the boot device of the given node(s)",
+		Long: "This command sets the device the nodes specified by PATTERN boot from\n" +
+			"next: pxe, disk or bios for the BIOS setup. The setting applies to the\n" +
+			"next boot only, unless --persistent is given.",
+		Args: cobra.MinimumNArgs(2),
+		RunE: CobraRunE(&vars),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return power.BootDevices, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			nodeDB, _ := node.New()
+			nodes, _ := nodeDB.FindAllNodes()
+			var node_names []string
+			for _, node := range nodes {
+				node_names = append(node_names, node.Id())
+			}
+			return node_names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	powercmd.AddFlags(powerCmd, &vars.Options, false)
+	powerCmd.PersistentFlags().BoolVar(&vars.Persistent, "persistent", false, "use the boot device for all following boots")
+	powerCmd.PersistentFlags().BoolVar(&vars.UEFI, "uefi", false, "boot in UEFI mode")
+	return powerCmd
+}
@@ -0,0 +1,96 @@
+package bootdev
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Power_BootDev(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodeprofiles:
+  default:
+    ipmi:
+      template: ipmitool.tmpl
+      username: admin
+      password: admin
+nodes:
+  n01:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.10
+  n02:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.11
+      backend: redfish
+    state: maintenance
+  n03:
+    ipmi:
+      ipaddr: 10.10.10.12
+      template: nobmc.tmpl`)
+	env.ImportFile("usr/share/warewulf/bmc/ipmitool.tmpl", "../../../../../lib/warewulf/bmc/ipmitool.tmpl")
+	env.ImportFile("usr/share/warewulf/bmc/nobmc.tmpl", "../../../../../lib/warewulf/bmc/nobmc.tmpl")
+
+	tests := map[string]struct {
+		args     []string
+		expected string
+		wantErr  bool
+	}{
+		"pxe": {
+			args:     []string{"--show", "n01", "pxe"},
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis bootdev pxe",
+		},
+		"persistent disk": {
+			args:     []string{"--show", "--persistent", "n01", "disk"},
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis bootdev disk options=persistent",
+		},
+		"uefi bios": {
+			args:     []string{"--show", "--persistent", "--uefi", "n01", "bios"},
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis bootdev bios options=persistent,efiboot",
+		},
+		"redfish in maintenance": {
+			args:     []string{"--show", "--uefi", "n02", "pxe"},
+			expected: `10.10.10.11: PATCH https://10.10.10.11:443/redfish/v1/Systems/<system> {"Boot":{"BootSourceOverrideEnabled":"Once","BootSourceOverrideMode":"UEFI","BootSourceOverrideTarget":"Pxe"}}`,
+		},
+		"invalid device": {
+			args:    []string{"--show", "n01", "floppy"},
+			wantErr: true,
+		},
+		"no bmc": {
+			args:     []string{"n03", "pxe"},
+			expected: "ERROR  : n03: boot device not supported without a BMC",
+			wantErr:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			baseCmd := GetCommand()
+			baseCmd.SilenceUsage = true
+			baseCmd.SilenceErrors = true
+			buf := new(bytes.Buffer)
+			baseCmd.SetOut(buf)
+			baseCmd.SetErr(buf)
+			wwlog.SetLogWriter(buf)
+			baseCmd.SetArgs(tt.args)
+			err := baseCmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, buf.String(), tt.expected)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(tt.expected), strings.TrimSpace(buf.String()))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package credentials
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/credentials/rotate"
+)
+
+// GetCommand returns the command which manages the BMC credentials
+func GetCommand() *cobra.Command {
+	command := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "credentials COMMAND [OPTIONS]",
+		Short:                 "Manage the BMC credentials of nodes",
+		Long:                  "These commands manage the credentials Warewulf uses to access the BMCs of the nodes.",
+	}
+	command.AddCommand(rotate.GetCommand())
+	return command
+}
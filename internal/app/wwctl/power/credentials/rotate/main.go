@@ -0,0 +1,176 @@
+package rotate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/history"
+	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/power"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) (err error) {
+	return func(cmd *cobra.Command, args []string) error {
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %s", err)
+		}
+		nodes, err := nodeDB.FindAllNodes()
+		if err != nil {
+			return fmt.Errorf("could not get node list: %s", err)
+		}
+		nodes, err = node.FilterNodeListByName(nodes, hostlist.Expand(args))
+		if err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			return fmt.Errorf("no nodes found")
+		}
+		passwords, err := newPasswords(nodes, vars.Length, vars.Group)
+		if err != nil {
+			return err
+		}
+
+		results := power.SetPasswords(nodes, passwords, power.Options{
+			Fanout:   vars.Fanout,
+			Timeout:  vars.Timeout,
+			Retries:  vars.Retries,
+			ShowOnly: vars.Showcmd,
+		})
+		if vars.JSON {
+			buf, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+		} else {
+			powercmd.PrintResults(results, &vars.Options)
+		}
+		if vars.Showcmd {
+			return nil
+		}
+
+		if err := save(results, nodes, passwords); err != nil {
+			return err
+		}
+		return power.Err(results)
+	}
+}
+
+/*
+newPasswords returns a new password for every node, the same one for
+the nodes with the same value of the field group. Nodes without a value
+get a password of their own.
+*/
+func newPasswords(nodes []node.Node, length int, group string) (map[string]string, error) {
+	passwords := make(map[string]string)
+	groups := make(map[string]string)
+	for _, n := range nodes {
+		key := ""
+		if group != "" {
+			key = n.SelectorValue(group)
+		}
+		if password, ok := groups[key]; ok && key != "" {
+			passwords[n.Id()] = password
+			continue
+		}
+		password, err := power.NewPassword(length)
+		if err != nil {
+			return nil, err
+		}
+		groups[key] = password
+		passwords[n.Id()] = password
+	}
+	return passwords, nil
+}
+
+/*
+save stores the new passwords of the nodes on which they were verified
+and records all results in the audit log. The node configuration is
+read again, as it may have changed while the BMCs were accessed. The
+passwords are encrypted if the server has a secret key. If they can't
+be saved, they are logged, as the BMCs don't accept the stored ones
+anymore.
+*/
+func save(results []power.Result, nodes []node.Node, passwords map[string]string) error {
+	keyring, err := secret.LoadKeyring(secret.KeyFile())
+	if errors.Is(err, secret.ErrNoKey) {
+		keyring, err = nil, nil
+	}
+	var nodeDB node.NodesYaml
+	if err == nil {
+		nodeDB, err = node.New()
+	}
+	if err == nil {
+		for _, result := range results {
+			if result.Failed() {
+				continue
+			}
+			n, getErr := nodeDB.GetNodeOnlyPtr(result.Node)
+			if getErr != nil {
+				err = fmt.Errorf("%s: %w", result.Node, getErr)
+				break
+			}
+			password := passwords[result.Node]
+			if keyring != nil {
+				if password, err = keyring.Encrypt(password); err != nil {
+					break
+				}
+			}
+			if n.Ipmi == nil {
+				n.Ipmi = new(node.IpmiConf)
+			}
+			n.Ipmi.Password = password
+		}
+	}
+	if err == nil {
+		err = nodeDB.Persist()
+	}
+
+	user := history.InvokingUser()
+	var records []power.AuditRecord
+	for i, result := range results {
+		record := power.AuditRecord{
+			Time:    time.Now(),
+			User:    user,
+			Action:  "rotate",
+			Node:    result.Node,
+			Address: result.Address,
+			State:   result.State,
+			Reason:  result.Reason,
+		}
+		if nodes[i].Ipmi != nil {
+			record.Account = nodes[i].Ipmi.UserName
+		}
+		if err != nil && !result.Failed() {
+			record.State = power.StateError
+			record.Reason = fmt.Sprintf("password changed but not saved: %s", err)
+		}
+		records = append(records, record)
+	}
+	if auditErr := power.Audit(warewulfconf.Get().Paths.AuditLog(), records...); auditErr != nil {
+		wwlog.Warn("could not write audit log: %s", auditErr)
+	}
+
+	if err != nil {
+		for _, result := range results {
+			if !result.Failed() {
+				wwlog.Error("%s: password changed to %s but not saved", result.Node, passwords[result.Node])
+			}
+		}
+		return fmt.Errorf("could not save new passwords: %w", err)
+	}
+	if err := warewulfd.DaemonReload(); err != nil {
+		return fmt.Errorf("failed to reload warewulf daemon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package rotate
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+type variables struct {
+	powercmd.Options
+	Length int
+	Group  string
+}
+
+// GetCommand returns the command which rotates the BMC passwords
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	rotateCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "rotate [OPTIONS] PATTERN [PATTERN ...]",
+		Short:                 "Set new BMC passwords for the given node(s)",
+		Long: "This command generates a new password for the BMC user of every node\n" +
+			"specified by PATTERN, or one per group of nodes with --group-by. The\n" +
+			"password is changed on the BMC with the current credentials and stored\n" +
+			"for the node only after logging in with it succeeded. Every change is\n" +
+			"recorded in the audit log, without the passwords.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: CobraRunE(&vars),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			nodeDB, _ := node.New()
+			nodes, _ := nodeDB.FindAllNodes()
+			var node_names []string
+			for _, node := range nodes {
+				node_names = append(node_names, node.Id())
+			}
+			return node_names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	powercmd.AddFlags(rotateCmd, &vars.Options, false)
+	rotateCmd.PersistentFlags().IntVar(&vars.Length, "length", 16, "length of the new passwords")
+	rotateCmd.PersistentFlags().StringVar(&vars.Group, "group-by", "", "use one password per group of nodes with the same value of a field, e.g. tag.rack or cluster")
+	return rotateCmd
+}
@@ -0,0 +1,131 @@
+package rotate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/ipmitest"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Rotate(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	sim1 := ipmitest.New(t)
+	port1, port2, port3 := sim1.Port(), ipmitest.New(t).Port(), ipmitest.New(t).Port()
+	env.WriteFile("etc/warewulf/nodes.conf", fmt.Sprintf(`
+nodeprofiles:
+  default:
+    ipmi:
+      username: admin
+      password: secret
+      backend: lanplus
+nodes:
+  n01:
+    profiles:
+    - default
+    tags:
+      rack: r1
+    ipmi:
+      ipaddr: 127.0.0.1
+      port: "%s"
+  n02:
+    profiles:
+    - default
+    tags:
+      rack: r1
+    ipmi:
+      ipaddr: 127.0.0.1
+      port: "%s"
+  n03:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 127.0.0.1
+      port: "%s"
+      password: wrong
+  n04:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.10
+      backend: ipmi
+      template: ipmitool.tmpl`, port1, port2, port3))
+	env.ImportFile("usr/share/warewulf/bmc/ipmitool.tmpl", "../../../../../../lib/warewulf/bmc/ipmitool.tmpl")
+
+	run := func(args ...string) (string, error) {
+		baseCmd := GetCommand()
+		baseCmd.SilenceUsage = true
+		baseCmd.SilenceErrors = true
+		buf := new(bytes.Buffer)
+		baseCmd.SetOut(buf)
+		baseCmd.SetErr(buf)
+		wwlog.SetLogWriter(buf)
+		baseCmd.SetArgs(args)
+		err := baseCmd.Execute()
+		return buf.String(), err
+	}
+
+	t.Run("show", func(t *testing.T) {
+		nodesConf := env.ReadFile("etc/warewulf/nodes.conf")
+		out, err := run("--show", "n01")
+		assert.NoError(t, err)
+		assert.Contains(t, out, "127.0.0.1: lanplus admin@127.0.0.1:"+port1+" user set password admin")
+		assert.Equal(t, nodesConf, env.ReadFile("etc/warewulf/nodes.conf"))
+		assert.NoFileExists(t, env.GetPath("var/local/warewulf/audit.log"))
+
+		out, err = run("--show", "--length", "20", "n04")
+		assert.NoError(t, err)
+		assert.Regexp(t, `10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '\*{6}' -e ~ user set password '<id>' '\*{20}' 20\n`, out)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		out, err := run("--group-by", "tag.rack", "--timeout", "5s", "n0[1-3]")
+		assert.EqualError(t, err, "power operation failed on 1 of 3 nodes")
+		assert.Contains(t, out, "n01: ok")
+		assert.Contains(t, out, "n02: ok")
+		assert.Contains(t, out, "n03: could not set password")
+
+		nodeDB, err := node.New()
+		require.NoError(t, err)
+		n01, err := nodeDB.GetNode("n01")
+		require.NoError(t, err)
+		n02, err := nodeDB.GetNode("n02")
+		require.NoError(t, err)
+		n03, err := nodeDB.GetNode("n03")
+		require.NoError(t, err)
+		assert.Len(t, n01.Ipmi.Password, 16)
+		assert.Equal(t, n01.Ipmi.Password, n02.Ipmi.Password, "same rack")
+		assert.Equal(t, "wrong", n03.Ipmi.Password)
+		profile, err := nodeDB.GetProfile("default")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", profile.Ipmi.Password)
+
+		client, err := ipmi.Dial(sim1.Address(), "admin", n01.Ipmi.Password, 5*time.Second)
+		require.NoError(t, err)
+		client.Close()
+
+		audit := env.ReadFile("var/local/warewulf/audit.log")
+		lines := strings.Split(strings.TrimSpace(audit), "\n")
+		assert.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"action":"rotate","node":"n01","address":"127.0.0.1","account":"admin","state":"ok"`)
+		assert.Contains(t, lines[2], `"node":"n03"`)
+		assert.Contains(t, lines[2], `"state":"error"`)
+		assert.NotContains(t, audit, n01.Ipmi.Password)
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		_, err := run("--length", "40", "n01")
+		assert.Error(t, err)
+	})
+}
@@ -2,12 +2,12 @@ package off
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 )
 
 type variables struct {
-	Showcmd bool
-	Fanout  int
+	powercmd.Options
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -33,8 +33,7 @@ func GetCommand() *cobra.Command {
 			return node_names, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	powerCmd.PersistentFlags().BoolVarP(&vars.Showcmd, "show", "s", false, "only show command which will be executed")
-	powerCmd.PersistentFlags().IntVar(&vars.Fanout, "fanout", 50, "how many command should be executed in parallel")
+	powercmd.AddFlags(powerCmd, &vars.Options, true)
 
 	return powerCmd
 }
@@ -36,7 +36,7 @@ nodes:
 	}{
 		"power off": {
 			args:     []string{"--show", "n01"},
-			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P admin -e ~ chassis power off",
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis power off",
 		},
 	}
 
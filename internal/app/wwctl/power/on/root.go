@@ -2,12 +2,12 @@ package on
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/power/powercmd"
 	"github.com/warewulf/warewulf/internal/pkg/node"
 )
 
 type variables struct {
-	Showcmd bool
-	Fanout  int
+	powercmd.Options
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -16,8 +16,10 @@ func GetCommand() *cobra.Command {
 	powerCmd := &cobra.Command{
 		Use:   "on [OPTIONS] [PATTERN ...]",
 		Short: "Power on the given node(s)",
-		Long:  "This command will power on a set of nodes specified by PATTERN.",
-		RunE:  CobraRunE(&vars),
+		Long: "This command will power on a set of nodes specified by PATTERN.\n" +
+			"With --wave-size or --group-size the nodes are powered on in waves,\n" +
+			"optionally grouped by a field like tag.rack with --group-by.",
+		RunE: CobraRunE(&vars),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
 				return nil, cobra.ShellCompDirectiveNoFileComp
@@ -32,8 +34,8 @@ func GetCommand() *cobra.Command {
 			return node_names, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
-	powerCmd.PersistentFlags().BoolVarP(&vars.Showcmd, "show", "s", false, "only show command which will be executed")
-	powerCmd.PersistentFlags().IntVar(&vars.Fanout, "fanout", 50, "how many command should be executed in parallel")
+	powercmd.AddFlags(powerCmd, &vars.Options, true)
+	powercmd.AddSequenceFlags(powerCmd, &vars.Options)
 
 	return powerCmd
 }
@@ -27,7 +27,34 @@ nodes:
     profiles:
     - default
     ipmi:
-      ipaddr: 10.10.10.10`)
+      ipaddr: 10.10.10.10
+  n02:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.11
+    state: maintenance
+  n03:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.12
+    tags:
+      rack: r1
+  n04:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.13
+    tags:
+      rack: r1
+  n05:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.14
+    tags:
+      rack: r2`)
 	env.ImportFile("usr/share/warewulf/bmc/ipmitool.tmpl", "../../../../../lib/warewulf/bmc/ipmitool.tmpl")
 
 	tests := map[string]struct {
@@ -36,7 +63,23 @@ nodes:
 	}{
 		"power on": {
 			args:     []string{"--show", "n01"},
-			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P admin -e ~ chassis power on",
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis power on",
+		},
+		"skip node in maintenance": {
+			args:     []string{"--show", "n02"},
+			expected: "WARN   : n02: skipping node in maintenance, use --force to override",
+		},
+		"force node in maintenance": {
+			args:     []string{"--show", "--force", "n02"},
+			expected: "10.10.10.11: ipmitool -I lan -H 10.10.10.11 -p 623 -U admin -P '*****' -e ~ chassis power on",
+		},
+		"waves by rack": {
+			args: []string{"--show", "--group-by", "tag.rack", "--group-size", "1", "n03", "n04", "n05"},
+			expected: `wave 1 of 2: 2 nodes
+10.10.10.12: ipmitool -I lan -H 10.10.10.12 -p 623 -U admin -P '*****' -e ~ chassis power on
+10.10.10.14: ipmitool -I lan -H 10.10.10.14 -p 623 -U admin -P '*****' -e ~ chassis power on
+wave 2 of 2: 1 nodes
+10.10.10.13: ipmitool -I lan -H 10.10.10.13 -p 623 -U admin -P '*****' -e ~ chassis power on`,
 		},
 	}
 	for name, tt := range tests {
@@ -0,0 +1,221 @@
+package powercmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apinode "github.com/warewulf/warewulf/internal/pkg/api/node"
+	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/power"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+// Options are the options shared by the power subcommands
+type Options struct {
+	Showcmd bool
+	Fanout  int
+	Timeout time.Duration
+	Retries int
+	JSON    bool
+	Force   bool
+
+	// sequencing of power on, see AddSequenceFlags
+	GroupBy     string
+	GroupSize   int
+	WaveSize    int
+	WaveDelay   time.Duration
+	WaitStage   string
+	WaitTimeout time.Duration
+}
+
+// stagePoll is the interval in which the node status is checked while
+// waiting for a wave to reach a provisioning stage
+const stagePoll = 5 * time.Second
+
+// sequenced returns true if the nodes are powered in waves
+func (opts *Options) sequenced() bool {
+	return opts.GroupSize > 0 || opts.WaveSize > 0 || opts.WaveDelay > 0 || opts.WaitStage != ""
+}
+
+// AddFlags adds the flags of the shared options to cmd, --force only
+// if the command changes the power state
+func AddFlags(cmd *cobra.Command, opts *Options, force bool) {
+	cmd.PersistentFlags().BoolVarP(&opts.Showcmd, "show", "s", false, "only show command which will be executed")
+	cmd.PersistentFlags().IntVar(&opts.Fanout, "fanout", 50, "how many command should be executed in parallel")
+	cmd.PersistentFlags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "timeout of a single BMC call, 0 for none")
+	cmd.PersistentFlags().IntVar(&opts.Retries, "retries", 0, "how often a failed BMC call is retried")
+	cmd.PersistentFlags().BoolVarP(&opts.JSON, "json", "j", false, "output the results as JSON")
+	if force {
+		cmd.PersistentFlags().BoolVarP(&opts.Force, "force", "f", false, "also power nodes which are in maintenance")
+	}
+}
+
+// AddSequenceFlags adds the flags to power the nodes in waves to cmd
+func AddSequenceFlags(cmd *cobra.Command, opts *Options) {
+	cmd.PersistentFlags().StringVar(&opts.GroupBy, "group-by", "", "group nodes by a field, e.g. tag.rack or cluster")
+	cmd.PersistentFlags().IntVar(&opts.GroupSize, "group-size", 0, "maximum number of nodes of a group per wave")
+	cmd.PersistentFlags().IntVar(&opts.WaveSize, "wave-size", 0, "maximum number of nodes per wave")
+	cmd.PersistentFlags().DurationVar(&opts.WaveDelay, "wave-delay", 0, "time to wait between waves")
+	cmd.PersistentFlags().StringVar(&opts.WaitStage, "wait-stage", "", "wait until the nodes of a wave reach a provisioning stage, e.g. kernel or runtime")
+	cmd.PersistentFlags().DurationVar(&opts.WaitTimeout, "wait-timeout", 10*time.Minute, "maximum time to wait for a wave to reach the provisioning stage")
+}
+
+/*
+Run runs the power operation on the nodes matching args and prints the
+results. An error is returned if the operation failed on any node.
+*/
+func Run(cmd *cobra.Command, args []string, operation power.Operation, opts *Options) error {
+	nodeDB, err := node.New()
+	if err != nil {
+		return fmt.Errorf("could not open node configuration: %s", err)
+	}
+
+	nodes, err := nodeDB.FindAllNodes()
+	if err != nil {
+		return fmt.Errorf("could not get node list: %s", err)
+	}
+
+	if len(args) > 0 {
+		nodes, err = node.FilterNodeListByName(nodes, hostlist.Expand(args))
+		if err != nil {
+			return err
+		}
+	} else {
+		//nolint:errcheck
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes found")
+	}
+
+	if operation.ChangesPower() && !opts.Force {
+		var skipped []node.Node
+		nodes, skipped = node.FilterNodeListByState(nodes, node.StateMaintenance)
+		for _, n := range skipped {
+			wwlog.Warn("%s: skipping node in maintenance, use --force to override", n.Id())
+		}
+	}
+
+	var stage string
+	if opts.WaitStage != "" {
+		if stage, err = apinode.ParseStage(opts.WaitStage); err != nil {
+			return err
+		}
+	}
+
+	runOpts := power.Options{
+		Fanout:   opts.Fanout,
+		Timeout:  opts.Timeout,
+		Retries:  opts.Retries,
+		ShowOnly: opts.Showcmd,
+	}
+	waves := [][]node.Node{nodes}
+	if opts.sequenced() {
+		waves = power.Waves(nodes, power.Sequence{
+			GroupBy:   opts.GroupBy,
+			GroupSize: opts.GroupSize,
+			WaveSize:  opts.WaveSize,
+		})
+	}
+
+	var results []power.Result
+	for i, wave := range waves {
+		if i > 0 && opts.WaveDelay > 0 && !opts.Showcmd {
+			progress(opts, "waiting %s before the next wave", opts.WaveDelay)
+			time.Sleep(opts.WaveDelay)
+		}
+		if len(waves) > 1 {
+			progress(opts, "wave %d of %d: %d nodes", i+1, len(waves), len(wave))
+		}
+		start := time.Now()
+		waveResults := power.Run(wave, operation, runOpts)
+		if !opts.JSON {
+			PrintResults(waveResults, opts)
+		}
+		results = append(results, waveResults...)
+		if stage != "" && !opts.Showcmd && i < len(waves)-1 {
+			if err := waitStage(waveResults, stage, start, opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.JSON {
+		if results == nil {
+			results = []power.Result{}
+		}
+		buf, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+	}
+
+	return power.Err(results)
+}
+
+// progress reports the progress of a sequence, only verbose with JSON
+// output so that the output stays valid JSON
+func progress(opts *Options, message string, args ...interface{}) {
+	if opts.JSON {
+		wwlog.Verbose(message, args...)
+	} else {
+		wwlog.Info(message, args...)
+	}
+}
+
+// PrintResults prints the results of a power operation as text
+func PrintResults(results []power.Result, opts *Options) {
+	for _, result := range results {
+		switch {
+		case result.Failed():
+			wwlog.Error("%s: %s", result.Node, result.Reason)
+		case opts.Showcmd:
+			wwlog.Info("%s: %s", result.Address, result.Output)
+		case result.Reason != "":
+			wwlog.Warn("%s: %s (%s)", result.Node, result.State, result.Reason)
+		default:
+			wwlog.Info("%s: %s", result.Node, result.State)
+		}
+	}
+}
+
+/*
+waitStage waits until the nodes of a wave which were powered
+successfully reached the provisioning stage since start. If they don't
+within opts.WaitTimeout, the sequence goes on with a warning.
+*/
+func waitStage(results []power.Result, stage string, start time.Time, opts *Options) error {
+	var names []string
+	for _, result := range results {
+		if !result.Failed() {
+			names = append(names, result.Node)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	progress(opts, "waiting for %d nodes to reach %s", len(names), stage)
+	deadline := start.Add(opts.WaitTimeout)
+	for {
+		pending, err := apinode.NodesPendingStage(names, stage, start)
+		if err != nil {
+			return fmt.Errorf("could not get node status: %w", err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			wwlog.Warn("%d nodes didn't reach %s within %s: %s", len(pending), stage, opts.WaitTimeout, strings.Join(pending, ", "))
+			return nil
+		}
+		time.Sleep(stagePoll)
+	}
+}
@@ -2,6 +2,8 @@ package power
 
 import (
 	"github.com/spf13/cobra"
+	powerbootdev "github.com/warewulf/warewulf/internal/app/wwctl/power/bootdev"
+	powercredentials "github.com/warewulf/warewulf/internal/app/wwctl/power/credentials"
 	powercycle "github.com/warewulf/warewulf/internal/app/wwctl/power/cycle"
 	poweroff "github.com/warewulf/warewulf/internal/app/wwctl/power/off"
 	poweron "github.com/warewulf/warewulf/internal/app/wwctl/power/on"
@@ -20,6 +22,8 @@ var (
 )
 
 func init() {
+	baseCmd.AddCommand(powerbootdev.GetCommand())
+	baseCmd.AddCommand(powercredentials.GetCommand())
 	baseCmd.AddCommand(powercycle.GetCommand())
 	baseCmd.AddCommand(poweroff.GetCommand())
 	baseCmd.AddCommand(poweron.GetCommand())
@@ -2,6 +2,8 @@ package status
 
 import (
 	"bytes"
+	"os"
+	"path"
 	"strings"
 	"testing"
 
@@ -27,30 +29,110 @@ nodes:
     profiles:
     - default
     ipmi:
-      ipaddr: 10.10.10.10`)
+      ipaddr: 10.10.10.10
+  n02:
+    profiles:
+    - default`)
 	env.ImportFile("usr/share/warewulf/bmc/ipmitool.tmpl", "../../../../../lib/warewulf/bmc/ipmitool.tmpl")
 
 	tests := map[string]struct {
 		args     []string
 		expected string
+		fail     bool
 	}{
 		"sensors": {
 			args:     []string{"--show", "n01"},
-			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P admin -e ~ chassis power status",
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis power status",
+		},
+		"json": {
+			args: []string{"--show", "--json", "n01"},
+			expected: `[
+  {
+    "node": "n01",
+    "address": "10.10.10.10",
+    "state": "unknown",
+    "output": "ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis power status",
+    "attempts": 1
+  }
+]`,
+		},
+		"no address": {
+			args:     []string{"--show", "n01", "n02"},
+			expected: "10.10.10.10: ipmitool -I lan -H 10.10.10.10 -p 623 -U admin -P '*****' -e ~ chassis power status\nERROR  : n02: no IPMI IP address",
+			fail:     true,
 		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			baseCmd := GetCommand()
+			baseCmd.SilenceUsage = true
+			baseCmd.SilenceErrors = true
 			buf := new(bytes.Buffer)
 			baseCmd.SetOut(buf)
 			baseCmd.SetErr(buf)
 			wwlog.SetLogWriter(buf)
 			baseCmd.SetArgs(tt.args)
 			err := baseCmd.Execute()
-			assert.NoError(t, err)
+			if tt.fail {
+				assert.EqualError(t, err, "power operation failed on 1 of 2 nodes")
+			} else {
+				assert.NoError(t, err)
+			}
 			assert.Equal(t, strings.TrimSpace(tt.expected), strings.TrimSpace(buf.String()))
 		})
 	}
 }
+
+func Test_Power_Status_NoBMC(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodeprofiles:
+  default:
+    ipmi:
+      template: nobmc.tmpl
+nodes:
+  n01:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.10
+  n02:
+    profiles:
+    - default
+    ipmi:
+      ipaddr: 10.10.10.11`)
+	env.ImportFile("usr/share/warewulf/bmc/nobmc.tmpl", "../../../../../lib/warewulf/bmc/nobmc.tmpl")
+	// only n01 answers to ping
+	bin := t.TempDir()
+	assert.NoError(t, os.WriteFile(path.Join(bin, "ping"), []byte("#!/bin/sh\n[ \"$3\" = 10.10.10.10 ]\n"), 0o755))
+	t.Setenv("PATH", bin+":"+os.Getenv("PATH"))
+
+	baseCmd := GetCommand()
+	baseCmd.SilenceUsage = true
+	baseCmd.SilenceErrors = true
+	buf := new(bytes.Buffer)
+	baseCmd.SetOut(buf)
+	baseCmd.SetErr(buf)
+	wwlog.SetLogWriter(buf)
+	baseCmd.SetArgs([]string{"--json", "n01", "n02"})
+	assert.NoError(t, baseCmd.Execute())
+	assert.JSONEq(t, `[
+  {
+    "node": "n01",
+    "address": "10.10.10.10",
+    "state": "on",
+    "output": "Power is on",
+    "attempts": 1
+  },
+  {
+    "node": "n02",
+    "address": "10.10.10.11",
+    "state": "off",
+    "output": "Power is off",
+    "attempts": 1
+  }
+]`, buf.String())
+}
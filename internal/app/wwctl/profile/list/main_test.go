@@ -167,6 +167,7 @@ nodes:
     "Root": "",
     "NetDevs": null,
     "Tags": null,
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
@@ -218,6 +219,7 @@ nodes:
     "Root": "",
     "NetDevs": null,
     "Tags": null,
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
@@ -236,6 +238,7 @@ nodes:
     "Root": "",
     "NetDevs": null,
     "Tags": null,
+    "Fields": null,
     "PrimaryNetDev": "",
     "Disks": null,
     "FileSystems": null
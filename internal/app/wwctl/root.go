@@ -6,12 +6,15 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/warewulf/warewulf/internal/app/wwctl/clean"
 	"github.com/warewulf/warewulf/internal/app/wwctl/configure"
+	"github.com/warewulf/warewulf/internal/app/wwctl/consoleserver"
 	"github.com/warewulf/warewulf/internal/app/wwctl/container"
 	"github.com/warewulf/warewulf/internal/app/wwctl/genconf"
+	"github.com/warewulf/warewulf/internal/app/wwctl/history"
 	"github.com/warewulf/warewulf/internal/app/wwctl/node"
 	"github.com/warewulf/warewulf/internal/app/wwctl/overlay"
 	"github.com/warewulf/warewulf/internal/app/wwctl/power"
 	"github.com/warewulf/warewulf/internal/app/wwctl/profile"
+	"github.com/warewulf/warewulf/internal/app/wwctl/secret"
 	"github.com/warewulf/warewulf/internal/app/wwctl/server"
 	"github.com/warewulf/warewulf/internal/app/wwctl/ssh"
 	"github.com/warewulf/warewulf/internal/app/wwctl/upgrade"
@@ -55,11 +58,14 @@ func init() {
 	rootCmd.AddCommand(profile.GetCommand())
 	rootCmd.AddCommand(configure.GetCommand())
 	rootCmd.AddCommand(server.GetCommand())
+	rootCmd.AddCommand(consoleserver.GetCommand())
 	rootCmd.AddCommand(version.GetCommand())
 	rootCmd.AddCommand(ssh.GetCommand())
 	rootCmd.AddCommand(genconf.GetCommand())
 	rootCmd.AddCommand(clean.GetCommand())
 	rootCmd.AddCommand(upgrade.GetCommand())
+	rootCmd.AddCommand(history.GetCommand())
+	rootCmd.AddCommand(secret.GetCommand())
 }
 
 // GetRootCommand returns the root cobra.Command for the application.
@@ -0,0 +1,40 @@
+package get
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %w", err)
+		}
+		var value string
+		if vars.profile {
+			profile, err := nodeDB.GetProfile(args[0])
+			if err != nil {
+				return fmt.Errorf("profile %s: %w", args[0], err)
+			}
+			value, _ = node.GetField(&profile, args[1])
+		} else {
+			n, err := nodeDB.GetNode(args[0])
+			if err != nil {
+				return fmt.Errorf("node %s: %w", args[0], err)
+			}
+			value, _ = node.GetField(&n, args[1])
+		}
+		if value == "" {
+			return fmt.Errorf("%s is not set for %s", args[1], args[0])
+		}
+		if err := secret.Reveal(&value); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	}
+}
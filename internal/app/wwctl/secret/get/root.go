@@ -0,0 +1,24 @@
+package get
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	profile bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "get [OPTIONS] NODE FIELD",
+		Short:                 "Show a decrypted value",
+		Long: `This command prints the decrypted value of FIELD of NODE, including values
+which the node inherits from its profiles.`,
+		Args: cobra.ExactArgs(2),
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.profile, "profile", "p", false, "Get the field of the profile NODE instead of a node")
+	return baseCmd
+}
@@ -0,0 +1,24 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/app/wwctl/secret/get"
+	"github.com/warewulf/warewulf/internal/app/wwctl/secret/rotatekey"
+	"github.com/warewulf/warewulf/internal/app/wwctl/secret/set"
+)
+
+func GetCommand() *cobra.Command {
+	command := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "secret COMMAND [OPTIONS]",
+		Short:                 "Encrypted secrets in nodes.conf",
+		Long: `Fields of nodes and profiles, like the IPMI password or tags holding keys, can
+be stored encrypted in nodes.conf with a key held by the server. Encrypted
+values are masked in listings and only decrypted for the templates of the
+node and for the BMC.`,
+	}
+	command.AddCommand(set.GetCommand())
+	command.AddCommand(get.GetCommand())
+	command.AddCommand(rotatekey.GetCommand())
+	return command
+}
@@ -0,0 +1,125 @@
+package secret
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	pkgsecret "github.com/warewulf/warewulf/internal/pkg/secret"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func Test_Secret(t *testing.T) {
+	warewulfd.SetNoDaemon()
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodeprofiles:
+  default:
+    tags:
+      rack: r1
+nodes:
+  n1:
+    profiles:
+    - default`)
+
+	run := func(stdin string, args ...string) (string, error) {
+		baseCmd := GetCommand()
+		baseCmd.SilenceUsage = true
+		baseCmd.SilenceErrors = true
+		buf := new(bytes.Buffer)
+		baseCmd.SetOut(buf)
+		baseCmd.SetErr(buf)
+		baseCmd.SetIn(strings.NewReader(stdin))
+		wwlog.SetLogWriter(buf)
+		baseCmd.SetArgs(args)
+		err := baseCmd.Execute()
+		return buf.String(), err
+	}
+
+	t.Run("set", func(t *testing.T) {
+		_, err := run("", "set", "n1", "Ipmi.Password", "admin")
+		require.NoError(t, err)
+		assert.FileExists(t, env.GetPath("etc/warewulf/secret.key"))
+		nodesConf := env.ReadFile("etc/warewulf/nodes.conf")
+		assert.Contains(t, nodesConf, pkgsecret.Prefix)
+		assert.NotContains(t, nodesConf, "admin")
+
+		_, err = run("munge key\n", "set", "--profile", "default", "tag.munge")
+		require.NoError(t, err)
+		assert.NotContains(t, env.ReadFile("etc/warewulf/nodes.conf"), "munge key")
+
+		_, err = run("", "set", "n2", "Ipmi.Password", "admin")
+		assert.Error(t, err)
+		_, err = run("", "set", "n1", "Ipmi.Password")
+		assert.EqualError(t, err, "no value given")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		out, err := run("", "get", "n1", "Ipmi.Password")
+		require.NoError(t, err)
+		assert.Equal(t, "admin\n", out)
+		out, err = run("", "get", "n1", "tag.munge")
+		require.NoError(t, err)
+		assert.Equal(t, "munge key\n", out, "inherited from the profile")
+		out, err = run("", "get", "--profile", "default", "tag.rack")
+		require.NoError(t, err)
+		assert.Equal(t, "r1\n", out)
+		_, err = run("", "get", "n1", "Ipmi.UserName")
+		assert.EqualError(t, err, "Ipmi.UserName is not set for n1")
+	})
+
+	t.Run("rotate-key", func(t *testing.T) {
+		keyring, err := pkgsecret.LoadKeyring(pkgsecret.KeyFile())
+		require.NoError(t, err)
+		old := keyring.Current()
+		nodeDB, err := node.New()
+		require.NoError(t, err)
+		oldNode, err := nodeDB.GetNodeOnly("n1")
+		require.NoError(t, err)
+		oldValue := oldNode.Ipmi.Password
+		out, err := run("", "rotate-key")
+		require.NoError(t, err)
+		assert.Contains(t, out, "encrypted 2 secrets")
+
+		keyring, err = pkgsecret.LoadKeyring(pkgsecret.KeyFile())
+		require.NoError(t, err)
+		assert.NotEqual(t, old, keyring.Current())
+		nodesConf := env.ReadFile("etc/warewulf/nodes.conf")
+		assert.NotContains(t, nodesConf, pkgsecret.Prefix+old)
+		assert.Contains(t, nodesConf, pkgsecret.Prefix+keyring.Current())
+
+		nodeDB, err = node.New()
+		require.NoError(t, err)
+		n, err := nodeDB.GetNode("n1")
+		require.NoError(t, err)
+		require.NoError(t, pkgsecret.Reveal(&n))
+		assert.Equal(t, "admin", n.Ipmi.Password)
+		assert.Equal(t, "munge key", n.Tags["munge"])
+
+		// the old key is kept for the earlier revisions in the history
+		_, err = keyring.Decrypt(oldValue)
+		assert.NoError(t, err)
+		_, err = run("", "rotate-key", "--prune")
+		require.NoError(t, err)
+		keyring, err = pkgsecret.LoadKeyring(pkgsecret.KeyFile())
+		require.NoError(t, err)
+		_, err = keyring.Decrypt(oldValue)
+		assert.NoError(t, err, "the history still uses the old key")
+
+		require.NoError(t, os.RemoveAll(warewulfconf.Get().Paths.HistoryDir()))
+		_, err = run("", "rotate-key", "--prune")
+		require.NoError(t, err)
+		keyring, err = pkgsecret.LoadKeyring(pkgsecret.KeyFile())
+		require.NoError(t, err)
+		_, err = keyring.Decrypt(oldValue)
+		assert.ErrorIs(t, err, pkgsecret.ErrNoKey)
+	})
+}
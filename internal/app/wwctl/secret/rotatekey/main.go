@@ -0,0 +1,68 @@
+package rotatekey
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apihistory "github.com/warewulf/warewulf/internal/pkg/api/history"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		file := secret.KeyFile()
+		keyring, err := secret.LoadKeyring(file)
+		if err != nil {
+			return err
+		}
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %w", err)
+		}
+
+		// the new key is saved next to the old ones before nodes.conf is
+		// written, the old keys are kept to read the earlier revisions
+		// of nodes.conf
+		if err := keyring.AddKey(); err != nil {
+			return err
+		}
+		if err := keyring.Save(file); err != nil {
+			return fmt.Errorf("could not save secret key: %w", err)
+		}
+		count := 0
+		err = secret.Walk(&nodeDB, func(value string) (string, error) {
+			count++
+			return keyring.Reencrypt(value)
+		})
+		if err != nil {
+			return err
+		}
+		if err := nodeDB.Persist(); err != nil {
+			return fmt.Errorf("failed to persist nodedb: %w", err)
+		}
+		wwlog.Info("encrypted %d secrets with the new key %s", count, keyring.Current())
+		if vars.prune {
+			// the earlier revisions of nodes.conf are encrypted with the
+			// old keys, which are kept while a revision uses them
+			revisions, err := apihistory.HistoryList("nodes")
+			if err != nil {
+				return err
+			}
+			var contents []string
+			for _, rev := range revisions {
+				contents = append(contents, rev.Content)
+			}
+			removed := keyring.Prune(contents...)
+			if err := keyring.Save(file); err != nil {
+				return fmt.Errorf("could not save secret key: %w", err)
+			}
+			for _, id := range removed {
+				wwlog.Info("removed the retired key %s", id)
+			}
+		}
+		return warewulfd.DaemonReload()
+	}
+}
@@ -0,0 +1,26 @@
+package rotatekey
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	prune bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "rotate-key",
+		Short:                 "Encrypt all secrets with a new key",
+		Long: `This command creates a new key and encrypts all secrets in nodes.conf again
+with it. The old keys are kept, so that the earlier revisions of
+nodes.conf in its history can still be read and rolled back to. With
+--prune, the old keys which no revision uses anymore are removed.`,
+		Args: cobra.NoArgs,
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().BoolVar(&vars.prune, "prune", false, "Remove the old keys which the history of nodes.conf doesn't use")
+	return baseCmd
+}
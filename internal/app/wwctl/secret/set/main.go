@@ -0,0 +1,76 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+func CobraRunE(vars *variables) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var value string
+		if len(args) == 3 {
+			value = args[2]
+		} else {
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		}
+		if value == "" {
+			return fmt.Errorf("no value given")
+		}
+
+		keyring, err := secret.LoadKeyring(secret.KeyFile())
+		if errors.Is(err, secret.ErrNoKey) {
+			keyring = &secret.Keyring{}
+			if err := keyring.AddKey(); err != nil {
+				return err
+			}
+			if err := keyring.Save(secret.KeyFile()); err != nil {
+				return fmt.Errorf("could not save secret key: %w", err)
+			}
+			wwlog.Info("created secret key %s", secret.KeyFile())
+		} else if err != nil {
+			return err
+		}
+		encrypted, err := keyring.Encrypt(value)
+		if err != nil {
+			return err
+		}
+
+		nodeDB, err := node.New()
+		if err != nil {
+			return fmt.Errorf("could not open node configuration: %w", err)
+		}
+		if vars.profile {
+			profile, err := nodeDB.GetProfilePtr(args[0])
+			if err != nil {
+				return fmt.Errorf("profile %s: %w", args[0], err)
+			}
+			if err := node.SetField(profile, args[1], encrypted); err != nil {
+				return err
+			}
+		} else {
+			n, err := nodeDB.GetNodeOnlyPtr(args[0])
+			if err != nil {
+				return fmt.Errorf("node %s: %w", args[0], err)
+			}
+			if err := node.SetField(n, args[1], encrypted); err != nil {
+				return err
+			}
+		}
+		if err := nodeDB.Persist(); err != nil {
+			return fmt.Errorf("failed to persist nodedb: %w", err)
+		}
+		return warewulfd.DaemonReload()
+	}
+}
@@ -0,0 +1,26 @@
+package set
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type variables struct {
+	profile bool
+}
+
+func GetCommand() *cobra.Command {
+	vars := variables{}
+	baseCmd := &cobra.Command{
+		DisableFlagsInUseLine: true,
+		Use:                   "set [OPTIONS] NODE FIELD [VALUE]",
+		Short:                 "Store an encrypted value",
+		Long: `This command encrypts VALUE and stores it in FIELD of NODE, e.g. Ipmi.Password
+or Tags[munge] (or tag.munge). Without VALUE, the value is read from the
+standard input, so that it doesn't show up in the shell history. The key
+of the server is created if it doesn't exist yet.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: CobraRunE(&vars),
+	}
+	baseCmd.PersistentFlags().BoolVarP(&vars.profile, "profile", "p", false, "Set the field of the profile NODE instead of a node")
+	return baseCmd
+}
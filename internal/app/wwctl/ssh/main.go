@@ -32,7 +32,11 @@ func CobraRunE(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(args) > 0 {
-		nodes = node.FilterNodeListByName(nodes, hostlist.Expand(args))
+		nodes, err = node.FilterNodeListByName(nodes, hostlist.Expand(args))
+		if err != nil {
+			wwlog.Error("%s", err)
+			os.Exit(1)
+		}
 	} else {
 		//nolint:errcheck
 		cmd.Usage()
@@ -100,7 +100,7 @@ ARG_LOOP:
 		//_, arg := range args {
 		containerName := cdp.ContainerNames[i]
 		for _, n := range nodes {
-			if n.ContainerName == containerName {
+			if n.ContainerName == containerName || strings.HasPrefix(n.ContainerName, containerName+"@") {
 				wwlog.Error("Container is configured for nodes, skipping: %s", containerName)
 				continue ARG_LOOP
 			}
@@ -110,7 +110,16 @@ ARG_LOOP:
 			wwlog.Error("Container name is not a valid source: %s", containerName)
 			continue
 		}
-		err := container.DeleteSource(containerName)
+		snapshots, err := container.Snapshots(containerName)
+		if err != nil {
+			wwlog.Error("Could not list snapshots of %s: %s", containerName, err)
+		}
+		for _, snapshot := range snapshots {
+			if err := container.DeleteSnapshot(containerName, snapshot.Name); err != nil {
+				wwlog.Error("Could not remove snapshot %s of %s: %s", snapshot.Name, containerName, err)
+			}
+		}
+		err = container.DeleteSource(containerName)
 		if err != nil {
 			wwlog.Error("Could not remove source: %s", containerName)
 		}
@@ -211,6 +220,36 @@ func ContainerImport(cip *wwapiv1.ContainerImportParameter) (containerName strin
 	return
 }
 
+/*
+ContainerUpdate pulls the OCI image a container was imported from
+again into a new snapshot, if the image changed, and returns the name
+of the snapshot. The snapshot is built if requested.
+*/
+func ContainerUpdate(cip *wwapiv1.ContainerImportParameter) (snapshot string, err error) {
+	if cip == nil {
+		err = fmt.Errorf("ContainerImportParameter is nil")
+		return
+	}
+	var sCtx *types.SystemContext
+	sCtx, err = getSystemContext(cip.OciNoHttps, cip.OciUsername, cip.OciPassword, cip.Platform)
+	if err != nil {
+		return
+	}
+	snapshot, err = container.Update(cip.Name, sCtx)
+	if err != nil || snapshot == "" {
+		return
+	}
+	if cip.Build {
+		ref := container.SnapshotName(cip.Name, snapshot)
+		err = container.Build(ref, true)
+		if err != nil {
+			err = fmt.Errorf("could not build container %s: %s", ref, err.Error())
+			return
+		}
+	}
+	return
+}
+
 func ContainerList() (containerInfo []*wwapiv1.ContainerInfo, err error) {
 	var sources []string
 
@@ -257,7 +296,8 @@ func ContainerList() (containerInfo []*wwapiv1.ContainerInfo, err error) {
 			creationTime = uint64(sourceStat.ModTime().Unix())
 		}
 		var modTime uint64
-		imageStat, err := os.Stat(container.ImageFile(source))
+		imageFile := container.FormatImageFile(source, container.Format(source))
+		imageStat, err := os.Stat(imageFile)
 		if err == nil {
 			modTime = uint64(imageStat.ModTime().Unix())
 		}
@@ -266,13 +306,17 @@ func ContainerList() (containerInfo []*wwapiv1.ContainerInfo, err error) {
 			wwlog.Error("%s\n", err)
 		}
 		imgSize := 0
-		if imgF, err := os.Stat(container.ImageFile(source)); err == nil {
+		if imgF, err := os.Stat(imageFile); err == nil {
 			imgSize = int(imgF.Size())
 		}
 		imgCSize := 0
 		if imgFC, err := os.Stat(container.ImageFile(source) + ".gz"); err == nil {
 			imgCSize = int(imgFC.Size())
 		}
+		if container.Format(source) != container.FormatCpio {
+			// squashfs and erofs images are compressed themselves
+			imgCSize = imgSize
+		}
 		containerInfo = append(containerInfo, &wwapiv1.ContainerInfo{
 			Name:          source,
 			NodeCount:     uint32(nodemap[source]),
@@ -342,6 +386,19 @@ func ContainerRename(crp *wwapiv1.ContainerRenameParameter) (err error) {
 		return err
 	}
 
+	snapshots, err := container.Snapshots(crp.TargetName)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		err = container.RenameSnapshotImages(
+			container.SnapshotName(crp.ContainerName, snapshot.Name),
+			container.SnapshotName(crp.TargetName, snapshot.Name))
+		if err != nil {
+			return err
+		}
+	}
+
 	err = container.DeleteImage(crp.ContainerName)
 	if err != nil {
 		wwlog.Warn("Could not remove image files for %s: %s", crp.ContainerName, err)
@@ -365,9 +422,7 @@ func ContainerRename(crp *wwapiv1.ContainerRenameParameter) (err error) {
 		return err
 	}
 	for _, node := range nodes {
-		if node.ContainerName == crp.ContainerName {
-			node.ContainerName = crp.TargetName
-		}
+		node.ContainerName = renamedContainer(node.ContainerName, crp.ContainerName, crp.TargetName)
 	}
 
 	profiles, err := nodeDB.FindAllProfiles()
@@ -375,9 +430,7 @@ func ContainerRename(crp *wwapiv1.ContainerRenameParameter) (err error) {
 		return err
 	}
 	for _, profile := range profiles {
-		if profile.ContainerName == crp.ContainerName {
-			profile.ContainerName = crp.TargetName
-		}
+		profile.ContainerName = renamedContainer(profile.ContainerName, crp.ContainerName, crp.TargetName)
 	}
 
 	err = nodeDB.Persist()
@@ -395,6 +448,18 @@ func ContainerRename(crp *wwapiv1.ContainerRenameParameter) (err error) {
 	return warewulfd.DaemonReload()
 }
 
+// renamedContainer returns the container name, or the name of a
+// snapshot of it, after a container was renamed
+func renamedContainer(containerName string, oldName string, newName string) string {
+	if containerName == oldName {
+		return newName
+	}
+	if snapshot, ok := strings.CutPrefix(containerName, oldName+"@"); ok {
+		return container.SnapshotName(newName, snapshot)
+	}
+	return containerName
+}
+
 // create the system context and reading out environment variables
 func getSystemContext(noHttps bool, username string, password string, platform string) (sCtx *types.SystemContext, err error) {
 	sCtx = &types.SystemContext{}
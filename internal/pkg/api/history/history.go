@@ -0,0 +1,114 @@
+package apihistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/history"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+Returns the path of the configuration file with the given name, which
+is either nodes.conf or warewulf.conf
+*/
+func ConfigFile(name string) (string, error) {
+	conf := warewulfconf.Get()
+	switch name {
+	case "", "nodes", "nodes.conf":
+		return conf.Paths.NodesConf(), nil
+	case "warewulf", "warewulf.conf":
+		if conf.GetWarewulfConf() != "" {
+			return conf.GetWarewulfConf(), nil
+		}
+		return warewulfconf.ConfigFile, nil
+	}
+	return "", fmt.Errorf("no history for configuration file: %s", name)
+}
+
+/*
+Returns all recorded revisions of the given configuration file
+*/
+func HistoryList(name string) ([]history.Revision, error) {
+	file, err := ConfigFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return history.List(warewulfconf.Get().Paths.HistoryDir(), file)
+}
+
+/*
+Returns a single revision of the given configuration file
+*/
+func HistoryGet(name string, id int) (history.Revision, error) {
+	file, err := ConfigFile(name)
+	if err != nil {
+		return history.Revision{}, err
+	}
+	return history.Get(warewulfconf.Get().Paths.HistoryDir(), file, id)
+}
+
+/*
+Returns the diff between two revisions of the given configuration
+file. If to is 0 the revision is compared against the current file.
+*/
+func HistoryDiff(name string, from, to int) (string, error) {
+	file, err := ConfigFile(name)
+	if err != nil {
+		return "", err
+	}
+	historyDir := warewulfconf.Get().Paths.HistoryDir()
+	fromRev, err := history.Get(historyDir, file, from)
+	if err != nil {
+		return "", err
+	}
+	var toContent string
+	if to == 0 {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		toContent = string(data)
+	} else {
+		toRev, err := history.Get(historyDir, file, to)
+		if err != nil {
+			return "", err
+		}
+		toContent = toRev.Content
+	}
+	return history.Diff(filepath.Base(file), fromRev.Content, toContent)
+}
+
+/*
+Restores the given configuration file to the content of the given
+revision. If id is 0 the last recorded change is undone.
+*/
+func HistoryRollback(name string, id int) (restored int, err error) {
+	file, err := ConfigFile(name)
+	if err != nil {
+		return
+	}
+	historyDir := warewulfconf.Get().Paths.HistoryDir()
+	if id == 0 {
+		var latest int
+		latest, err = history.Latest(historyDir, file)
+		if err != nil {
+			return
+		}
+		if latest < 2 {
+			return 0, fmt.Errorf("no previous revision of %s recorded", filepath.Base(file))
+		}
+		id = latest - 1
+	}
+	if err = history.Restore(historyDir, file, id); err != nil {
+		return
+	}
+	wwlog.Info("Restored %s to revision %d", file, id)
+	if err = warewulfd.DaemonReload(); err != nil {
+		return id, fmt.Errorf("failed to reload warewulf daemon: %w", err)
+	}
+	return id, nil
+}
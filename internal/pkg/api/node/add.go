@@ -61,6 +61,16 @@ func NodeAdd(nap *wwapiv1.NodeAddParameter) (err error) {
 				ipmiaddr = n.Ipmi.Ipaddr
 			}
 		}
+		if err = nodeDB.AllocateAddresses(a); err != nil {
+			return fmt.Errorf("failed to allocate addresses: %w", err)
+		}
+		merged, err := nodeDB.GetNode(a)
+		if err != nil {
+			return fmt.Errorf("failed to add node: %w", err)
+		}
+		if err = merged.CheckNetDevs(); err != nil {
+			return fmt.Errorf("%s: %w", a, err)
+		}
 	}
 
 	err = nodeDB.Persist()
@@ -0,0 +1,316 @@
+package apinode
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/container"
+	"github.com/warewulf/warewulf/internal/pkg/kernel"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/overlay"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+const (
+	CheckError   = "error"
+	CheckWarning = "warning"
+)
+
+// CheckResult is a single problem found by NodeCheck
+type CheckResult struct {
+	Severity string `json:"severity" yaml:"severity"`
+	Node     string `json:"node,omitempty" yaml:"node,omitempty"`
+	Profile  string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Field    string `json:"field,omitempty" yaml:"field,omitempty"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// nodeCheck collects the results of NodeCheck
+type nodeCheck struct {
+	results []CheckResult
+}
+
+func (check *nodeCheck) node(severity, id, field, format string, args ...interface{}) {
+	check.results = append(check.results, CheckResult{
+		Severity: severity, Node: id, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (check *nodeCheck) profile(severity, id, field, format string, args ...interface{}) {
+	check.results = append(check.results, CheckResult{
+		Severity: severity, Profile: id, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+/*
+NodeCheck checks the consistency of the whole node database. It
+reports duplicate hardware and IP addresses, references to profiles,
+containers, overlays and kernels which don't exist, nodes without a
+primary network device, addresses outside of their configured network,
+invalid bonds, VLANs and bridges, invalid custom fields and lifecycle
+states and profile cycles.
+*/
+func NodeCheck() (results []CheckResult, err error) {
+	nodeDB, err := node.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node database: %w", err)
+	}
+	nodes, err := nodeDB.FindAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	check := new(nodeCheck)
+	check.profileCycles(nodeDB)
+	check.profileReferences(nodeDB, nodes)
+	check.customFields(nodeDB, nodes)
+	check.duplicateAddresses(nodes)
+	for _, n := range nodes {
+		check.references(n)
+		check.primaryNetDev(nodeDB, n)
+		check.networks(n)
+		if n.State != "" && !util.InSlice(node.NodeStates, n.State) {
+			check.node(CheckError, n.Id(), "State", "invalid state %s, must be one of %s", n.State, strings.Join(node.NodeStates, ", "))
+		}
+		if err := n.CheckNetDevs(); err != nil {
+			check.node(CheckError, n.Id(), "NetDevs", "%s", err)
+		}
+	}
+	sort.SliceStable(check.results, func(i, j int) bool {
+		a, b := check.results[i], check.results[j]
+		if (a.Profile == "") != (b.Profile == "") {
+			return a.Profile != ""
+		}
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Message < b.Message
+	})
+	return check.results, nil
+}
+
+// CheckErrors returns the number of results with error severity
+func CheckErrors(results []CheckResult) (count int) {
+	for _, result := range results {
+		if result.Severity == CheckError {
+			count++
+		}
+	}
+	return
+}
+
+// CheckResponse returns the results of NodeCheck as response of the
+// Warewulf API
+func CheckResponse(results []CheckResult) *wwapiv1.NodeCheckResponse {
+	response := &wwapiv1.NodeCheckResponse{}
+	for _, result := range results {
+		response.Results = append(response.Results, &wwapiv1.NodeCheckResult{
+			Severity: result.Severity,
+			Node:     result.Node,
+			Profile:  result.Profile,
+			Field:    result.Field,
+			Message:  result.Message,
+		})
+	}
+	return response
+}
+
+// profileCycles reports profiles which include themselves, directly or
+// through other profiles
+func (check *nodeCheck) profileCycles(nodeDB node.NodesYaml) {
+	reported := make(map[string]bool)
+	var visit func(id string, path []string)
+	visit = func(id string, path []string) {
+		for i, prev := range path {
+			if prev == id {
+				cycle := append(append([]string{}, path[i:]...), id)
+				if !reported[id] {
+					check.profile(CheckError, id, "Profiles", "profile cycle: %s", strings.Join(cycle, " -> "))
+					reported[id] = true
+				}
+				return
+			}
+		}
+		profile, err := nodeDB.GetProfile(id)
+		if err != nil {
+			return
+		}
+		for _, sub := range profile.Profiles {
+			if !strings.HasPrefix(sub, "~") {
+				visit(sub, append(path, id))
+			}
+		}
+	}
+	for _, id := range nodeDB.ListAllProfiles() {
+		visit(id, nil)
+	}
+}
+
+// profileReferences reports profiles which are referenced by nodes or
+// other profiles but don't exist
+func (check *nodeCheck) profileReferences(nodeDB node.NodesYaml, nodes []node.Node) {
+	for _, n := range nodes {
+		own, err := nodeDB.GetNodeOnly(n.Id())
+		if err != nil {
+			continue
+		}
+		for _, ref := range own.Profiles {
+			if _, ok := nodeDB.NodeProfiles[strings.TrimPrefix(ref, "~")]; !ok {
+				check.node(CheckError, n.Id(), "Profiles", "profile does not exist: %s", ref)
+			}
+		}
+	}
+	for _, id := range nodeDB.ListAllProfiles() {
+		profile, err := nodeDB.GetProfile(id)
+		if err != nil {
+			continue
+		}
+		for _, ref := range profile.Profiles {
+			if _, ok := nodeDB.NodeProfiles[strings.TrimPrefix(ref, "~")]; !ok {
+				check.profile(CheckError, id, "Profiles", "profile does not exist: %s", ref)
+			}
+		}
+	}
+}
+
+// customFields reports custom field values of nodes and profiles
+// which are not declared in fields.conf or don't match their type
+func (check *nodeCheck) customFields(nodeDB node.NodesYaml, nodes []node.Node) {
+	for _, n := range nodes {
+		if own, err := nodeDB.GetNodeOnly(n.Id()); err == nil {
+			if err := own.CheckFields(); err != nil {
+				check.node(CheckError, n.Id(), "Fields", "%s", err)
+			}
+		}
+	}
+	for _, id := range nodeDB.ListAllProfiles() {
+		if profile, err := nodeDB.GetProfile(id); err == nil {
+			if err := profile.CheckFields(); err != nil {
+				check.profile(CheckError, id, "Fields", "%s", err)
+			}
+		}
+	}
+}
+
+// duplicateAddresses reports hardware and IP addresses which are used
+// by more than one network device or IPMI interface
+func (check *nodeCheck) duplicateAddresses(nodes []node.Node) {
+	type owner struct{ node, field string }
+	hwaddrs := make(map[string][]owner)
+	ipaddrs := make(map[string][]owner)
+	for _, n := range nodes {
+		for name, netdev := range n.NetDevs {
+			if netdev == nil {
+				continue
+			}
+			if netdev.Hwaddr != "" {
+				hwaddr := strings.ToLower(netdev.Hwaddr)
+				hwaddrs[hwaddr] = append(hwaddrs[hwaddr], owner{n.Id(), fmt.Sprintf("NetDevs[%s].Hwaddr", name)})
+			}
+			if netdev.Ipaddr != nil && !netdev.Ipaddr.IsUnspecified() {
+				ipaddrs[netdev.Ipaddr.String()] = append(ipaddrs[netdev.Ipaddr.String()], owner{n.Id(), fmt.Sprintf("NetDevs[%s].Ipaddr", name)})
+			}
+		}
+		if n.Ipmi != nil && n.Ipmi.Ipaddr != nil && !n.Ipmi.Ipaddr.IsUnspecified() {
+			ipaddrs[n.Ipmi.Ipaddr.String()] = append(ipaddrs[n.Ipmi.Ipaddr.String()], owner{n.Id(), "Ipmi.Ipaddr"})
+		}
+	}
+	for kind, addrs := range map[string]map[string][]owner{"hardware": hwaddrs, "IP": ipaddrs} {
+		for addr, owners := range addrs {
+			if len(owners) < 2 {
+				continue
+			}
+			for _, o := range owners {
+				var others []string
+				for _, other := range owners {
+					if other != o {
+						others = append(others, other.node+":"+other.field)
+					}
+				}
+				check.node(CheckError, o.node, o.field, "duplicate %s address %s, also used by %s", kind, addr, strings.Join(others, ", "))
+			}
+		}
+	}
+}
+
+// references reports containers, overlays and kernels which are
+// referenced by the node but don't exist
+func (check *nodeCheck) references(n node.Node) {
+	if n.ContainerName != "" {
+		if !container.DoesSourceExist(n.ContainerName) {
+			check.node(CheckError, n.Id(), "ContainerName", "container does not exist: %s", n.ContainerName)
+		} else if kernel.FromNode(&n) == nil {
+			if n.Kernel != nil && n.Kernel.Version != "" {
+				check.node(CheckError, n.Id(), "Kernel.Version", "kernel %s not found in container %s", n.Kernel.Version, n.ContainerName)
+			} else {
+				check.node(CheckWarning, n.Id(), "Kernel.Version", "no kernel found in container %s", n.ContainerName)
+			}
+		}
+	}
+	for field, overlays := range map[string][]string{"SystemOverlay": n.SystemOverlay, "RuntimeOverlay": n.RuntimeOverlay} {
+		for _, name := range overlays {
+			if !overlay.GetOverlay(name).Exists() {
+				check.node(CheckError, n.Id(), field, "overlay does not exist: %s", name)
+			}
+		}
+	}
+}
+
+// primaryNetDev reports nodes without a primary network device
+func (check *nodeCheck) primaryNetDev(nodeDB node.NodesYaml, n node.Node) {
+	if len(n.NetDevs) == 0 {
+		check.node(CheckWarning, n.Id(), "PrimaryNetDev", "no network devices configured")
+		return
+	}
+	if own, err := nodeDB.GetNodeOnly(n.Id()); err == nil && own.PrimaryNetDev != "" {
+		if _, ok := n.NetDevs[own.PrimaryNetDev]; !ok {
+			check.node(CheckError, n.Id(), "PrimaryNetDev", "primary network device does not exist: %s", own.PrimaryNetDev)
+		}
+	}
+}
+
+// networks reports addresses which are outside of the named network or
+// the address pool of their network device, or for the primary network
+// device, outside of the network of the Warewulf server
+func (check *nodeCheck) networks(n node.Node) {
+	conf := warewulfconf.Get()
+	var names []string
+	for name := range n.NetDevs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		netdev := n.NetDevs[name]
+		if netdev == nil || netdev.Ipaddr == nil || netdev.Ipaddr.IsUnspecified() {
+			continue
+		}
+		field := fmt.Sprintf("NetDevs[%s].Ipaddr", name)
+		if netdev.Network != "" {
+			if network, ok := conf.Networks[netdev.Network]; !ok || network == nil {
+				check.node(CheckError, n.Id(), fmt.Sprintf("NetDevs[%s].Network", name), "network does not exist: %s", netdev.Network)
+			} else if _, prefix, err := net.ParseCIDR(network.Network); err == nil && !prefix.Contains(netdev.Ipaddr) {
+				check.node(CheckError, n.Id(), field, "address %s is outside of network %s (%s)", netdev.Ipaddr, netdev.Network, network.Network)
+			}
+		}
+		if netdev.Pool != "" {
+			if pool, ok := conf.AddressPools[netdev.Pool]; !ok || pool == nil {
+				check.node(CheckError, n.Id(), fmt.Sprintf("NetDevs[%s].Pool", name), "address pool does not exist: %s", netdev.Pool)
+			} else if prefix, err := pool.Prefix(); err == nil && !prefix.Contains(netdev.Ipaddr) {
+				check.node(CheckError, n.Id(), field, "address %s is outside of address pool %s (%s)", netdev.Ipaddr, netdev.Pool, pool.Network)
+			}
+		}
+		if netdev.Network == "" && netdev.Pool == "" && name == n.PrimaryNetDev && conf.Network != "" && conf.Netmask != "" {
+			network := net.IPNet{IP: net.ParseIP(conf.Network), Mask: net.IPMask(net.ParseIP(conf.Netmask).To4())}
+			if network.IP != nil && network.Mask != nil && !network.Contains(netdev.Ipaddr) {
+				check.node(CheckWarning, n.Id(), field, "address %s is outside of the Warewulf network %s", netdev.Ipaddr, network.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package apinode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_CheckResponse(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodeprofiles:
+  default:
+    profiles:
+      - missing
+nodes:
+  n01:
+    profiles:
+      - default
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:01
+`)
+
+	results, err := NodeCheck()
+	require.NoError(t, err)
+	assert.Equal(t, &wwapiv1.NodeCheckResponse{
+		Results: []*wwapiv1.NodeCheckResult{
+			{Severity: CheckError, Profile: "default", Field: "Profiles", Message: "profile does not exist: missing"},
+		},
+	}, CheckResponse(results))
+	assert.Empty(t, CheckResponse(nil).Results)
+}
@@ -81,7 +81,10 @@ func NodeDeleteParameterCheck(ndp *wwapiv1.NodeDeleteParameter, console bool) (n
 		return
 	}
 
-	node_args := hostlist.Expand(ndp.NodeNames)
+	node_args, err := nodeDB.SelectNodeNames(hostlist.Expand(ndp.NodeNames))
+	if err != nil {
+		return
+	}
 
 	for _, r := range node_args {
 		var match bool
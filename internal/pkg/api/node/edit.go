@@ -13,36 +13,62 @@ import (
 /*
 Returns filtered list of nodes
 */
-func FilteredNodes(nodeList *wwapiv1.NodeList) *wwapiv1.NodeYaml {
+func FilteredNodes(nodeList *wwapiv1.NodeList) (*wwapiv1.NodeYaml, error) {
 	nodeDB, err := node.New()
 	if err != nil {
 		wwlog.Error("Could not open nodeDB: %s\n", err)
 		os.Exit(1)
 	}
 	nodeMap, _ := nodeDB.FindAllNodes()
-	nodeMap = node.FilterNodeListByName(nodeMap, nodeList.Output)
+	nodeMap, err = node.FilterNodeListByName(nodeMap, nodeList.Output)
+	if err != nil {
+		return nil, err
+	}
 	buffer, _ := yaml.Marshal(nodeMap)
 	retVal := wwapiv1.NodeYaml{
 		NodeConfMapYaml: string(buffer),
 		Hash:            nodeDB.StringHash(),
 	}
-	return &retVal
+	return &retVal, nil
 }
 
 /*
 Add nodes from yaml
 */
 func NodeAddFromYaml(nodeList *wwapiv1.NodeYaml) (err error) {
+	return NodeReplaceFromYaml(nodeList, nil, false)
+}
+
+/*
+Replace the nodes in replaced with the nodes from yaml in a single
+write of the node database. Unless forced, the hash of the node database
+must still be the hash the yaml was created with, or node.ErrConflict is
+returned.
+*/
+func NodeReplaceFromYaml(nodeList *wwapiv1.NodeYaml, replaced []string, force bool) (err error) {
 	nodeDB, err := node.New()
 	if err != nil {
 		return fmt.Errorf("could not open NodeDB: %w", err)
 	}
+	if !force && nodeDB.StringHash() != nodeList.Hash {
+		return fmt.Errorf("%w, not modifying node database", node.ErrConflict)
+	}
 	nodeMap := make(map[string]*node.Node)
 	err = yaml.Unmarshal([]byte(nodeList.NodeConfMapYaml), nodeMap)
 	if err != nil {
 		return fmt.Errorf("could not unmarshal Yaml: %w", err)
 	}
+	for _, nodeName := range replaced {
+		if err = nodeDB.DelNode(nodeName); err != nil {
+			wwlog.Verbose("Problem deleting node before modification: %s", err)
+		}
+	}
 	for nodeName, node := range nodeMap {
+		if _, ok := nodeDB.Nodes[nodeName]; !ok {
+			if _, err = nodeDB.AddNode(nodeName); err != nil {
+				return fmt.Errorf("couldn't add node: %w", err)
+			}
+		}
 		err = nodeDB.SetNode(nodeName, *node)
 		if err != nil {
 			return fmt.Errorf("couldn't set node: %w", err)
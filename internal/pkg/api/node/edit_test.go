@@ -0,0 +1,65 @@
+package apinode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_NodeReplaceFromYaml(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n1:
+    comment: one
+  n2:
+    comment: two`)
+
+	loaded, err := FilteredNodes(&wwapiv1.NodeList{Output: []string{"n1", "n2"}})
+	require.NoError(t, err)
+	edited := &wwapiv1.NodeYaml{
+		NodeConfMapYaml: "n1:\n  comment: edited\n",
+		Hash:            loaded.Hash,
+	}
+
+	// someone else changes the nodes while they are edited
+	env.WriteFile("etc/warewulf/nodes.conf", `
+nodes:
+  n1:
+    comment: one
+  n2:
+    comment: two
+  n3:
+    comment: three`)
+	err = NodeReplaceFromYaml(edited, []string{"n1", "n2"}, false)
+	assert.True(t, errors.Is(err, node.ErrConflict))
+	assert.Contains(t, env.ReadFile("etc/warewulf/nodes.conf"), "n3")
+
+	require.NoError(t, NodeReplaceFromYaml(edited, []string{"n1", "n2"}, true))
+	nodeDB, err := node.New()
+	require.NoError(t, err)
+	nodes, err := nodeDB.FindAllNodes()
+	require.NoError(t, err)
+	var ids []string
+	for _, n := range nodes {
+		ids = append(ids, n.Id())
+	}
+	assert.ElementsMatch(t, []string{"n1", "n3"}, ids)
+	n1, err := nodeDB.GetNode("n1")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", n1.Comment)
+
+	// without a conflict the hash matches
+	loaded, err = FilteredNodes(&wwapiv1.NodeList{Output: []string{"n3"}})
+	require.NoError(t, err)
+	require.NoError(t, NodeReplaceFromYaml(&wwapiv1.NodeYaml{
+		NodeConfMapYaml: "n3:\n  comment: edited\n",
+		Hash:            loaded.Hash,
+	}, []string{"n3"}, false))
+}
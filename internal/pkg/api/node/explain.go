@@ -0,0 +1,47 @@
+package apinode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+)
+
+/*
+NodeExplain returns the resolution chain of the fields of the given
+node. If field is not empty, only fields with this name, or nested
+below it (e.g. "Kernel" or "NetDevs[default]"), are returned. The
+field name is matched case-insensitively.
+*/
+func NodeExplain(nodeName string, field string) (explanation node.NodeExplanation, err error) {
+	nodeDB, err := node.New()
+	if err != nil {
+		return explanation, fmt.Errorf("failed to open node database: %w", err)
+	}
+	explanation, err = nodeDB.ExplainNode(nodeName)
+	if err != nil {
+		return explanation, fmt.Errorf("failed to explain node %s: %w", nodeName, err)
+	}
+	secret.MaskAll(&explanation)
+	if field == "" {
+		return explanation, nil
+	}
+	var fields []node.FieldExplanation
+	for _, explained := range explanation.Fields {
+		if matchField(explained.Field, field) {
+			fields = append(fields, explained)
+		}
+	}
+	if len(fields) == 0 {
+		return explanation, fmt.Errorf("field %s is not set for node %s", field, nodeName)
+	}
+	explanation.Fields = fields
+	return explanation, nil
+}
+
+// matchField returns true if name is field or nested below field
+func matchField(name, field string) bool {
+	name, field = strings.ToLower(name), strings.ToLower(field)
+	return name == field || strings.HasPrefix(name, field+".") || strings.HasPrefix(name, field+"[")
+}
@@ -0,0 +1,180 @@
+package apinode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+)
+
+// Modes of NodeImport
+const (
+	ImportCreate  = "create"
+	ImportUpdate  = "update"
+	ImportReplace = "replace"
+)
+
+// ImportModes are the valid modes of NodeImport
+var ImportModes = []string{ImportCreate, ImportUpdate, ImportReplace}
+
+// NodeImportChange is the change of a single field of a node made by
+// an import. Old is empty for fields which are added, New for fields
+// which are removed.
+type NodeImportChange struct {
+	Node  string `json:"node" yaml:"node"`
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old,omitempty" yaml:"old,omitempty"`
+	New   string `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// NodeImportPlan holds the changes of an import until they are applied
+type NodeImportPlan struct {
+	Created []string
+	Changes []NodeImportChange
+	nodeDB  node.NodesYaml
+}
+
+/*
+NodeImport prepares the import of the given node records. In create
+mode, all nodes must be new. In update mode, the fields in the records
+are set on existing nodes and all other fields are kept. In replace
+mode, existing nodes are replaced by the records. Nodes which don't
+exist are created in update and replace mode.
+
+Nothing is written until the returned plan is applied.
+*/
+func NodeImport(records map[string]node.NodeRecord, mode string) (plan *NodeImportPlan, err error) {
+	if !util.InSlice(ImportModes, mode) {
+		return nil, fmt.Errorf("invalid import mode %s, must be one of %s", mode, strings.Join(ImportModes, ", "))
+	}
+	plan = new(NodeImportPlan)
+	plan.nodeDB, err = node.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node database: %w", err)
+	}
+
+	var ids []string
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if len(hostlist.Expand([]string{id})) != 1 {
+			return nil, fmt.Errorf("invalid node name: %s", id)
+		}
+		old, exists := plan.nodeDB.Nodes[id]
+		if exists && mode == ImportCreate {
+			return nil, fmt.Errorf("node %s already exists", id)
+		}
+		newNode := node.NewNode(id)
+		if exists && mode == ImportUpdate {
+			if err = newNode.ApplyRecord(old.Record()); err != nil {
+				return nil, fmt.Errorf("%s: %w", id, err)
+			}
+		}
+		if err = newNode.ApplyRecord(records[id]); err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+		if err = newNode.CheckFields(); err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+
+		var oldRecord node.NodeRecord
+		fields := node.RecordFields(newNode)
+		if exists {
+			oldRecord = old.Record()
+			fields = node.RecordFields(*old, newNode)
+		} else {
+			plan.Created = append(plan.Created, id)
+		}
+		newRecord := newNode.Record()
+		changed := !exists
+		for _, field := range fields {
+			if oldRecord[field] != newRecord[field] {
+				plan.Changes = append(plan.Changes, NodeImportChange{
+					Node: id, Field: field, Old: oldRecord[field], New: newRecord[field]})
+				changed = true
+			}
+		}
+		if changed {
+			plan.nodeDB.Nodes[id] = &newNode
+		}
+	}
+	return plan, nil
+}
+
+// Empty returns true if the import doesn't change anything
+func (plan *NodeImportPlan) Empty() bool {
+	return len(plan.Created) == 0 && len(plan.Changes) == 0
+}
+
+// Diff returns the changes of the import as text: a line for every
+// created node and for every added (+), removed (-) and changed (~)
+// field
+func (plan *NodeImportPlan) Diff() string {
+	var diff strings.Builder
+	for _, id := range plan.Created {
+		fmt.Fprintf(&diff, "+ %s\n", id)
+	}
+	for _, change := range plan.Changes {
+		switch {
+		case change.Old == "":
+			fmt.Fprintf(&diff, "+ %s %s: %s\n", change.Node, change.Field, change.New)
+		case change.New == "":
+			fmt.Fprintf(&diff, "- %s %s: %s\n", change.Node, change.Field, change.Old)
+		default:
+			fmt.Fprintf(&diff, "~ %s %s: %s -> %s\n", change.Node, change.Field, change.Old, change.New)
+		}
+	}
+	return diff.String()
+}
+
+// Apply writes the imported nodes to the node database
+func (plan *NodeImportPlan) Apply() error {
+	if err := plan.nodeDB.Persist(); err != nil {
+		return fmt.Errorf("failed to persist nodedb: %w", err)
+	}
+	if err := warewulfd.DaemonReload(); err != nil {
+		return fmt.Errorf("failed to reload warewulf daemon: %w", err)
+	}
+	return nil
+}
+
+/*
+NodeExport returns the nodes matching the given patterns, or all nodes
+if no pattern is given, as they are configured in the node database,
+without the values of their profiles.
+*/
+func NodeExport(patterns []string) (nodes []node.Node, err error) {
+	nodeDB, err := node.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node database: %w", err)
+	}
+	merged, err := nodeDB.FindAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) > 0 {
+		merged, err = node.FilterNodeListByName(merged, hostlist.Expand(patterns))
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Id() < merged[j].Id() })
+	for _, n := range merged {
+		own, err := nodeDB.GetNodeOnly(n.Id())
+		if err != nil {
+			return nil, err
+		}
+		exported := node.NewNode(n.Id())
+		if err := exported.ApplyRecord(own.Record()); err != nil {
+			return nil, fmt.Errorf("%s: %w", n.Id(), err)
+		}
+		nodes = append(nodes, exported)
+	}
+	return nodes, nil
+}
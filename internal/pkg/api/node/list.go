@@ -9,6 +9,7 @@ import (
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
 	"github.com/warewulf/warewulf/internal/pkg/hostlist"
 	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 	"gopkg.in/yaml.v3"
 )
@@ -29,11 +30,15 @@ func NodeList(nodeGet *wwapiv1.GetNodeList) (nodeList wwapiv1.NodeList, err erro
 	}
 	nodeGet.Nodes = hostlist.Expand(nodeGet.Nodes)
 	sort.Strings(nodeGet.Nodes)
+	nodes, err = node.FilterNodeListByName(nodes, nodeGet.Nodes)
+	if err != nil {
+		return
+	}
 
 	if nodeGet.Type == wwapiv1.GetNodeList_Simple {
 		nodeList.Output = append(nodeList.Output,
 			fmt.Sprintf("%s:=:%s:=:%s", "NODE NAME", "PROFILES", "NETWORK"))
-		for _, n := range node.FilterNodeListByName(nodes, nodeGet.Nodes) {
+		for _, n := range nodes {
 			var netNames []string
 			for k := range n.NetDevs {
 				netNames = append(netNames, k)
@@ -45,7 +50,7 @@ func NodeList(nodeGet *wwapiv1.GetNodeList) (nodeList wwapiv1.NodeList, err erro
 	} else if nodeGet.Type == wwapiv1.GetNodeList_Network {
 		nodeList.Output = append(nodeList.Output,
 			fmt.Sprintf("%s:=:%s:=:%s:=:%s:=:%s:=:%s", "NODE", "NETWORK", "HWADDR", "IPADDR", "GATEWAY", "DEVICE"))
-		for _, n := range node.FilterNodeListByName(nodes, nodeGet.Nodes) {
+		for _, n := range nodes {
 			if len(n.NetDevs) > 0 {
 				for name := range n.NetDevs {
 					nodeList.Output = append(nodeList.Output,
@@ -63,7 +68,7 @@ func NodeList(nodeGet *wwapiv1.GetNodeList) (nodeList wwapiv1.NodeList, err erro
 	} else if nodeGet.Type == wwapiv1.GetNodeList_Ipmi {
 		nodeList.Output = append(nodeList.Output,
 			fmt.Sprintf("%s:=:%s:=:%s:=:%s:=:%s", "NODE", "IPMI IPADDR", "IPMI PORT", "IPMI USERNAME", "IPMI INTERFACE"))
-		for _, n := range node.FilterNodeListByName(nodes, nodeGet.Nodes) {
+		for _, n := range nodes {
 			nodeList.Output = append(nodeList.Output,
 				fmt.Sprintf("%s:=:%s:=:%s:=:%s:=:%s",
 					n.Id(),
@@ -75,7 +80,7 @@ func NodeList(nodeGet *wwapiv1.GetNodeList) (nodeList wwapiv1.NodeList, err erro
 	} else if nodeGet.Type == wwapiv1.GetNodeList_Long {
 		nodeList.Output = append(nodeList.Output,
 			fmt.Sprintf("%s:=:%s:=:%s:=:%s", "NODE NAME", "KERNEL VERSION", "CONTAINER", "OVERLAYS (S/R)"))
-		for _, n := range node.FilterNodeListByName(nodes, nodeGet.Nodes) {
+		for _, n := range nodes {
 			nodeList.Output = append(nodeList.Output,
 				fmt.Sprintf("%s:=:%s:=:%s:=:%s", n.Id(),
 					n.Kernel.Version,
@@ -85,19 +90,22 @@ func NodeList(nodeGet *wwapiv1.GetNodeList) (nodeList wwapiv1.NodeList, err erro
 	} else if nodeGet.Type == wwapiv1.GetNodeList_All {
 		nodeList.Output = append(nodeList.Output,
 			fmt.Sprintf("%s:=:%s:=:%s:=:%s", "NODE", "FIELD", "PROFILE", "VALUE"))
-		for _, n := range node.FilterNodeListByName(nodes, nodeGet.Nodes) {
+		for _, n := range nodes {
 			if _, fields, err := nodeDB.MergeNode(n.Id()); err != nil {
 				wwlog.Error("unable to merge node %v: %v", n.Id(), err)
 				continue
 			} else {
 				for _, f := range fields.List(n) {
 					nodeList.Output = append(nodeList.Output,
-						fmt.Sprintf("%s:=:%s:=:%s:=:%s", n.Id(), f.Field, f.Source, f.Value))
+						fmt.Sprintf("%s:=:%s:=:%s:=:%s", n.Id(), f.Field, f.Source, secret.MaskValue(f.Value)))
 				}
 			}
 		}
 	} else if nodeGet.Type == wwapiv1.GetNodeList_YAML || nodeGet.Type == wwapiv1.GetNodeList_JSON {
-		filterNodes := node.FilterNodeListByName(nodes, nodeGet.Nodes)
+		filterNodes := nodes
+		for i := range filterNodes {
+			secret.MaskAll(&filterNodes[i])
+		}
 		var buf []byte
 		if nodeGet.Type == wwapiv1.GetNodeList_JSON {
 			buf, _ = json.MarshalIndent(filterNodes, "", "  ")
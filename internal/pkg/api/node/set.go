@@ -57,8 +57,13 @@ func NodeSetParameterCheck(set *wwapiv1.ConfSetParameter) (nodeDB node.NodesYaml
 		wwlog.Warn("no nodes/profiles found")
 		return
 	}
-	for _, nId := range set.ConfList {
-		if util.InSlice(set.ConfList, nId) {
+	var nodeNames []string
+	nodeNames, err = nodeDB.SelectNodeNames(set.ConfList)
+	if err != nil {
+		return
+	}
+	for _, nId := range nodeNames {
+		if util.InSlice(nodeNames, nId) {
 			wwlog.Debug("evaluating node: %s", nId)
 			var nodePtr *node.Node
 			nodePtr, err = nodeDB.GetNodeOnlyPtr(nId)
@@ -138,6 +143,17 @@ func NodeSetParameterCheck(set *wwapiv1.ConfSetParameter) (nodeDB node.NodesYaml
 					nodePtr.NetDevs[set.Netdev].Tags[key] = val
 				}
 			}
+			if err = nodeDB.AllocateAddresses(nId); err != nil {
+				return
+			}
+			var merged node.Node
+			if merged, err = nodeDB.GetNode(nId); err != nil {
+				return
+			}
+			if err = merged.CheckNetDevs(); err != nil {
+				err = fmt.Errorf("%s: %w", nId, err)
+				return
+			}
 			count++
 		}
 	}
@@ -0,0 +1,54 @@
+package apinode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/hostlist"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/warewulfd"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+NodeStateSet sets the lifecycle state of the nodes matching the given
+patterns, records the reason and the time of the change and returns the
+ids of the changed nodes.
+*/
+func NodeStateSet(patterns []string, state, reason string) (changed []string, err error) {
+	nodeDB, err := node.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node database: %w", err)
+	}
+	nodes, err := nodeDB.FindAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err = node.FilterNodeListByName(nodes, hostlist.Expand(patterns))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found")
+	}
+	now := time.Now()
+	for _, n := range nodes {
+		own, err := nodeDB.GetNodeOnlyPtr(n.Id())
+		if err != nil {
+			return nil, err
+		}
+		if err := own.SetState(state, reason, now); err != nil {
+			return nil, err
+		}
+		wwlog.Verbose("node %s: state %s", n.Id(), own.State)
+		changed = append(changed, n.Id())
+	}
+
+	if err = nodeDB.Persist(); err != nil {
+		return nil, fmt.Errorf("failed to persist nodedb: %w", err)
+	}
+	if err = warewulfd.DaemonReload(); err != nil {
+		return nil, fmt.Errorf("failed to reload warewulf daemon: %w", err)
+	}
+	return changed, nil
+}
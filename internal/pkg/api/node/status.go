@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 
@@ -92,3 +94,78 @@ func NodeStatus(nodeNames []string) (nodeStatusResponse *wwapiv1.NodeStatusRespo
 	}
 	return
 }
+
+// ProvisionStages are the provisioning stages reported in the node
+// status, in the order in which a node passes them. EFI is reported
+// instead of IPXE when booting with GRUB.
+var ProvisionStages = []string{"IPXE", "KERNEL", "INITRAMFS", "SYSTEM_OVERLAY", "RUNTIME_OVERLAY"}
+
+// stageAliases maps the names of the provisioning requests to the
+// stages reported in the node status
+var stageAliases = map[string]string{
+	"EFI":     "IPXE",
+	"EFIBOOT": "IPXE",
+	"SYSTEM":  "SYSTEM_OVERLAY",
+	"RUNTIME": "RUNTIME_OVERLAY",
+}
+
+// ParseStage returns the provisioning stage for the given name, e.g.
+// kernel, system or runtime_overlay
+func ParseStage(name string) (string, error) {
+	stage := strings.ToUpper(name)
+	if alias, ok := stageAliases[stage]; ok {
+		stage = alias
+	}
+	for _, s := range ProvisionStages {
+		if s == stage {
+			return stage, nil
+		}
+	}
+	return "", fmt.Errorf("invalid provisioning stage %s, must be one of %s", name, strings.ToLower(strings.Join(ProvisionStages, ", ")))
+}
+
+// stageIndex returns the position of the stage in ProvisionStages, or
+// -1 for unknown stages
+func stageIndex(stage string) int {
+	if alias, ok := stageAliases[stage]; ok {
+		stage = alias
+	}
+	for i, s := range ProvisionStages {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// stageReached returns true if the node has reported the stage or a
+// later one since the given time
+func stageReached(status *wwapiv1.NodeStatus, stage string, since time.Time) bool {
+	if status.Lastseen < since.Unix() {
+		return false
+	}
+	current := stageIndex(status.Stage)
+	return current >= 0 && current >= stageIndex(stage)
+}
+
+/*
+NodesPendingStage returns the nodes which haven't reached the
+provisioning stage, or a later one, since the given time.
+This requires warewulfd.
+*/
+func NodesPendingStage(nodeNames []string, stage string, since time.Time) (pending []string, err error) {
+	nodeStatusResponse, err := NodeStatus(nodeNames)
+	if err != nil {
+		return nil, err
+	}
+	reached := make(map[string]bool)
+	for _, status := range nodeStatusResponse.NodeStatus {
+		reached[status.NodeName] = stageReached(status, stage, since)
+	}
+	for _, name := range nodeNames {
+		if !reached[name] {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
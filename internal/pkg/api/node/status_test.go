@@ -0,0 +1,43 @@
+package apinode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
+)
+
+func Test_ParseStage(t *testing.T) {
+	for name, expected := range map[string]string{
+		"kernel":          "KERNEL",
+		"efiboot":         "IPXE",
+		"system":          "SYSTEM_OVERLAY",
+		"RUNTIME_OVERLAY": "RUNTIME_OVERLAY",
+	} {
+		stage, err := ParseStage(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, stage)
+	}
+	_, err := ParseStage("container")
+	assert.Error(t, err)
+}
+
+func Test_stageReached(t *testing.T) {
+	since := time.Unix(1000, 0)
+	tests := map[string]struct {
+		status  *wwapiv1.NodeStatus
+		reached bool
+	}{
+		"stage":        {&wwapiv1.NodeStatus{Stage: "KERNEL", Lastseen: 1000}, true},
+		"later stage":  {&wwapiv1.NodeStatus{Stage: "SYSTEM_OVERLAY", Lastseen: 1010}, true},
+		"efi":          {&wwapiv1.NodeStatus{Stage: "EFI", Lastseen: 1010}, false},
+		"before since": {&wwapiv1.NodeStatus{Stage: "RUNTIME_OVERLAY", Lastseen: 999}, false},
+		"never seen":   {&wwapiv1.NodeStatus{}, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.reached, stageReached(tt.status, "KERNEL", since))
+		})
+	}
+}
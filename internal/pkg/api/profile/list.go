@@ -7,6 +7,7 @@ import (
 
 	"github.com/warewulf/warewulf/internal/pkg/api/routes/wwapiv1"
 	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
 	"gopkg.in/yaml.v3"
 )
 
@@ -34,12 +35,13 @@ func ProfileList(ShowOpt *wwapiv1.GetProfileList) (profileList wwapiv1.ProfileLi
 			fields := node.GetFieldList(p)
 			for _, f := range fields {
 				profileList.Output = append(profileList.Output,
-					fmt.Sprintf("%s:=:%s:=:%s", p.Id(), f.Field, f.Value))
+					fmt.Sprintf("%s:=:%s:=:%s", p.Id(), f.Field, secret.MaskValue(f.Value)))
 			}
 		}
 	} else if ShowOpt.ShowYaml {
 		profileMap := make(map[string]node.Profile)
 		for _, profile := range profiles {
+			secret.MaskAll(&profile)
 			profileMap[profile.Id()] = profile
 		}
 
@@ -48,6 +50,7 @@ func ProfileList(ShowOpt *wwapiv1.GetProfileList) (profileList wwapiv1.ProfileLi
 	} else if ShowOpt.ShowJson {
 		profileMap := make(map[string]node.Profile)
 		for _, profile := range profiles {
+			secret.MaskAll(&profile)
 			profileMap[profile.Id()] = profile
 		}
 
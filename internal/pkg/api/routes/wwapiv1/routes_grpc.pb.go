@@ -48,6 +48,8 @@ type WWApiClient interface {
 	// NodeStatus returns the imaging state for nodes.
 	// This requires warewulfd.
 	NodeStatus(ctx context.Context, in *NodeNames, opts ...grpc.CallOption) (*NodeStatusResponse, error)
+	// NodeCheck checks the consistency of the node database.
+	NodeCheck(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*NodeCheckResponse, error)
 	// Version returns the wwapi version, the api prefix, and the Warewulf
 	// version. This is also useful for testing if the service is up.
 	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionResponse, error)
@@ -169,6 +171,15 @@ func (c *wWApiClient) NodeStatus(ctx context.Context, in *NodeNames, opts ...grp
 	return out, nil
 }
 
+func (c *wWApiClient) NodeCheck(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*NodeCheckResponse, error) {
+	out := new(NodeCheckResponse)
+	err := c.cc.Invoke(ctx, "/wwapi.v1.WWApi/NodeCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *wWApiClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionResponse, error) {
 	out := new(VersionResponse)
 	err := c.cc.Invoke(ctx, "/wwapi.v1.WWApi/Version", in, out, opts...)
@@ -207,6 +218,8 @@ type WWApiServer interface {
 	// NodeStatus returns the imaging state for nodes.
 	// This requires warewulfd.
 	NodeStatus(context.Context, *NodeNames) (*NodeStatusResponse, error)
+	// NodeCheck checks the consistency of the node database.
+	NodeCheck(context.Context, *emptypb.Empty) (*NodeCheckResponse, error)
 	// Version returns the wwapi version, the api prefix, and the Warewulf
 	// version. This is also useful for testing if the service is up.
 	Version(context.Context, *emptypb.Empty) (*VersionResponse, error)
@@ -253,6 +266,9 @@ func (UnimplementedWWApiServer) NodeSet(context.Context, *ConfSetParameter) (*No
 func (UnimplementedWWApiServer) NodeStatus(context.Context, *NodeNames) (*NodeStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NodeStatus not implemented")
 }
+func (UnimplementedWWApiServer) NodeCheck(context.Context, *emptypb.Empty) (*NodeCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NodeCheck not implemented")
+}
 func (UnimplementedWWApiServer) Version(context.Context, *emptypb.Empty) (*VersionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
@@ -485,6 +501,24 @@ func _WWApi_NodeStatus_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WWApi_NodeCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WWApiServer).NodeCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wwapi.v1.WWApi/NodeCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WWApiServer).NodeCheck(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WWApi_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -558,6 +592,10 @@ var WWApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NodeStatus",
 			Handler:    _WWApi_NodeStatus_Handler,
 		},
+		{
+			MethodName: "NodeCheck",
+			Handler:    _WWApi_NodeCheck_Handler,
+		},
 		{
 			MethodName: "Version",
 			Handler:    _WWApi_Version_Handler,
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"net"
+)
+
+// NetworkConf describes a named network. Network devices of nodes
+// which reference the network by its name inherit its netmask,
+// gateway, MTU, DNS servers, VLAN id and address pool unless they set
+// them themselves.
+type NetworkConf struct {
+	Comment string   `yaml:"comment,omitempty"`
+	Network string   `yaml:"network,omitempty"`
+	Gateway string   `yaml:"gateway,omitempty"`
+	MTU     string   `yaml:"mtu,omitempty"`
+	DNS     []string `yaml:"dns,omitempty"`
+	VlanId  string   `yaml:"vlan id,omitempty"`
+	Pool    string   `yaml:"pool,omitempty"`
+}
+
+// Netmask returns the netmask of the network given in CIDR notation,
+// or nil if it is not set or invalid.
+func (network NetworkConf) Netmask() net.IP {
+	_, prefix, err := net.ParseCIDR(network.Network)
+	if err != nil {
+		return nil
+	}
+	if prefix.IP.To4() == nil {
+		return nil
+	}
+	return net.IP(prefix.Mask).To4()
+}
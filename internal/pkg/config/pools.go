@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// AddressPool describes a range of IPv4 addresses from which Warewulf
+// assigns addresses to network devices and IPMI interfaces of nodes
+// which reference the pool by its name.
+//
+// Addresses are allocated from RangeStart to RangeEnd, which default
+// to the first and last host address of Network. A /31 network has no
+// network and broadcast address (RFC 3021), so both of its addresses
+// are allocated, and a /32 network is its single address. The gateway
+// and all Reserved addresses or ranges (given as "first-last") are
+// never allocated.
+type AddressPool struct {
+	Network    string   `yaml:"network"`
+	Gateway    string   `yaml:"gateway,omitempty"`
+	RangeStart string   `yaml:"range start,omitempty"`
+	RangeEnd   string   `yaml:"range end,omitempty"`
+	Reserved   []string `yaml:"reserved,omitempty"`
+}
+
+// Prefix returns the parsed Network of the pool.
+func (pool AddressPool) Prefix() (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(pool.Network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %s: %w", pool.Network, err)
+	}
+	if network.IP.To4() == nil {
+		return nil, fmt.Errorf("invalid network %s: only IPv4 pools are supported", pool.Network)
+	}
+	return network, nil
+}
+
+// Netmask returns the netmask of the pool network.
+func (pool AddressPool) Netmask() net.IP {
+	network, err := pool.Prefix()
+	if err != nil {
+		return nil
+	}
+	return net.IP(network.Mask).To4()
+}
+
+// Range returns the first and the last address which may be allocated
+// from the pool.
+func (pool AddressPool) Range() (start net.IP, end net.IP, err error) {
+	network, err := pool.Prefix()
+	if err != nil {
+		return
+	}
+	ones, _ := network.Mask.Size()
+	pointToPoint := ones >= 31
+	if pool.RangeStart != "" {
+		if start = net.ParseIP(pool.RangeStart).To4(); start == nil || !network.Contains(start) {
+			return nil, nil, fmt.Errorf("invalid range start: %s", pool.RangeStart)
+		}
+	} else {
+		start = network.IP.To4()
+		if !pointToPoint {
+			start = net.IPv4(start[0], start[1], start[2], start[3]+1).To4()
+		}
+	}
+	if pool.RangeEnd != "" {
+		if end = net.ParseIP(pool.RangeEnd).To4(); end == nil || !network.Contains(end) {
+			return nil, nil, fmt.Errorf("invalid range end: %s", pool.RangeEnd)
+		}
+	} else {
+		end = make(net.IP, net.IPv4len)
+		for i := range end {
+			end[i] = network.IP.To4()[i] | ^network.Mask[i]
+		}
+		if !pointToPoint {
+			end[3]--
+		}
+	}
+	if compareIPv4(start, end) > 0 {
+		return nil, nil, fmt.Errorf("invalid range: %s is after %s", start, end)
+	}
+	return
+}
+
+// IsReserved returns true if the address is the gateway of the pool or
+// part of one of its reserved addresses or ranges.
+func (pool AddressPool) IsReserved(addr net.IP) bool {
+	if gateway := net.ParseIP(pool.Gateway); gateway != nil && gateway.Equal(addr) {
+		return true
+	}
+	ip := addr.To4()
+	if ip == nil {
+		return false
+	}
+	for _, reserved := range pool.Reserved {
+		first, last, isRange := strings.Cut(reserved, "-")
+		firstIP := net.ParseIP(strings.TrimSpace(first)).To4()
+		if firstIP == nil {
+			continue
+		}
+		if !isRange {
+			if firstIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		lastIP := net.ParseIP(strings.TrimSpace(last)).To4()
+		if lastIP == nil {
+			continue
+		}
+		if compareIPv4(firstIP, ip) <= 0 && compareIPv4(ip, lastIP) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compareIPv4 compares two IPv4 addresses and returns -1, 0 or 1.
+func compareIPv4(a, b net.IP) int {
+	for i := 0; i < net.IPv4len; i++ {
+		if a[i] < b[i] {
+			return -1
+		} else if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
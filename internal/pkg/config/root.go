@@ -9,6 +9,7 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"net/netip"
 	"os"
@@ -17,7 +18,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/creasty/defaults"
+	"github.com/warewulf/warewulf/internal/pkg/history"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+	"golang.org/x/sys/unix"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,21 +30,23 @@ var cachedConf WarewulfYaml
 // some information about the Warewulf server locally, and has
 // [WarewulfConf], [DHCPConf], [TFTPConf], and [NFSConf] sub-sections.
 type WarewulfYaml struct {
-	Comment         string        `yaml:"comment,omitempty"`
-	Ipaddr          string        `yaml:"ipaddr,omitempty"`
-	Ipaddr6         string        `yaml:"ipaddr6,omitempty"`
-	Netmask         string        `yaml:"netmask,omitempty"`
-	Network         string        `yaml:"network,omitempty"`
-	Ipv6net         string        `yaml:"ipv6net,omitempty"`
-	Fqdn            string        `yaml:"fqdn,omitempty"`
-	Warewulf        *WarewulfConf `yaml:"warewulf,omitempty"`
-	DHCP            *DHCPConf     `yaml:"dhcp,omitempty"`
-	TFTP            *TFTPConf     `yaml:"tftp,omitempty"`
-	NFS             *NFSConf      `yaml:"nfs,omitempty"`
-	SSH             *SSHConf      `yaml:"ssh,omitempty"`
-	MountsContainer []*MountEntry `yaml:"container mounts,omitempty" default:"[{\"source\": \"/etc/resolv.conf\", \"dest\": \"/etc/resolv.conf\"}]"`
-	Paths           *BuildConfig  `yaml:"paths,omitempty"`
-	WWClient        *WWClientConf `yaml:"wwclient,omitempty"`
+	Comment         string                  `yaml:"comment,omitempty"`
+	Ipaddr          string                  `yaml:"ipaddr,omitempty"`
+	Ipaddr6         string                  `yaml:"ipaddr6,omitempty"`
+	Netmask         string                  `yaml:"netmask,omitempty"`
+	Network         string                  `yaml:"network,omitempty"`
+	Ipv6net         string                  `yaml:"ipv6net,omitempty"`
+	Fqdn            string                  `yaml:"fqdn,omitempty"`
+	Warewulf        *WarewulfConf           `yaml:"warewulf,omitempty"`
+	DHCP            *DHCPConf               `yaml:"dhcp,omitempty"`
+	TFTP            *TFTPConf               `yaml:"tftp,omitempty"`
+	NFS             *NFSConf                `yaml:"nfs,omitempty"`
+	SSH             *SSHConf                `yaml:"ssh,omitempty"`
+	MountsContainer []*MountEntry           `yaml:"container mounts,omitempty" default:"[{\"source\": \"/etc/resolv.conf\", \"dest\": \"/etc/resolv.conf\"}]"`
+	Paths           *BuildConfig            `yaml:"paths,omitempty"`
+	WWClient        *WWClientConf           `yaml:"wwclient,omitempty"`
+	AddressPools    map[string]*AddressPool `yaml:"address pools,omitempty"`
+	Networks        map[string]*NetworkConf `yaml:"networks,omitempty"`
 
 	warewulfconf string
 }
@@ -215,22 +220,53 @@ func (config *WarewulfYaml) Dump() ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+/*
+Write the WarewulfYaml to the given file. The file is locked while it is
+written, and every change of the configured warewulf.conf is recorded in
+the configuration history.
+*/
 func (config *WarewulfYaml) PersistToFile(configFile string) error {
 	out, dumpErr := config.Dump()
 	if dumpErr != nil {
 		wwlog.Error("%s", dumpErr)
 		return dumpErr
 	}
-	file, err := os.OpenFile(configFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	file, err := os.OpenFile(configFile, os.O_RDWR|os.O_CREATE, 0o644)
 	if err != nil {
 		wwlog.Error("%s", err)
 		return err
 	}
 	defer file.Close()
-	_, err = file.WriteString(string(out))
+	wwlog.Debug("locking: %s", configFile)
+	if err = unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("could not lock %s: %w", configFile, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(file.Fd()), unix.LOCK_UN)
+	}()
+	previous, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if err = file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = file.Write(out)
 	if err != nil {
 		return err
 	}
 	wwlog.Debug("persisted: %s", configFile)
+	confFile := config.warewulfconf
+	if confFile == "" {
+		confFile = ConfigFile
+	}
+	if config.Paths != nil && history.SameFile(configFile, confFile) {
+		if err = history.Record(config.Paths.HistoryDir(), configFile, previous, out); err != nil {
+			wwlog.Warn("could not record history of %s: %s", configFile, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,88 @@
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// DetachKey ends an attached console session (Ctrl-])
+const DetachKey = 0x1d
+
+var (
+	// ErrNoServer is returned by Attach if the console server isn't
+	// running
+	ErrNoServer = errors.New("console server not available")
+	// ErrNotCaptured is returned by Attach if the console server doesn't
+	// capture the console of the node
+	ErrNotCaptured = errors.New("not captured by the console server")
+)
+
+/*
+Attach attaches to the console of the node captured by the console
+server in dir: the console output is copied to out and in is sent to
+the console until in is closed or DetachKey is read. Before anything is
+copied, ErrNoServer or ErrNotCaptured are returned if the console can't
+be attached to.
+*/
+func Attach(dir, node string, in io.Reader, out io.Writer) error {
+	conn, err := net.Dial("unix", SocketPath(dir))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoServer, err)
+	}
+	defer conn.Close()
+	if _, err := fmt.Fprintf(conn, "attach %s\n", node); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply = strings.TrimSpace(reply); reply != "ok" {
+		if strings.HasPrefix(reply, "error ") {
+			return fmt.Errorf("%s: %w", node, ErrNotCaptured)
+		}
+		return fmt.Errorf("unexpected reply of the console server: %s", reply)
+	}
+
+	output := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, reader)
+		output <- err
+	}()
+	input := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if i := bytes.IndexByte(buf[:n], DetachKey); i >= 0 {
+				_, _ = conn.Write(buf[:i])
+				input <- nil
+				return
+			}
+			if n > 0 {
+				if _, err := conn.Write(buf[:n]); err != nil {
+					input <- err
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				input <- err
+				return
+			}
+		}
+	}()
+	select {
+	case err = <-output:
+	case err = <-input:
+	}
+	return err
+}
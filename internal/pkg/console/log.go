@@ -0,0 +1,216 @@
+// Package console captures the serial consoles of nodes: it keeps the
+// console sessions open, writes their output to per-node log files and
+// lets clients attach to the live sessions.
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogFile returns the log file of the console of the node in dir
+func LogFile(dir, node string) string {
+	return path.Join(dir, node+".log")
+}
+
+/*
+Log writes the output of a console to a log file, every line prefixed
+with the time at which it was received. The file is rotated when it
+exceeds maxSize bytes; keep rotated files are kept as .1 (the newest)
+to .keep (the oldest).
+*/
+type Log struct {
+	file    *os.File
+	path    string
+	maxSize int64
+	keep    int
+	size    int64
+	midLine bool
+	// lineLength is the length of the incomplete line
+	lineLength int
+	// afterCR is set if the last write ended with a carriage return,
+	// so that a following newline doesn't end another line
+	afterCR bool
+}
+
+// OpenLog opens the console log of the node in dir for appending
+func OpenLog(dir, node string, maxSize int64, keep int) (*Log, error) {
+	log := &Log{path: LogFile(dir, node), maxSize: maxSize, keep: keep}
+	if err := log.open(); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func (log *Log) open() (err error) {
+	log.file, err = os.OpenFile(log.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := log.file.Stat()
+	if err != nil {
+		return err
+	}
+	log.size = info.Size()
+	log.midLine = false
+	return nil
+}
+
+// rotate moves the log file to .1, and the rotated files one further,
+// if it exceeds the maximum size
+func (log *Log) rotate() error {
+	if log.maxSize <= 0 || log.size < log.maxSize {
+		return nil
+	}
+	if err := log.file.Close(); err != nil {
+		return err
+	}
+	if log.keep > 0 {
+		for i := log.keep - 1; i >= 1; i-- {
+			err := os.Rename(fmt.Sprintf("%s.%d", log.path, i), fmt.Sprintf("%s.%d", log.path, i+1))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if err := os.Rename(log.path, log.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(log.path); err != nil {
+		return err
+	}
+	return log.open()
+}
+
+// maxLineLength is the length after which a line of console output
+// without line break is continued on the next line of the log
+const maxLineLength = 4096
+
+/*
+Write writes the console output p to the log, prefixing every line
+with the current time. A carriage return ends a line like a newline, so
+that progress output which redraws a line is logged as separate lines,
+but a carriage return followed by a newline ends only one line. Lines
+longer than maxLineLength are split.
+*/
+func (log *Log) Write(p []byte) (int, error) {
+	written := len(p)
+	if log.afterCR && len(p) > 0 && p[0] == '\n' {
+		p = p[1:]
+	}
+	log.afterCR = false
+	for len(p) > 0 {
+		if !log.midLine {
+			if err := log.rotate(); err != nil {
+				return 0, err
+			}
+			n, err := log.file.WriteString(time.Now().UTC().Format(time.RFC3339) + " ")
+			log.size += int64(n)
+			if err != nil {
+				return 0, err
+			}
+			log.lineLength = 0
+		}
+		limit := maxLineLength - log.lineLength
+		var line []byte
+		if i := bytes.IndexAny(p, "\r\n"); i >= 0 && i <= limit {
+			line = append(p[:i:i], '\n')
+			if p[i] == '\r' && i+1 < len(p) && p[i+1] == '\n' {
+				i++
+			} else if p[i] == '\r' && i+1 == len(p) {
+				log.afterCR = true
+			}
+			p = p[i+1:]
+		} else if len(p) >= limit {
+			line = append(p[:limit:limit], '\n')
+			p = p[limit:]
+		} else {
+			line = p
+			p = nil
+		}
+		n, err := log.file.Write(line)
+		log.size += int64(n)
+		log.midLine = line[len(line)-1] != '\n'
+		log.lineLength += n
+		if err != nil {
+			return 0, err
+		}
+	}
+	return written, nil
+}
+
+// Close ends an incomplete line and closes the log file
+func (log *Log) Close() error {
+	if log.midLine {
+		_, _ = log.file.WriteString("\n")
+	}
+	return log.file.Close()
+}
+
+// logFiles returns the log file of the node and its rotated files,
+// oldest first
+func logFiles(dir, node string) ([]string, error) {
+	file := LogFile(dir, node)
+	rotated, err := filepath.Glob(file + ".*")
+	if err != nil {
+		return nil, err
+	}
+	index := func(name string) int {
+		i, _ := strconv.Atoi(strings.TrimPrefix(name, file+"."))
+		return i
+	}
+	sort.Slice(rotated, func(i, j int) bool { return index(rotated[i]) > index(rotated[j]) })
+	return append(rotated, file), nil
+}
+
+/*
+ReadLog writes the logged console output of the node since the given
+time to w, including the rotated log files. A node without log has no
+output.
+*/
+func ReadLog(dir, node string, since time.Time, w io.Writer) error {
+	files, err := logFiles(dir, node)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		file, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		// lines are read without a limit of their length, as the logs of
+		// earlier versions may have very long lines
+		reader := bufio.NewReader(file)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				file.Close()
+				return err
+			}
+			if line == "" {
+				break
+			}
+			stamp, _, _ := strings.Cut(line, " ")
+			if t, err := time.Parse(time.RFC3339, stamp); err == nil && t.Before(since) {
+				continue
+			}
+			if _, err := fmt.Fprintln(w, strings.TrimSuffix(line, "\n")); err != nil {
+				file.Close()
+				return err
+			}
+		}
+		file.Close()
+	}
+	return nil
+}
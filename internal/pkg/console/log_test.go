@@ -0,0 +1,98 @@
+package console
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Log(t *testing.T) {
+	dir := t.TempDir()
+	log, err := OpenLog(dir, "n1", 0, 0)
+	require.NoError(t, err)
+	_, err = log.Write([]byte("Booting\r\nlog"))
+	require.NoError(t, err)
+	_, err = log.Write([]byte("in: \n"))
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	var buf bytes.Buffer
+	require.NoError(t, ReadLog(dir, "n1", time.Time{}, &buf))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ Booting$`, string(lines[0]))
+	assert.Regexp(t, `^\S+ login:$`, string(lines[1]))
+
+	buf.Reset()
+	require.NoError(t, ReadLog(dir, "n1", time.Now().Add(time.Hour), &buf))
+	assert.Empty(t, buf.String())
+
+	buf.Reset()
+	require.NoError(t, ReadLog(dir, "n2", time.Time{}, &buf))
+	assert.Empty(t, buf.String())
+}
+
+func Test_LogRotate(t *testing.T) {
+	dir := t.TempDir()
+	log, err := OpenLog(dir, "n1", 40, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"one", "two", "three", "four"} {
+		_, err = log.Write([]byte(line + " 0123456789012345678901\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, log.Close())
+
+	_, err = os.Stat(path.Join(dir, "n1.log.3"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	var buf bytes.Buffer
+	require.NoError(t, ReadLog(dir, "n1", time.Time{}, &buf))
+	assert.NotContains(t, buf.String(), "one")
+	assert.Regexp(t, `(?s)two.*three.*four`, buf.String())
+}
+
+func Test_LogLines(t *testing.T) {
+	dir := t.TempDir()
+	log, err := OpenLog(dir, "n1", 0, 0)
+	require.NoError(t, err)
+	// progress output redraws the line with carriage returns
+	for _, out := range []string{"10%\r", "\n", "50%\r100%\r", "\ndone\r\r\nlong "} {
+		_, err = log.Write([]byte(out))
+		require.NoError(t, err)
+	}
+	_, err = log.Write(bytes.Repeat([]byte("x"), 2*maxLineLength))
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	var buf bytes.Buffer
+	require.NoError(t, ReadLog(dir, "n1", time.Time{}, &buf))
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		_, text, _ := strings.Cut(line, " ")
+		lines = append(lines, text)
+	}
+	require.Len(t, lines, 8)
+	assert.Equal(t, []string{"10%", "50%", "100%", "done", ""}, lines[:5])
+	// the long line is split
+	assert.True(t, strings.HasPrefix(lines[5], "long x"))
+	for i, length := range []int{maxLineLength, maxLineLength, 5} {
+		assert.Len(t, lines[5+i], length)
+	}
+}
+
+func Test_ReadLogLongLines(t *testing.T) {
+	dir := t.TempDir()
+	// logs written by earlier versions can have lines longer than the
+	// buffer of a scanner
+	long := strings.Repeat("x", 2*1024*1024)
+	require.NoError(t, os.WriteFile(LogFile(dir, "n1"), []byte("2024-01-01T00:00:00Z "+long+"\n2024-01-01T00:00:01Z end"), 0o640))
+
+	var buf bytes.Buffer
+	require.NoError(t, ReadLog(dir, "n1", time.Time{}, &buf))
+	assert.Equal(t, "2024-01-01T00:00:00Z "+long+"\n2024-01-01T00:00:01Z end\n", buf.String())
+}
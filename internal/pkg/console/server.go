@@ -0,0 +1,249 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+// restartDelay is the time to wait before a console session which
+// ended is started again
+var restartDelay = 10 * time.Second
+
+// clientTimeout is the time a write to an attached client may take
+// before the client is dropped, so that a stalled client can't block
+// the logging
+const clientTimeout = 5 * time.Second
+
+// SocketPath returns the path of the socket of the console server in
+// dir
+func SocketPath(dir string) string {
+	return path.Join(dir, "console.sock")
+}
+
+/*
+Server keeps the console sessions of nodes open, writes their output to
+the node logs in Dir and lets clients attach to the sessions through
+the socket at SocketPath(Dir). Sessions which end are started again.
+*/
+type Server struct {
+	Dir     string
+	MaxSize int64
+	Keep    int
+
+	sessions map[string]*session
+}
+
+// session is the console session of a single node
+type session struct {
+	node string
+	open func() (io.ReadWriteCloser, error)
+	log  *Log
+
+	lock    sync.Mutex
+	clients map[net.Conn]bool
+
+	// the input has its own lock so that a console which doesn't read
+	// its input can't block the output
+	stdinLock sync.Mutex
+	stdin     io.Writer
+}
+
+// NewServer returns a console server which logs to dir
+func NewServer(dir string, maxSize int64, keep int) *Server {
+	return &Server{Dir: dir, MaxSize: maxSize, Keep: keep, sessions: make(map[string]*session)}
+}
+
+// Add adds the console of the node, which is connected to by open
+func (server *Server) Add(node string, open func() (io.ReadWriteCloser, error)) {
+	server.sessions[node] = &session{node: node, open: open, clients: make(map[net.Conn]bool)}
+}
+
+/*
+Run starts the console sessions and serves attaching clients until ctx
+is done.
+*/
+func (server *Server) Run(ctx context.Context) error {
+	if err := os.MkdirAll(server.Dir, 0o750); err != nil {
+		return err
+	}
+	for _, s := range server.sessions {
+		var err error
+		if s.log, err = OpenLog(server.Dir, s.node, server.MaxSize, server.Keep); err != nil {
+			return fmt.Errorf("could not open console log of %s: %w", s.node, err)
+		}
+		defer s.log.Close()
+	}
+
+	socket := SocketPath(server.Dir)
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(socket, 0o600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range server.sessions {
+		wg.Add(1)
+		go func(s *session) {
+			defer wg.Done()
+			s.run(ctx)
+		}(s)
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			wwlog.Warn("console server: %s", err)
+			continue
+		}
+		go server.serve(conn)
+	}
+	wg.Wait()
+	os.Remove(socket)
+	return nil
+}
+
+/*
+serve handles a client connection: the client sends "attach NODE", the
+server answers "ok" or "error MESSAGE" and then copies the console
+output to the client and the input of the client to the console.
+*/
+func (server *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	request, err := reader.ReadString('\n')
+	if err != nil {
+		return
+	}
+	verb, node, _ := strings.Cut(strings.TrimSpace(request), " ")
+	s, ok := server.sessions[node]
+	if verb != "attach" || !ok {
+		fmt.Fprintf(conn, "error %s is not captured by the console server\n", node)
+		return
+	}
+	if _, err := fmt.Fprintln(conn, "ok"); err != nil {
+		return
+	}
+	wwlog.Verbose("%s: client attached to console", node)
+	s.attach(conn)
+	defer s.detach(conn)
+	buf := make([]byte, 4096)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			s.input(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	wwlog.Verbose("%s: client detached from console", node)
+}
+
+// run keeps the console session open until ctx is done
+func (s *session) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.connect(ctx); err != nil {
+			wwlog.Warn("%s: console session ended: %s", s.node, err)
+			fmt.Fprintf(s, "\n[console session ended: %s]\n", err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+// connect runs a single console session until it ends or ctx is done
+func (s *session) connect(ctx context.Context) error {
+	conn, err := s.open()
+	if err != nil {
+		return err
+	}
+	wwlog.Verbose("%s: console session started", s.node)
+	s.stdinLock.Lock()
+	s.stdin = conn
+	s.stdinLock.Unlock()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	_, err = io.Copy(s, conn)
+	s.stdinLock.Lock()
+	s.stdin = nil
+	s.stdinLock.Unlock()
+	conn.Close()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("connection closed")
+	}
+	return err
+}
+
+// Write logs the console output and sends it to the attached clients
+func (s *session) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, err := s.log.Write(p); err != nil {
+		wwlog.Error("%s: could not write console log: %s", s.node, err)
+	}
+	for conn := range s.clients {
+		_ = conn.SetWriteDeadline(time.Now().Add(clientTimeout))
+		if _, err := conn.Write(p); err != nil {
+			delete(s.clients, conn)
+			conn.Close()
+		}
+	}
+	return len(p), nil
+}
+
+// input sends the input of a client to the console
+func (s *session) input(p []byte) {
+	s.stdinLock.Lock()
+	defer s.stdinLock.Unlock()
+	if s.stdin != nil {
+		_, _ = s.stdin.Write(p)
+	}
+}
+
+func (s *session) attach(conn net.Conn) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.clients[conn] = true
+}
+
+func (s *session) detach(conn net.Conn) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.clients, conn)
+}
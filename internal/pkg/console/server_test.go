@@ -0,0 +1,99 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer which may be written and read
+// concurrently
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+// loopback is a console which echoes its input after a greeting
+type loopback struct {
+	reader *io.PipeReader
+	writer *io.PipeWriter
+}
+
+func newLoopback(greeting string) *loopback {
+	reader, writer := io.Pipe()
+	go func() { _, _ = writer.Write([]byte(greeting)) }()
+	return &loopback{reader: reader, writer: writer}
+}
+
+func (l *loopback) Read(p []byte) (int, error)  { return l.reader.Read(p) }
+func (l *loopback) Write(p []byte) (int, error) { return l.writer.Write(p) }
+func (l *loopback) Close() error {
+	l.writer.Close()
+	return l.reader.Close()
+}
+
+func Test_Server(t *testing.T) {
+	restartDelay = 10 * time.Millisecond
+	// unix socket paths are limited in length
+	dir, err := os.MkdirTemp("", "console")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := NewServer(dir, 0, 0)
+	server.Add("n1", func() (io.ReadWriteCloser, error) {
+		return newLoopback("hello\n"), nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- server.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(SocketPath(dir))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = Attach(dir, "n2", strings.NewReader(""), io.Discard)
+	assert.ErrorIs(t, err, ErrNotCaptured)
+	err = Attach(t.TempDir(), "n1", strings.NewReader(""), io.Discard)
+	assert.ErrorIs(t, err, ErrNoServer)
+
+	inReader, inWriter := io.Pipe()
+	var out syncBuffer
+	attached := make(chan error)
+	go func() { attached <- Attach(dir, "n1", inReader, &out) }()
+	require.Eventually(t, func() bool {
+		_, err := inWriter.Write([]byte("ping\n"))
+		return err == nil && strings.Contains(out.String(), "ping")
+	}, 5*time.Second, 50*time.Millisecond)
+	_, err = inWriter.Write([]byte{DetachKey})
+	require.NoError(t, err)
+	assert.NoError(t, <-attached)
+
+	cancel()
+	assert.NoError(t, <-done)
+	var log bytes.Buffer
+	require.NoError(t, ReadLog(dir, "n1", time.Time{}, &log))
+	assert.Contains(t, log.String(), " hello\n")
+	assert.Contains(t, log.String(), " ping\n")
+	_, err = os.Stat(SocketPath(dir))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -14,7 +14,8 @@ func Build(name string, buildForce bool) error {
 	wwlog.Info("Building container: %s", name)
 
 	rootfsPath := RootFsDir(name)
-	imagePath := ImageFile(name)
+	format := Format(name)
+	imagePath := FormatImageFile(name, format)
 
 	if !ValidSource(name) {
 		return errors.Errorf("Container does not exist: %s", name)
@@ -38,15 +39,26 @@ func Build(name string, buildForce bool) error {
 		}
 	}
 
-	err := util.BuildFsImage(
-		"VNFS container "+name,
-		rootfsPath,
-		imagePath,
-		[]string{"*"},
-		ignore,
-		// ignore cross-device files
-		true,
-		"newc")
-
-	return err
+	var err error
+	if format != FormatCpio {
+		err = buildMountableImage(name, rootfsPath, format, ignore)
+	} else {
+		err = util.BuildFsImage(
+			"VNFS container "+name,
+			rootfsPath,
+			imagePath,
+			[]string{"*"},
+			ignore,
+			// ignore cross-device files
+			true,
+			"newc")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeBuildState(name); err != nil {
+		wwlog.Warn("Could not record the state of %s at the build: %s", name, err)
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package container
 
 import (
 	"path"
+	"strings"
 
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 )
@@ -11,10 +12,21 @@ func SourceParentDir() string {
 	return conf.Paths.WWChrootdir
 }
 
+// SourceDir returns the directory of a container or, for a name of the
+// form container@snapshot, of a snapshot
 func SourceDir(name string) string {
+	if base, snapshot, ok := strings.Cut(name, "@"); ok {
+		return path.Join(SnapshotParentDir(base), snapshot)
+	}
 	return path.Join(SourceParentDir(), name)
 }
 
+// SnapshotParentDir returns the directory of the snapshots of a
+// container
+func SnapshotParentDir(name string) string {
+	return path.Join(SourceParentDir(), name, "snapshots")
+}
+
 func RootFsDir(name string) string {
 	return path.Join(SourceDir(name), "rootfs")
 }
@@ -0,0 +1,384 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+// Kinds of file changes
+const (
+	FileAdded    = "A"
+	FileRemoved  = "D"
+	FileModified = "M"
+)
+
+// FileChange is a file which differs between two rootfs trees. Details
+// describe changes of the type, mode and owner of a modified file.
+type FileChange struct {
+	Path    string
+	Change  string
+	Details []string
+}
+
+// PackageChange is a package which was added, removed or changed
+// between two rootfs trees. A package can be installed in several
+// versions, e.g. the kernel.
+type PackageChange struct {
+	Name string
+	From []string
+	To   []string
+}
+
+// Diff is the difference between two rootfs trees
+type Diff struct {
+	Files    []FileChange
+	Packages []PackageChange
+}
+
+// fileState is what is compared of a file
+type fileState struct {
+	Mode  uint32 `json:"mode"`
+	Uid   uint32 `json:"uid"`
+	Gid   uint32 `json:"gid"`
+	Size  int64  `json:"size"`
+	Mtime int64  `json:"mtime"`
+	Link  string `json:"link,omitempty"`
+	dev   uint64
+	ino   uint64
+}
+
+/*
+buildState is the state of the rootfs of a container when its image was
+last built, so that the changes since the build can be shown.
+*/
+type buildState struct {
+	Built    time.Time            `json:"built"`
+	Files    map[string]fileState `json:"files"`
+	Packages map[string][]string  `json:"packages,omitempty"`
+}
+
+func buildStateFile(name string) string {
+	return path.Join(SourceDir(name), "build.json.gz")
+}
+
+// scanTree returns the state of all files in rootfs by their path
+// relative to it
+func scanTree(rootfs string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(rootfs, func(location string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootfs, location)
+		if err != nil || relPath == "." {
+			return err
+		}
+		var stat syscall.Stat_t
+		if err := syscall.Lstat(location, &stat); err != nil {
+			return err
+		}
+		state := fileState{
+			Mode:  stat.Mode,
+			Uid:   stat.Uid,
+			Gid:   stat.Gid,
+			Mtime: stat.Mtim.Nano(),
+			dev:   stat.Dev,
+			ino:   stat.Ino,
+		}
+		switch stat.Mode & syscall.S_IFMT {
+		case syscall.S_IFREG:
+			state.Size = stat.Size
+		case syscall.S_IFLNK:
+			if state.Link, err = os.Readlink(location); err != nil {
+				return err
+			}
+		}
+		files["/"+relPath] = state
+		return nil
+	})
+	return files, err
+}
+
+/*
+diffFiles compares the states of two trees. Regular files of the same
+size are compared by sameContent, directories only by their mode and
+owner.
+*/
+func diffFiles(from map[string]fileState, to map[string]fileState, sameContent func(file string, from fileState, to fileState) (bool, error)) ([]FileChange, error) {
+	var changes []FileChange
+	for file, fromState := range from {
+		toState, ok := to[file]
+		if !ok {
+			changes = append(changes, FileChange{Path: file, Change: FileRemoved})
+			continue
+		}
+		var details []string
+		fromType, toType := fromState.Mode&syscall.S_IFMT, toState.Mode&syscall.S_IFMT
+		modified := false
+		if fromType != toType {
+			details = append(details, fmt.Sprintf("type %s -> %s", fileType(fromType), fileType(toType)))
+		} else {
+			if fromState.Mode&07777 != toState.Mode&07777 {
+				details = append(details, fmt.Sprintf("mode %04o -> %04o", fromState.Mode&07777, toState.Mode&07777))
+			}
+			switch fromType {
+			case syscall.S_IFREG:
+				modified = fromState.Size != toState.Size
+				if !modified {
+					same, err := sameContent(file, fromState, toState)
+					if err != nil {
+						return nil, err
+					}
+					modified = !same
+				}
+			case syscall.S_IFLNK:
+				modified = fromState.Link != toState.Link
+			}
+		}
+		if fromState.Uid != toState.Uid || fromState.Gid != toState.Gid {
+			details = append(details, fmt.Sprintf("owner %d:%d -> %d:%d", fromState.Uid, fromState.Gid, toState.Uid, toState.Gid))
+		}
+		if modified || len(details) > 0 {
+			changes = append(changes, FileChange{Path: file, Change: FileModified, Details: details})
+		}
+	}
+	for file := range to {
+		if _, ok := from[file]; !ok {
+			changes = append(changes, FileChange{Path: file, Change: FileAdded})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func fileType(mode uint32) string {
+	switch mode {
+	case syscall.S_IFREG:
+		return "file"
+	case syscall.S_IFDIR:
+		return "directory"
+	case syscall.S_IFLNK:
+		return "symlink"
+	default:
+		return "special"
+	}
+}
+
+// diffPackages compares the installed packages of two trees
+func diffPackages(from map[string][]string, to map[string][]string) (changes []PackageChange) {
+	for name, fromVersions := range from {
+		toVersions, ok := to[name]
+		if !ok || strings.Join(fromVersions, " ") != strings.Join(toVersions, " ") {
+			changes = append(changes, PackageChange{Name: name, From: fromVersions, To: toVersions})
+		}
+	}
+	for name, toVersions := range to {
+		if _, ok := from[name]; !ok {
+			changes = append(changes, PackageChange{Name: name, To: toVersions})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+/*
+packages returns the installed packages of a rootfs with their versions,
+read from the dpkg status file or with rpm, or nil if the rootfs has no
+package database which can be read.
+*/
+func packages(rootfs string) (map[string][]string, error) {
+	installed := make(map[string][]string)
+	if util.IsFile(path.Join(rootfs, "var/lib/dpkg/status")) {
+		if err := dpkgPackages(path.Join(rootfs, "var/lib/dpkg/status"), installed); err != nil {
+			return nil, err
+		}
+	} else if util.IsDir(path.Join(rootfs, "var/lib/rpm")) || util.IsDir(path.Join(rootfs, "usr/lib/sysimage/rpm")) {
+		if _, err := exec.LookPath("rpm"); err != nil {
+			wwlog.Warn("rpm is not installed, can't compare the packages of %s", rootfs)
+			return nil, nil
+		}
+		cmd := exec.Command("rpm", "--root", rootfs, "-qa", "--qf", `%{NAME}.%{ARCH} %|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\n`)
+		out, err := cmd.Output()
+		if err != nil {
+			wwlog.Warn("Could not read the rpm database of %s: %s", rootfs, err)
+			return nil, nil
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			name, version, found := strings.Cut(line, " ")
+			if found {
+				installed[name] = append(installed[name], version)
+			}
+		}
+	} else {
+		return nil, nil
+	}
+	for _, versions := range installed {
+		sort.Strings(versions)
+	}
+	return installed, nil
+}
+
+// dpkgPackages adds the installed packages in a dpkg status file
+func dpkgPackages(statusFile string, installed map[string][]string) error {
+	data, err := os.ReadFile(statusFile)
+	if err != nil {
+		return err
+	}
+	for _, paragraph := range strings.Split(string(data), "\n\n") {
+		fields := make(map[string]string)
+		scanner := bufio.NewScanner(strings.NewReader(paragraph))
+		for scanner.Scan() {
+			key, value, found := strings.Cut(scanner.Text(), ":")
+			if found && !strings.HasPrefix(key, " ") {
+				fields[key] = strings.TrimSpace(value)
+			}
+		}
+		if fields["Package"] == "" || !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+		name := fields["Package"]
+		if fields["Architecture"] != "" {
+			name += ":" + fields["Architecture"]
+		}
+		installed[name] = append(installed[name], fields["Version"])
+	}
+	return nil
+}
+
+/*
+DiffContainers compares the rootfs trees of two containers or
+snapshots. Files which are hard links to each other, as between
+snapshots, aren't read.
+*/
+func DiffContainers(from string, to string) (*Diff, error) {
+	for _, name := range []string{from, to} {
+		if !ValidSource(name) {
+			return nil, fmt.Errorf("container does not exist: %s", name)
+		}
+	}
+	fromRootfs, toRootfs := RootFsDir(from), RootFsDir(to)
+	fromFiles, err := scanTree(fromRootfs)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := scanTree(toRootfs)
+	if err != nil {
+		return nil, err
+	}
+	files, err := diffFiles(fromFiles, toFiles, func(file string, fromState fileState, toState fileState) (bool, error) {
+		if fromState.dev == toState.dev && fromState.ino == toState.ino {
+			return true, nil
+		}
+		return sameContent(path.Join(fromRootfs, file), path.Join(toRootfs, file))
+	})
+	if err != nil {
+		return nil, err
+	}
+	fromPackages, err := packages(fromRootfs)
+	if err != nil {
+		return nil, err
+	}
+	toPackages, err := packages(toRootfs)
+	if err != nil {
+		return nil, err
+	}
+	return &Diff{Files: files, Packages: diffPackages(fromPackages, toPackages)}, nil
+}
+
+/*
+DiffSinceBuild compares the rootfs of a container with its state when
+the image was last built. As the content of the files isn't kept,
+regular files of the same size are modified if their modification time
+changed.
+*/
+func DiffSinceBuild(name string) (*Diff, error) {
+	if !ValidSource(name) {
+		return nil, fmt.Errorf("container does not exist: %s", name)
+	}
+	state, err := readBuildState(name)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no build of %s is recorded, build it first", name)
+	}
+	wwlog.Verbose("Comparing %s with its build at %s", name, state.Built.Format(time.RFC822))
+	rootfs := RootFsDir(name)
+	current, err := scanTree(rootfs)
+	if err != nil {
+		return nil, err
+	}
+	files, err := diffFiles(state.Files, current, func(file string, fromState fileState, toState fileState) (bool, error) {
+		return fromState.Mtime == toState.Mtime, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	currentPackages, err := packages(rootfs)
+	if err != nil {
+		return nil, err
+	}
+	return &Diff{Files: files, Packages: diffPackages(state.Packages, currentPackages)}, nil
+}
+
+// writeBuildState records the state of the rootfs of a container which
+// its image was built from
+func writeBuildState(name string) error {
+	rootfs := RootFsDir(name)
+	files, err := scanTree(rootfs)
+	if err != nil {
+		return err
+	}
+	installed, err := packages(rootfs)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(buildState{Built: time.Now(), Files: files, Packages: installed}); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(buildStateFile(name), buf.Bytes(), 0644)
+}
+
+func readBuildState(name string) (*buildState, error) {
+	f, err := os.Open(buildStateFile(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("build state of %s: %w", name, err)
+	}
+	state := &buildState{}
+	if err := json.NewDecoder(gz).Decode(state); err != nil {
+		return nil, fmt.Errorf("build state of %s: %w", name, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+const dpkgStatus = `Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.1-6
+Description: GNU Bourne Again SHell
+ Bash is an sh-compatible command language interpreter.
+
+Package: vim
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 2:8.2
+
+Package: curl
+Status: install ok installed
+Architecture: amd64
+Version: 7.81.0-1
+`
+
+func Test_DiffContainers(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/etc/hostname", "a")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/etc/removed", "")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/usr/bin/ping", "ping")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/etc/same", "same")
+	env.WriteFile("var/lib/warewulf/chroots/a/rootfs/var/lib/dpkg/status", dpkgStatus)
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/etc/hostname", "b")
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/etc/added", "")
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/usr/bin/ping", "ping")
+	require.NoError(t, syscall.Chmod(env.GetPath("var/lib/warewulf/chroots/b/rootfs/usr/bin/ping"), 04755))
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/etc/same", "same")
+	env.WriteFile("var/lib/warewulf/chroots/b/rootfs/var/lib/dpkg/status",
+		`Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.1-7
+
+Package: vim
+Status: install ok installed
+Architecture: amd64
+Version: 2:8.2
+`)
+
+	_, err := DiffContainers("a", "missing")
+	assert.EqualError(t, err, "container does not exist: missing")
+
+	diff, err := DiffContainers("a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Path: "/etc/added", Change: FileAdded},
+		{Path: "/etc/hostname", Change: FileModified},
+		{Path: "/etc/removed", Change: FileRemoved},
+		{Path: "/usr/bin/ping", Change: FileModified, Details: []string{"mode 0644 -> 4755"}},
+		{Path: "/var/lib/dpkg/status", Change: FileModified},
+	}, diff.Files)
+	assert.Equal(t, []PackageChange{
+		{Name: "bash:amd64", From: []string{"5.1-6"}, To: []string{"5.1-7"}},
+		{Name: "curl:amd64", From: []string{"7.81.0-1"}},
+		{Name: "vim:amd64", To: []string{"2:8.2"}},
+	}, diff.Packages)
+
+	diff, err = DiffContainers("a", "a")
+	require.NoError(t, err)
+	assert.Empty(t, diff.Files)
+	assert.Empty(t, diff.Packages)
+}
+
+func Test_DiffRpm(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.CreateFile("var/lib/warewulf/chroots/a/rootfs/var/lib/rpm/rpmdb.sqlite")
+	env.CreateFile("var/lib/warewulf/chroots/b/rootfs/usr/lib/sysimage/rpm/rpmdb.sqlite")
+
+	// rpm is replaced by a script which lists the packages of a fake
+	// database
+	bin := env.GetPath("bin")
+	env.WriteFile("bin/rpm", `#!/bin/sh
+case "$2" in
+*/a/rootfs) printf 'kernel.x86_64 5.14.0-1\nbash.x86_64 5.1-1\n' ;;
+*) printf 'kernel.x86_64 5.14.0-2\nkernel.x86_64 5.14.0-1\nbash.x86_64 5.1-1\n' ;;
+esac
+`)
+	env.Chmod("bin/rpm", 0755)
+	t.Setenv("PATH", bin+":"+os.Getenv("PATH"))
+
+	diff, err := DiffContainers("a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []PackageChange{
+		{Name: "kernel.x86_64", From: []string{"5.14.0-1"}, To: []string{"5.14.0-1", "5.14.0-2"}},
+	}, diff.Packages)
+}
+
+func Test_DiffSinceBuild(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/hostname", "a")
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/motd", "hello")
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/var/lib/dpkg/status", dpkgStatus)
+
+	_, err := DiffSinceBuild("image")
+	assert.EqualError(t, err, "no build of image is recorded, build it first")
+	require.NoError(t, writeBuildState("image"))
+	diff, err := DiffSinceBuild("image")
+	require.NoError(t, err)
+	assert.Empty(t, diff.Files)
+	assert.Empty(t, diff.Packages)
+
+	// a file of the same size is modified if its modification time
+	// changed
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/hostname", "b")
+	later := time.Now()
+	require.NoError(t, os.Chtimes(env.GetPath("var/lib/warewulf/chroots/image/rootfs/etc/hostname"), later, later))
+	require.NoError(t, os.Remove(env.GetPath("var/lib/warewulf/chroots/image/rootfs/etc/motd")))
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/var/lib/dpkg/status", "")
+	diff, err = DiffSinceBuild("image")
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{
+		{Path: "/etc/hostname", Change: FileModified},
+		{Path: "/etc/motd", Change: FileRemoved},
+		{Path: "/var/lib/dpkg/status", Change: FileModified},
+	}, diff.Files)
+	assert.Equal(t, []PackageChange{
+		{Name: "bash:amd64", From: []string{"5.1-6"}},
+		{Name: "curl:amd64", From: []string{"7.81.0-1"}},
+	}, diff.Packages)
+}
@@ -0,0 +1,172 @@
+package container
+
+import (
+	"context"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/image/v5/docker/archive"
+	"github.com/containers/image/v5/docker/reference"
+	ociarchive "github.com/containers/image/v5/oci/archive"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/types"
+	imgSpecs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/warewulf/warewulf/internal/pkg/oci"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+// Formats which a container can be exported in
+const (
+	ExportOciArchive    = "oci-archive"
+	ExportOciDir        = "oci"
+	ExportDockerArchive = "docker-archive"
+)
+
+// exportTag returns the image name and tag a container is exported as,
+// e.g. rocky-9:latest or rocky-9:snapshot for a snapshot. Container and
+// snapshot names which aren't valid in an OCI reference are rejected.
+func exportTag(name string) (reference.NamedTagged, error) {
+	image, tag, found := strings.Cut(name, "@")
+	if !found {
+		tag = "latest"
+	}
+	named, err := reference.ParseNormalizedNamed("localhost/" + strings.ToLower(image))
+	if err != nil || !reference.IsNameOnly(named) {
+		return nil, fmt.Errorf("%s is not a valid OCI image name", image)
+	}
+	tagged, err := reference.WithTag(named, tag)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid OCI image tag", tag)
+	}
+	return tagged, nil
+}
+
+/*
+architecture returns the architecture of the binaries in rootfs, as used
+in OCI images, from the ELF header of /bin/sh.
+*/
+func architecture(rootfs string) (string, error) {
+	file, err := elf.Open(resolveInRoot(rootfs, "/bin/sh"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	switch file.Machine {
+	case elf.EM_X86_64:
+		return "amd64", nil
+	case elf.EM_386:
+		return "386", nil
+	case elf.EM_AARCH64:
+		return "arm64", nil
+	case elf.EM_ARM:
+		return "arm", nil
+	case elf.EM_PPC64:
+		if file.ByteOrder == binary.LittleEndian {
+			return "ppc64le", nil
+		}
+		return "ppc64", nil
+	case elf.EM_S390:
+		return "s390x", nil
+	case elf.EM_RISCV:
+		return "riscv64", nil
+	case elf.EM_LOONGARCH:
+		return "loong64", nil
+	}
+	return "", fmt.Errorf("unknown machine %s", file.Machine)
+}
+
+// resolveInRoot returns the path of name in rootfs, following symbolic
+// links as if rootfs was the root directory
+func resolveInRoot(rootfs string, name string) string {
+	resolved := "/"
+	parts := strings.Split(name, "/")
+	for links := 0; len(parts) > 0 && links < 40; {
+		part := parts[0]
+		parts = parts[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = path.Dir(resolved)
+			continue
+		}
+		next := path.Join(resolved, part)
+		target, err := os.Readlink(filepath.Join(rootfs, next))
+		if err != nil {
+			resolved = next
+			continue
+		}
+		links++
+		if path.IsAbs(target) {
+			resolved = "/"
+		}
+		parts = append(strings.Split(target, "/"), parts...)
+	}
+	return filepath.Join(rootfs, resolved)
+}
+
+/*
+Export writes the rootfs of a container or snapshot as an OCI image to
+target, which is a file for the archive formats and a directory for an
+OCI layout. The image is tagged with the container name and, for a
+snapshot, the snapshot name as tag. If the container was imported from
+an OCI image, the image it is based on is recorded in the base image
+annotations. The architecture of the image is platform, or, if it is
+empty, the architecture of /bin/sh in the container.
+*/
+func Export(name string, format string, target string, platform string) error {
+	if !ValidSource(name) {
+		return fmt.Errorf("container does not exist: %s", name)
+	}
+	tagged, err := exportTag(name)
+	if err != nil {
+		return fmt.Errorf("can't export %s: %w", name, err)
+	}
+	image, tag := reference.Path(tagged), tagged.Tag()
+	if platform == "" {
+		if platform, err = architecture(RootFsDir(name)); err != nil {
+			return fmt.Errorf("could not determine the architecture of %s, the platform must be given: %w", name, err)
+		}
+	}
+	var dst types.ImageReference
+	switch format {
+	case ExportOciArchive:
+		dst, err = ociarchive.NewReference(target, image+":"+tag)
+	case ExportOciDir:
+		dst, err = layout.NewReference(target, image+":"+tag)
+	case ExportDockerArchive:
+		if util.IsFile(target) {
+			return fmt.Errorf("%s already exists", target)
+		}
+		dst, err = archive.NewReference(target, tagged)
+	default:
+		return fmt.Errorf("unknown export format %s, must be one of %s", format,
+			strings.Join([]string{ExportOciArchive, ExportOciDir, ExportDockerArchive}, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("invalid export target %s: %w", target, err)
+	}
+
+	annotations := map[string]string{
+		imgSpecs.AnnotationTitle:   name,
+		imgSpecs.AnnotationRefName: tag,
+	}
+	source, err := GetSource(name)
+	if err != nil {
+		return err
+	}
+	if source != nil {
+		annotations[imgSpecs.AnnotationBaseImageName] = source.URI
+		annotations[imgSpecs.AnnotationBaseImageDigest] = source.Digest
+	}
+
+	wwlog.Info("Exporting %s for %s to %s", name, platform, target)
+	return oci.Export(context.Background(), RootFsDir(name), dst, platform, []string{"/bin/sh"}, annotations)
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_Export(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/rocky/rootfs/etc/os-release", "9.4")
+	env.WriteFile("var/lib/warewulf/chroots/rocky/rootfs/bin/sh", "")
+	env.WriteFile("var/lib/warewulf/chroots/rocky/source.json", `{"uri": "docker://example.org/rocky:9", "digest": "sha256:1111"}`)
+
+	assert.EqualError(t, Export("missing", ExportOciDir, env.GetPath("out"), ""), "container does not exist: missing")
+	assert.ErrorContains(t, Export("rocky", "tar", env.GetPath("out"), "amd64"), "unknown export format tar")
+	assert.ErrorContains(t, Export("rocky", ExportOciDir, env.GetPath("out"), ""), "could not determine the architecture of rocky")
+
+	// an OCI layout carries the tag and the base image
+	require.NoError(t, Export("rocky", ExportOciDir, env.GetPath("oci"), "amd64"))
+	index := struct {
+		Manifests []struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"manifests"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(env.ReadFile("oci/index.json")), &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "rocky:latest", index.Manifests[0].Annotations["org.opencontainers.image.ref.name"])
+
+	require.NoError(t, Export("rocky", ExportOciArchive, env.GetPath("rocky.oci.tar"), "amd64"))
+	assert.FileExists(t, env.GetPath("rocky.oci.tar"))
+
+	// a docker archive can be imported again
+	archive := env.GetPath("rocky.tar")
+	require.NoError(t, Export("rocky", ExportDockerArchive, archive, "amd64"))
+	assert.ErrorContains(t, Export("rocky", ExportDockerArchive, archive, "amd64"), "already exists")
+	require.NoError(t, ImportDocker(archive, "imported", nil))
+	assert.Equal(t, "9.4", env.ReadFile("var/lib/warewulf/chroots/imported/rootfs/etc/os-release"))
+	info, err := os.Stat(path.Join(RootFsDir("imported"), "bin/sh"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+}
+
+// elfHeader returns the header of an ELF binary for machine
+func elfHeader(t *testing.T, machine elf.Machine) string {
+	header := elf.Header64{
+		Ident:   [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(elf.ELFCLASS64), byte(elf.ELFDATA2LSB), byte(elf.EV_CURRENT)},
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  64,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, header))
+	return buf.String()
+}
+
+func Test_ExportArchitecture(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	// /bin is a link to /usr/bin as in current distributions
+	env.WriteFile("var/lib/warewulf/chroots/arm/rootfs/usr/bin/bash", elfHeader(t, elf.EM_AARCH64))
+	env.Symlink("/usr/bin", "var/lib/warewulf/chroots/arm/rootfs/bin")
+	env.Symlink("bash", "var/lib/warewulf/chroots/arm/rootfs/usr/bin/sh")
+
+	arch, err := architecture(RootFsDir("arm"))
+	require.NoError(t, err)
+	assert.Equal(t, "arm64", arch)
+
+	require.NoError(t, Export("arm", ExportOciDir, env.GetPath("oci"), ""))
+	config := struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(ociConfig(t, env.GetPath("oci"))), &config))
+	assert.Equal(t, "arm64", config.Architecture)
+	assert.Equal(t, "linux", config.OS)
+
+	require.NoError(t, Export("arm", ExportOciDir, env.GetPath("oci-x86"), "amd64"))
+	require.NoError(t, json.Unmarshal([]byte(ociConfig(t, env.GetPath("oci-x86"))), &config))
+	assert.Equal(t, "amd64", config.Architecture)
+}
+
+// ociConfig returns the image config of the only image in an OCI layout
+func ociConfig(t *testing.T, dir string) string {
+	blob := func(digest string) []byte {
+		data, err := os.ReadFile(path.Join(dir, "blobs", strings.Replace(digest, ":", "/", 1)))
+		require.NoError(t, err)
+		return data
+	}
+	index := struct {
+		Manifests []struct {
+			Digest string `json:"digest"`
+		} `json:"manifests"`
+	}{}
+	data, err := os.ReadFile(path.Join(dir, "index.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &index))
+	require.Len(t, index.Manifests, 1)
+	manifest := struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+	}{}
+	require.NoError(t, json.Unmarshal(blob(index.Manifests[0].Digest), &manifest))
+	return string(blob(manifest.Config.Digest))
+}
+
+func Test_exportTag(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		ref  string
+		err  string
+	}{
+		"container":        {name: "Rocky-9", ref: "localhost/rocky-9:latest"},
+		"snapshot":         {name: "rocky-9@before_update", ref: "localhost/rocky-9:before_update"},
+		"colon in name":    {name: "rocky:9", err: "rocky:9 is not a valid OCI image name"},
+		"invalid name":     {name: "-rocky", err: "-rocky is not a valid OCI image name"},
+		"invalid snapshot": {name: "rocky@.snap", err: ".snap is not a valid OCI image tag"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ref, err := exportTag(tt.name)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ref, ref.String())
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+Image formats of a container. A cpio image is unpacked into memory by
+the node, squashfs and erofs images are loop mounted by the dracut boot
+path, so that the node only spends memory on the compressed image and
+the files it changes.
+*/
+const (
+	FormatCpio     = "cpio"
+	FormatSquashfs = "squashfs"
+	FormatErofs    = "erofs"
+)
+
+var Formats = []string{FormatCpio, FormatSquashfs, FormatErofs}
+
+// ValidFormat returns true if format is a known image format
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFile(name string) string {
+	return path.Join(SourceDir(name), "format")
+}
+
+// Format returns the image format of the container, cpio if none is
+// set
+func Format(name string) string {
+	data, err := os.ReadFile(formatFile(name))
+	if err != nil {
+		return FormatCpio
+	}
+	format := strings.TrimSpace(string(data))
+	if !ValidFormat(format) {
+		wwlog.Warn("Unknown image format %s of container %s, using %s", format, name, FormatCpio)
+		return FormatCpio
+	}
+	return format
+}
+
+// SetFormat sets the image format which the container is built in
+func SetFormat(name string, format string) error {
+	if !ValidFormat(format) {
+		return fmt.Errorf("unknown image format %s, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+	if format == FormatCpio {
+		err := os.Remove(formatFile(name))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(formatFile(name), []byte(format+"\n"), 0644)
+}
+
+// FormatImageFile returns the image of the container in the given
+// format
+func FormatImageFile(name string, format string) string {
+	switch format {
+	case FormatSquashfs, FormatErofs:
+		return path.Join(ImageParentDir(), name+"."+format)
+	default:
+		return ImageFile(name)
+	}
+}
+
+/*
+excludedFiles returns the files and directories below rootfsPath which
+aren't in the list of included files, as found by util.FindFilterFiles,
+so that squashfs and erofs images leave out the same files as cpio
+images. Files below an excluded directory aren't listed.
+*/
+func excludedFiles(rootfsPath string, included []string) (excluded []string, err error) {
+	includedSet := make(map[string]bool, len(included))
+	for _, file := range included {
+		includedSet[file] = true
+	}
+	err = filepath.WalkDir(rootfsPath, func(location string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(rootfsPath, location)
+		if err != nil || relPath == "." {
+			return err
+		}
+		if includedSet[relPath] {
+			return nil
+		}
+		excluded = append(excluded, relPath)
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return excluded, err
+}
+
+/*
+buildMountableImage builds a squashfs or erofs image of the rootfs of a
+container. The image is written to a temporary file first, so that
+warewulfd never sends a partial image.
+*/
+func buildMountableImage(name string, rootfsPath string, format string, ignore []string) error {
+	imagePath := FormatImageFile(name, format)
+	if err := os.MkdirAll(path.Dir(imagePath), 0755); err != nil {
+		return fmt.Errorf("failed to create image directory for %s: %s: %w", name, imagePath, err)
+	}
+	files, err := util.FindFilterFiles(rootfsPath, []string{"*"}, ignore, true)
+	if err != nil {
+		return fmt.Errorf("failed discovering files for %s: %s: %w", name, rootfsPath, err)
+	}
+	excluded, err := excludedFiles(rootfsPath, files)
+	if err != nil {
+		return fmt.Errorf("failed discovering files for %s: %s: %w", name, rootfsPath, err)
+	}
+
+	tmpPath := imagePath + ".tmp"
+	defer os.Remove(tmpPath)
+	var cmd *exec.Cmd
+	switch format {
+	case FormatSquashfs:
+		excludesFile, err := os.CreateTemp("", "warewulf-excludes-")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(excludesFile.Name())
+		_, err = excludesFile.WriteString(strings.Join(excluded, "\n") + "\n")
+		if closeErr := excludesFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		cmd = exec.Command("mksquashfs", rootfsPath, tmpPath, "-noappend", "-no-progress", "-ef", excludesFile.Name())
+	case FormatErofs:
+		args := []string{"-zlz4hc"}
+		for _, file := range excluded {
+			args = append(args, "--exclude-path="+file)
+		}
+		cmd = exec.Command("mkfs.erofs", append(args, tmpPath, rootfsPath)...)
+	default:
+		return fmt.Errorf("%s images can't be mounted", format)
+	}
+	wwlog.Debug("Running %s", cmd.Args)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		wwlog.Error("%s failed: %s", cmd.Args[0], out)
+		return fmt.Errorf("failed creating image for %s: %s: %w", name, imagePath, err)
+	}
+	wwlog.Debug("%s: %s", cmd.Args[0], out)
+	if err := os.Rename(tmpPath, imagePath); err != nil {
+		return err
+	}
+	wwlog.Info("Created %s image for %s: %s", format, name, imagePath)
+	return nil
+}
@@ -0,0 +1,89 @@
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_Format(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/etc/hostname")
+
+	assert.Equal(t, FormatCpio, Format("image"))
+	assert.Equal(t, ImageFile("image"), FormatImageFile("image", FormatCpio))
+	assert.Equal(t, filepath.Join(ImageParentDir(), "image.squashfs"), FormatImageFile("image", FormatSquashfs))
+	assert.Equal(t, filepath.Join(ImageParentDir(), "image.erofs"), FormatImageFile("image", FormatErofs))
+
+	require.NoError(t, SetFormat("image", FormatSquashfs))
+	assert.Equal(t, FormatSquashfs, Format("image"))
+	require.NoError(t, SetFormat("image", FormatErofs))
+	assert.Equal(t, FormatErofs, Format("image"))
+	assert.ErrorContains(t, SetFormat("image", "tar"), "unknown image format tar")
+	assert.Equal(t, FormatErofs, Format("image"))
+	require.NoError(t, SetFormat("image", FormatCpio))
+	assert.NoFileExists(t, env.GetPath("var/lib/warewulf/chroots/image/format"))
+	require.NoError(t, SetFormat("image", FormatCpio))
+
+	env.WriteFile("var/lib/warewulf/chroots/image/format", "tar\n")
+	assert.Equal(t, FormatCpio, Format("image"))
+}
+
+func Test_excludedFiles(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/boot/vmlinuz")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/etc/hostname")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/usr/share/GeoIP/data")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/usr/share/doc/README")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/var/cache/dnf/a.rpm")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/var/cache/dnf/b.rpm")
+
+	rootfs := RootFsDir("image")
+	included := []string{"boot", "etc", "etc/hostname", "usr", "usr/share", "usr/share/doc", "usr/share/doc/README", "var", "var/cache", "var/cache/dnf", "var/cache/dnf/b.rpm"}
+	excluded, err := excludedFiles(rootfs, included)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"boot/vmlinuz", "usr/share/GeoIP", "var/cache/dnf/a.rpm"}, excluded)
+}
+
+func Test_BuildSquashfs(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/boot/vmlinuz")
+	env.CreateFile("var/lib/warewulf/chroots/image/rootfs/usr/share/GeoIP/data")
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/warewulf/excludes", "/boot/*\n/usr/share/GeoIP\n")
+
+	// mksquashfs is replaced by a script which records its arguments and
+	// exclude file and writes the image
+	bin := env.GetPath("bin")
+	env.WriteFile("bin/mksquashfs", `#!/bin/sh
+echo "$@" > "$(dirname "$0")/args"
+cat "$6" > "$(dirname "$0")/excludes"
+echo squashfs > "$2"
+`)
+	env.Chmod("bin/mksquashfs", 0755)
+	t.Setenv("PATH", bin+":"+os.Getenv("PATH"))
+
+	require.NoError(t, SetFormat("image", FormatSquashfs))
+	require.NoError(t, Build("image", true))
+	data, err := os.ReadFile(FormatImageFile("image", FormatSquashfs))
+	require.NoError(t, err)
+	assert.Equal(t, "squashfs\n", string(data))
+	assert.NoFileExists(t, FormatImageFile("image", FormatSquashfs)+".tmp")
+	assert.NoFileExists(t, ImageFile("image"))
+	assert.FileExists(t, env.GetPath("var/lib/warewulf/chroots/image/build.json.gz"))
+	args := strings.Fields(env.ReadFile("bin/args"))
+	require.Len(t, args, 6)
+	assert.Equal(t, RootFsDir("image"), args[0])
+	assert.Equal(t, []string{"-noappend", "-no-progress", "-ef"}, args[2:5])
+	assert.Equal(t, "boot/vmlinuz\nusr/share/GeoIP\n", env.ReadFile("bin/excludes"))
+
+	require.NoError(t, DeleteImage("image"))
+	assert.NoFileExists(t, FormatImageFile("image", FormatSquashfs))
+}
@@ -0,0 +1,414 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/storage/drivers/copy"
+	"golang.org/x/sys/unix"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+Snapshot is an earlier state of the rootfs of a container, together
+with the images which were built from it. A snapshot is referred to as
+container@snapshot wherever a container name is expected, e.g. as the
+container of a node. Snapshots are read-only.
+*/
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+// IsSnapshot returns true if name refers to a snapshot of a container
+func IsSnapshot(name string) bool {
+	return strings.Contains(name, "@")
+}
+
+// SnapshotName returns the name which refers to a snapshot of a
+// container
+func SnapshotName(name string, snapshot string) string {
+	return name + "@" + snapshot
+}
+
+func snapshotInfoFile(name string, snapshot string) string {
+	return path.Join(SourceDir(SnapshotName(name, snapshot)), "snapshot.json")
+}
+
+// Snapshots returns the snapshots of a container, the oldest first
+func Snapshots(name string) (snapshots []Snapshot, err error) {
+	entries, err := os.ReadDir(SnapshotParentDir(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !ValidName(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(snapshotInfoFile(name, entry.Name()))
+		if err != nil {
+			wwlog.Verbose("Skipping incomplete snapshot %s: %s", entry.Name(), err)
+			continue
+		}
+		snapshot := Snapshot{}
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("snapshot %s of %s: %w", entry.Name(), name, err)
+		}
+		snapshot.Name = entry.Name()
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+/*
+CreateSnapshot keeps the current rootfs and images of a container as a
+snapshot. Files are copied with reflinks where the file system supports
+them, and files which didn't change since the previous snapshot are
+hard links to it, so that a snapshot only takes up the space of the
+changed files. Without a name, the snapshot is named after the current
+time.
+*/
+func CreateSnapshot(name string, snapshot string, comment string) (string, error) {
+	if IsSnapshot(name) || !ValidSource(name) {
+		return "", fmt.Errorf("container does not exist: %s", name)
+	}
+	return makeSnapshot(name, snapshot, comment, func(dir string, ref string) error {
+		if err := copy.DirCopy(RootFsDir(name), path.Join(dir, "rootfs"), copy.Content, true); err != nil {
+			return fmt.Errorf("could not copy rootfs of %s: %w", name, err)
+		}
+		if err := copyMetadata(SourceDir(name), dir, "format", "source.json", "build.json.gz"); err != nil {
+			return err
+		}
+
+		// only images which are current belong to the snapshot
+		images := 0
+		sources, targets := imageFiles(name), imageFiles(ref)
+		for i, source := range sources {
+			if !util.IsFile(source) {
+				continue
+			}
+			if !util.PathIsNewer(RootFsDir(name), source) {
+				wwlog.Warn("Image %s is older than the rootfs of %s, not kept in the snapshot", source, name)
+				continue
+			}
+			if err := copyImage(source, targets[i]); err != nil {
+				return err
+			}
+			images++
+		}
+		if images == 0 {
+			wwlog.Warn("No current image of %s, build %s before booting it", name, ref)
+		}
+		return nil
+	})
+}
+
+/*
+makeSnapshot creates a snapshot of a container in a temporary directory,
+which fill puts the rootfs and metadata of the snapshot ref in, and
+moves it in place once it is complete.
+*/
+func makeSnapshot(name string, snapshot string, comment string, fill func(dir string, ref string) error) (string, error) {
+	created := time.Now()
+	if snapshot == "" {
+		snapshot = created.Format("20060102-150405")
+	}
+	if !ValidName(snapshot) {
+		return "", fmt.Errorf("snapshot name contains illegal characters: %s", snapshot)
+	}
+	ref := SnapshotName(name, snapshot)
+	dir := SourceDir(ref)
+	if util.IsDir(dir) {
+		return "", fmt.Errorf("snapshot %s of %s already exists", snapshot, name)
+	}
+	previous, err := Snapshots(name)
+	if err != nil {
+		return "", err
+	}
+
+	wwlog.Info("Creating snapshot %s of %s", snapshot, name)
+	tmp := path.Join(SnapshotParentDir(name), "."+snapshot)
+	if err := os.RemoveAll(tmp); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := fill(tmp, ref); err != nil {
+		return "", err
+	}
+	if len(previous) > 0 {
+		last := SnapshotName(name, previous[len(previous)-1].Name)
+		if err := linkUnchanged(path.Join(tmp, "rootfs"), RootFsDir(last)); err != nil {
+			return "", fmt.Errorf("could not link unchanged files of %s: %w", last, err)
+		}
+	}
+	info, err := json.MarshalIndent(Snapshot{Name: snapshot, Created: created, Comment: comment}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path.Join(tmp, "snapshot.json"), info, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", err
+	}
+	return snapshot, nil
+}
+
+/*
+RestoreSnapshot replaces the rootfs and images of a container with the
+ones of a snapshot. The snapshot itself is kept.
+*/
+func RestoreSnapshot(name string, snapshot string) error {
+	ref := SnapshotName(name, snapshot)
+	if !ValidSource(ref) {
+		return fmt.Errorf("snapshot %s of %s does not exist", snapshot, name)
+	}
+	wwlog.Info("Restoring snapshot %s of %s", snapshot, name)
+	restored := path.Join(SourceDir(name), "rootfs.restore")
+	old := path.Join(SourceDir(name), "rootfs.old")
+	for _, dir := range []string{restored, old} {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	if err := copy.DirCopy(RootFsDir(ref), restored, copy.Content, true); err != nil {
+		os.RemoveAll(restored)
+		return fmt.Errorf("could not copy rootfs of %s: %w", ref, err)
+	}
+	if err := os.Rename(RootFsDir(name), old); err != nil {
+		os.RemoveAll(restored)
+		return err
+	}
+	if err := os.Rename(restored, RootFsDir(name)); err != nil {
+		return fmt.Errorf("could not restore %s, the previous rootfs is in %s: %w", name, old, err)
+	}
+	if err := os.RemoveAll(old); err != nil {
+		wwlog.Warn("Could not remove %s: %s", old, err)
+	}
+	for _, file := range []string{formatFile(name), sourceFile(name), buildStateFile(name)} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := copyMetadata(SourceDir(ref), SourceDir(name), "format", "source.json", "build.json.gz"); err != nil {
+		return err
+	}
+
+	images := 0
+	sources, targets := imageFiles(ref), imageFiles(name)
+	for i, target := range targets {
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if !util.IsFile(sources[i]) {
+			continue
+		}
+		if err := copyImage(sources[i], target); err != nil {
+			return err
+		}
+		images++
+	}
+	if images == 0 {
+		wwlog.Warn("Snapshot %s has no image, build %s before booting it", snapshot, name)
+	}
+	return nil
+}
+
+// DeleteSnapshot removes a snapshot and its images
+func DeleteSnapshot(name string, snapshot string) error {
+	ref := SnapshotName(name, snapshot)
+	if !ValidSource(ref) {
+		return fmt.Errorf("snapshot %s of %s does not exist", snapshot, name)
+	}
+	for _, image := range imageFiles(ref) {
+		if err := os.Remove(image); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	wwlog.Verbose("Removing path: %s", SourceDir(ref))
+	return os.RemoveAll(SourceDir(ref))
+}
+
+// RenameSnapshotImages renames the images of a snapshot after its
+// container was renamed
+func RenameSnapshotImages(oldRef string, newRef string) error {
+	sources, targets := imageFiles(oldRef), imageFiles(newRef)
+	for i, source := range sources {
+		if !util.IsFile(source) {
+			continue
+		}
+		if err := os.Rename(source, targets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// imageFiles returns the possible image files of a container in all
+// formats
+func imageFiles(name string) (files []string) {
+	for _, format := range Formats {
+		file := FormatImageFile(name, format)
+		files = append(files, file)
+		if format == FormatCpio {
+			files = append(files, file+".gz")
+		}
+	}
+	return files
+}
+
+// copyImage copies an image, with a reflink if possible, and replaces
+// the target atomically
+func copyImage(source string, target string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	copyWithFileRange, copyWithFileClone := true, true
+	if err := copy.CopyRegular(source, tmp, info, &copyWithFileRange, &copyWithFileClone); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not copy %s: %w", source, err)
+	}
+	return os.Rename(tmp, target)
+}
+
+// copyMetadata copies the metadata files of a container, e.g. its
+// image format, which it has
+func copyMetadata(sourceDir string, targetDir string, files ...string) error {
+	for _, file := range files {
+		data, err := os.ReadFile(path.Join(sourceDir, file))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path.Join(targetDir, file), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+linkUnchanged replaces the files in rootfs which are the same as in the
+rootfs of the previous snapshot by hard links to them. Files are the
+same if their content, modification time, mode, owner and capabilities
+are, as package managers keep the modification times of the files they
+install. Files which are hard linked within rootfs are left alone, as are
+the modification times of the directories.
+*/
+func linkUnchanged(rootfs string, previous string) error {
+	dirTimes := make(map[string]syscall.Stat_t)
+	err := filepath.WalkDir(rootfs, func(location string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		relPath, err := filepath.Rel(rootfs, location)
+		if err != nil {
+			return err
+		}
+		var stat, prevStat syscall.Stat_t
+		if err := syscall.Lstat(location, &stat); err != nil {
+			return err
+		}
+		if stat.Nlink != 1 {
+			return nil
+		}
+		prevLocation := filepath.Join(previous, relPath)
+		if err := syscall.Lstat(prevLocation, &prevStat); err != nil {
+			return nil
+		}
+		if stat.Mode != prevStat.Mode || stat.Size != prevStat.Size || stat.Mtim != prevStat.Mtim ||
+			stat.Uid != prevStat.Uid || stat.Gid != prevStat.Gid ||
+			capability(location) != capability(prevLocation) {
+			return nil
+		}
+		if same, err := sameContent(location, prevLocation); err != nil || !same {
+			return err
+		}
+		dir := filepath.Dir(location)
+		if _, ok := dirTimes[dir]; !ok {
+			var dirStat syscall.Stat_t
+			if err := syscall.Lstat(dir, &dirStat); err != nil {
+				return err
+			}
+			dirTimes[dir] = dirStat
+		}
+		tmp := location + ".wwlink"
+		if err := os.Link(prevLocation, tmp); err != nil {
+			return err
+		}
+		return os.Rename(tmp, location)
+	})
+	if err != nil {
+		return err
+	}
+	for dir, stat := range dirTimes {
+		if err := unix.UtimesNanoAt(unix.AT_FDCWD, dir, []unix.Timespec{unix.Timespec(stat.Atim), unix.Timespec(stat.Mtim)}, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameContent returns true if two files have the same content
+func sameContent(file1 string, file2 string) (bool, error) {
+	f1, err := os.Open(file1)
+	if err != nil {
+		return false, err
+	}
+	defer f1.Close()
+	f2, err := os.Open(file2)
+	if err != nil {
+		return false, err
+	}
+	defer f2.Close()
+	buf1, buf2 := make([]byte, 64*1024), make([]byte, 64*1024)
+	for {
+		n1, err1 := io.ReadFull(f1, buf1)
+		n2, err2 := io.ReadFull(f2, buf2)
+		if n1 != n2 || !bytes.Equal(buf1[:n1], buf2[:n2]) {
+			return false, nil
+		}
+		if err1 == io.EOF || err1 == io.ErrUnexpectedEOF {
+			return err2 == err1, nil
+		} else if err1 != nil {
+			return false, err1
+		} else if err2 != nil {
+			return false, err2
+		}
+	}
+}
+
+// capability returns the file capabilities of a file
+func capability(file string) string {
+	buf := make([]byte, 256)
+	n, err := unix.Lgetxattr(file, "security.capability", buf)
+	if err != nil {
+		return ""
+	}
+	return string(buf[:n])
+}
@@ -0,0 +1,99 @@
+package container
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func inode(t *testing.T, file string) uint64 {
+	var stat syscall.Stat_t
+	require.NoError(t, syscall.Stat(file, &stat))
+	return stat.Ino
+}
+
+// buildImage writes a fake cpio image which is newer than the rootfs
+func buildImage(t *testing.T, env *testenv.TestEnv, content string) {
+	env.WriteFile("srv/warewulf/container/image.img", content)
+	env.WriteFile("srv/warewulf/container/image.img.gz", content+" gz")
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(ImageFile("image"), future, future))
+	require.NoError(t, os.Chtimes(ImageFile("image")+".gz", future, future))
+}
+
+func Test_Snapshot(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/os-release", "v1")
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/usr/bin/tool", "tool")
+	buildImage(t, env, "image v1")
+
+	snapshot, err := CreateSnapshot("image", "s1", "before update")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", snapshot)
+	assert.True(t, ValidSource("image@s1"))
+	assert.True(t, IsSnapshot("image@s1"))
+	assert.False(t, IsWriteAble("image@s1"))
+	assert.Equal(t, "v1", env.ReadFile("var/lib/warewulf/chroots/image/snapshots/s1/rootfs/etc/os-release"))
+	assert.Equal(t, env.GetPath("var/lib/warewulf/chroots/image/rootfs"), RootFsDir("image"))
+	assert.Equal(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s1/rootfs"), RootFsDir("image@s1"))
+	assert.Equal(t, "image v1", env.ReadFile("srv/warewulf/container/image@s1.img"))
+	assert.Equal(t, "image v1 gz", env.ReadFile("srv/warewulf/container/image@s1.img.gz"))
+	_, err = CreateSnapshot("image", "s1", "")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = CreateSnapshot("image@s1", "s2", "")
+	assert.Error(t, err)
+	_, err = CreateSnapshot("image", "a/b", "")
+	assert.ErrorContains(t, err, "illegal characters")
+
+	// an update changes one file, the other one is shared between the
+	// snapshots
+	env.WriteFile("var/lib/warewulf/chroots/image/rootfs/etc/os-release", "v2")
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(ImageFile("image"), past, past))
+	require.NoError(t, os.Chtimes(ImageFile("image")+".gz", past, past))
+	_, err = CreateSnapshot("image", "s2", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", env.ReadFile("var/lib/warewulf/chroots/image/snapshots/s2/rootfs/etc/os-release"))
+	assert.Equal(t,
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s1/rootfs/usr/bin/tool")),
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s2/rootfs/usr/bin/tool")))
+	assert.NotEqual(t,
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s1/rootfs/etc/os-release")),
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s2/rootfs/etc/os-release")))
+	assert.NotEqual(t,
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/rootfs/usr/bin/tool")),
+		inode(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s2/rootfs/usr/bin/tool")))
+	assert.NoFileExists(t, env.GetPath("srv/warewulf/container/image@s2.img"), "outdated image")
+
+	snapshots, err := Snapshots("image")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "s1", snapshots[0].Name)
+	assert.Equal(t, "before update", snapshots[0].Comment)
+	assert.Equal(t, "s2", snapshots[1].Name)
+
+	require.NoError(t, RestoreSnapshot("image", "s1"))
+	assert.Equal(t, "v1", env.ReadFile("var/lib/warewulf/chroots/image/rootfs/etc/os-release"))
+	assert.Equal(t, "image v1", env.ReadFile("srv/warewulf/container/image.img"))
+	assert.Equal(t, "v1", env.ReadFile("var/lib/warewulf/chroots/image/snapshots/s1/rootfs/etc/os-release"))
+	assert.NoDirExists(t, env.GetPath("var/lib/warewulf/chroots/image/rootfs.old"))
+	assert.Error(t, RestoreSnapshot("image", "s3"))
+
+	require.NoError(t, DeleteSnapshot("image", "s1"))
+	assert.NoDirExists(t, env.GetPath("var/lib/warewulf/chroots/image/snapshots/s1"))
+	assert.NoFileExists(t, env.GetPath("srv/warewulf/container/image@s1.img"))
+	assert.Error(t, DeleteSnapshot("image", "s1"))
+	snapshots, err = Snapshots("image")
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+
+	sources, err := ListSources()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image"}, sources)
+}
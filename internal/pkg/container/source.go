@@ -0,0 +1,145 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/containers/image/v5/types"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/oci"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+Source records which OCI image a container or snapshot was imported
+from: the URI it was pulled from, the digest of the image manifest and
+the time of the import.
+*/
+type Source struct {
+	URI      string    `json:"uri"`
+	Digest   string    `json:"digest"`
+	Imported time.Time `json:"imported"`
+}
+
+func sourceFile(name string) string {
+	return path.Join(SourceDir(name), "source.json")
+}
+
+// GetSource returns where a container was imported from, or nil if it
+// wasn't imported from an OCI image
+func GetSource(name string) (*Source, error) {
+	data, err := os.ReadFile(sourceFile(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	source := &Source{}
+	if err := json.Unmarshal(data, source); err != nil {
+		return nil, fmt.Errorf("source of %s: %w", name, err)
+	}
+	return source, nil
+}
+
+func writeSource(dir string, source Source) error {
+	data, err := json.MarshalIndent(source, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(dir, "source.json"), data, 0644)
+}
+
+// remoteDigest returns the digest of the manifest of an OCI image
+var remoteDigest = func(uri string, sCtx *types.SystemContext) (string, error) {
+	p, err := oci.NewPuller(
+		oci.OptSetBlobCachePath(warewulfconf.Get().Paths.OciBlobCachedir()),
+		oci.OptSetSystemContext(sCtx),
+	)
+	if err != nil {
+		return "", err
+	}
+	return p.GenerateID(context.Background(), uri)
+}
+
+// pullImage unpacks an OCI image into dst and returns the digest of its
+// manifest
+var pullImage = func(uri string, dst string, sCtx *types.SystemContext) (string, error) {
+	ociBlobCacheDir := warewulfconf.Get().Paths.OciBlobCachedir()
+	if err := os.MkdirAll(ociBlobCacheDir, 0755); err != nil {
+		return "", err
+	}
+	p, err := oci.NewPuller(
+		oci.OptSetBlobCachePath(ociBlobCacheDir),
+		oci.OptSetSystemContext(sCtx),
+	)
+	if err != nil {
+		return "", err
+	}
+	digest, err := p.GenerateID(context.Background(), uri)
+	if err != nil {
+		return "", err
+	}
+	return digest, p.Pull(context.Background(), uri, dst)
+}
+
+/*
+Update pulls the OCI image a container was imported from again, if its
+digest changed since the container or any of its snapshots was pulled,
+into a new snapshot of the container. The container itself is left
+alone, so that nodes only move to the update when they are set to the
+snapshot or it is restored. Update returns the name of the new
+snapshot, or an empty name if the container is up to date.
+*/
+func Update(name string, sCtx *types.SystemContext) (string, error) {
+	if IsSnapshot(name) || !ValidSource(name) {
+		return "", fmt.Errorf("container does not exist: %s", name)
+	}
+	source, err := GetSource(name)
+	if err != nil {
+		return "", err
+	}
+	if source == nil {
+		return "", fmt.Errorf("container %s was not imported from an OCI image", name)
+	}
+	digest, err := remoteDigest(source.URI, sCtx)
+	if err != nil {
+		return "", fmt.Errorf("could not get the digest of %s: %w", source.URI, err)
+	}
+	if source.Digest == digest {
+		wwlog.Info("%s is up to date with %s", name, source.URI)
+		return "", nil
+	}
+	snapshots, err := Snapshots(name)
+	if err != nil {
+		return "", err
+	}
+	for _, snapshot := range snapshots {
+		ref := SnapshotName(name, snapshot.Name)
+		if s, err := GetSource(ref); err == nil && s != nil && s.Digest == digest {
+			wwlog.Info("%s is up to date with %s", ref, source.URI)
+			return "", nil
+		}
+	}
+
+	wwlog.Info("Pulling %s (%s) into a new snapshot of %s", source.URI, digest, name)
+	return makeSnapshot(name, "", "update from "+source.URI, func(dir string, ref string) error {
+		rootfs := path.Join(dir, "rootfs")
+		if err := os.MkdirAll(rootfs, 0755); err != nil {
+			return err
+		}
+		pulled, err := pullImage(source.URI, rootfs, sCtx)
+		if err != nil {
+			return fmt.Errorf("could not pull %s: %w", source.URI, err)
+		}
+		if err := copyMetadata(SourceDir(name), dir, "format"); err != nil {
+			return err
+		}
+		return writeSource(dir, Source{URI: source.URI, Digest: pulled, Imported: time.Now()})
+	})
+}
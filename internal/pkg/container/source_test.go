@@ -0,0 +1,82 @@
+package container
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/containers/image/v5/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+// fakeRegistry replaces pulling from a registry by an image which only
+// contains /etc/os-release
+func fakeRegistry(t *testing.T, digest *string, release *string) {
+	oldDigest, oldPull := remoteDigest, pullImage
+	t.Cleanup(func() { remoteDigest, pullImage = oldDigest, oldPull })
+	remoteDigest = func(uri string, sCtx *types.SystemContext) (string, error) {
+		return *digest, nil
+	}
+	pullImage = func(uri string, dst string, sCtx *types.SystemContext) (string, error) {
+		if err := os.MkdirAll(path.Join(dst, "etc"), 0755); err != nil {
+			return "", err
+		}
+		return *digest, os.WriteFile(path.Join(dst, "etc/os-release"), []byte(*release), 0644)
+	}
+}
+
+func Test_Source(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	digest, release := "sha256:1111", "9.4"
+	fakeRegistry(t, &digest, &release)
+
+	require.NoError(t, ImportDocker("docker://example.org/rocky:9", "rocky", nil))
+	assert.Equal(t, "9.4", env.ReadFile("var/lib/warewulf/chroots/rocky/rootfs/etc/os-release"))
+	source, err := GetSource("rocky")
+	require.NoError(t, err)
+	require.NotNil(t, source)
+	assert.Equal(t, "docker://example.org/rocky:9", source.URI)
+	assert.Equal(t, "sha256:1111", source.Digest)
+	assert.False(t, source.Imported.IsZero())
+
+	env.WriteFile("var/lib/warewulf/chroots/local/rootfs/bin/sh", "")
+	source, err = GetSource("local")
+	assert.NoError(t, err)
+	assert.Nil(t, source)
+	_, err = Update("local", nil)
+	assert.EqualError(t, err, "container local was not imported from an OCI image")
+	_, err = Update("missing", nil)
+	assert.EqualError(t, err, "container does not exist: missing")
+
+	snapshot, err := Update("rocky", nil)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot, "up to date")
+
+	digest, release = "sha256:2222", "9.5"
+	snapshot, err = Update("rocky", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, snapshot)
+	ref := SnapshotName("rocky", snapshot)
+	assert.Equal(t, "9.5", env.ReadFile("var/lib/warewulf/chroots/rocky/snapshots/"+snapshot+"/rootfs/etc/os-release"))
+	assert.Equal(t, "9.4", env.ReadFile("var/lib/warewulf/chroots/rocky/rootfs/etc/os-release"))
+	source, err = GetSource(ref)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:2222", source.Digest)
+	snapshots, err := Snapshots("rocky")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "update from docker://example.org/rocky:9", snapshots[0].Comment)
+
+	snapshot, err = Update("rocky", nil)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot, "the snapshot is up to date")
+
+	require.NoError(t, RestoreSnapshot("rocky", snapshots[0].Name))
+	source, err = GetSource("rocky")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:2222", source.Digest)
+	assert.Equal(t, "9.5", env.ReadFile("var/lib/warewulf/chroots/rocky/rootfs/etc/os-release"))
+}
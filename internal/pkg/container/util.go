@@ -3,6 +3,7 @@ package container
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -54,8 +55,11 @@ func DoesSourceExist(name string) bool {
 	return util.IsDir(fullPath)
 }
 
+// ValidSource returns true if name is an existing container or
+// snapshot of a container
 func ValidSource(name string) bool {
-	if !ValidName(name) {
+	base, snapshot, isSnapshot := strings.Cut(name, "@")
+	if !ValidName(base) || (isSnapshot && !ValidName(snapshot)) {
 		return false
 	}
 
@@ -91,26 +95,37 @@ func Duplicate(name string, destination string) error {
 }
 
 /*
-Delete the image of a container
+Delete the images of a container in all formats
 */
 func DeleteImage(name string) error {
-	imageFile := ImageFile(name)
-	if util.IsFile(imageFile) {
+	found := false
+	for _, format := range Formats {
+		imageFile := FormatImageFile(name, format)
+		if !util.IsFile(imageFile) {
+			continue
+		}
+		found = true
 		wwlog.Verbose("removing %s for container %s", imageFile, name)
-		errImg := os.Remove(imageFile)
-		wwlog.Verbose("removing %s for container %s", imageFile+".gz", name)
-		errGz := os.Remove(imageFile + ".gz")
-		if errImg != nil {
-			return errors.Errorf("Problems delete %s for container %s: %s\n", imageFile, name, errImg)
+		if err := os.Remove(imageFile); err != nil {
+			return errors.Errorf("Problems delete %s for container %s: %s\n", imageFile, name, err)
+		}
+		if format != FormatCpio {
+			continue
 		}
-		if errGz != nil {
-			return errors.Errorf("Problems delete %s for container %s: %s\n", imageFile+".gz", name, errGz)
+		wwlog.Verbose("removing %s for container %s", imageFile+".gz", name)
+		if err := os.Remove(imageFile + ".gz"); err != nil {
+			return errors.Errorf("Problems delete %s for container %s: %s\n", imageFile+".gz", name, err)
 		}
-		return nil
 	}
-	return errors.Errorf("Image %s of container %s doesn't exist\n", imageFile, name)
+	if !found {
+		return errors.Errorf("Image %s of container %s doesn't exist\n", ImageFile(name), name)
+	}
+	return nil
 }
 
 func IsWriteAble(name string) bool {
+	if IsSnapshot(name) {
+		return false
+	}
 	return !util.IsFile(filepath.Join(SourceDir(name), "readonly"))
 }
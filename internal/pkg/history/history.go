@@ -0,0 +1,236 @@
+// Package history records revisions of the Warewulf configuration
+// files (nodes.conf and warewulf.conf) so that changes can be
+// reviewed and rolled back.
+//
+// Every revision is stored as a yaml document in a per-file directory
+// below the history directory and holds the complete file content, a
+// unified diff against the previous revision and some information about
+// who changed the file.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/sys/unix"
+	"gopkg.in/yaml.v3"
+
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+var ErrNoRevision = errors.New("revision not found")
+
+// Revision describes a recorded change of a configuration file.
+type Revision struct {
+	Id      int       `yaml:"id"`
+	File    string    `yaml:"file"`
+	Time    time.Time `yaml:"time"`
+	User    string    `yaml:"user"`
+	Command string    `yaml:"command"`
+	Diff    string    `yaml:"diff,omitempty"`
+	Content string    `yaml:"content"`
+}
+
+// SameFile returns true if both paths refer to the same file once they
+// are made absolute and cleaned. History is only recorded for the
+// configured configuration files, not for copies written elsewhere.
+func SameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// revisionDir returns the directory in which the revisions of the
+// given configuration file are stored.
+func revisionDir(historyDir, file string) string {
+	return path.Join(historyDir, filepath.Base(file))
+}
+
+func revisionFile(historyDir, file string, id int) string {
+	return path.Join(revisionDir(historyDir, file), fmt.Sprintf("%06d.yaml", id))
+}
+
+// Record stores a new revision for the given configuration file if
+// content differs from previous. If there is no history for the file yet,
+// previous is recorded as the initial revision first, so that the very
+// first change can be rolled back as well.
+func Record(historyDir, file string, previous, content []byte) error {
+	if string(previous) == string(content) {
+		return nil
+	}
+	ids, err := listIds(historyDir, file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(revisionDir(historyDir, file), 0o750); err != nil {
+		return err
+	}
+	next := 1
+	if len(ids) > 0 {
+		next = ids[len(ids)-1] + 1
+	} else if len(previous) > 0 {
+		initial := Revision{
+			Id:      next,
+			File:    file,
+			Time:    time.Now(),
+			Command: "initial",
+			Content: string(previous),
+		}
+		if err := writeRevision(historyDir, initial); err != nil {
+			return err
+		}
+		next++
+	}
+	diff, err := Diff(filepath.Base(file), string(previous), string(content))
+	if err != nil {
+		return err
+	}
+	rev := Revision{
+		Id:      next,
+		File:    file,
+		Time:    time.Now(),
+		User:    InvokingUser(),
+		Command: strings.Join(os.Args, " "),
+		Diff:    diff,
+		Content: string(content),
+	}
+	wwlog.Debug("recording revision %d of %s", rev.Id, file)
+	return writeRevision(historyDir, rev)
+}
+
+func writeRevision(historyDir string, rev Revision) error {
+	out, err := yaml.Marshal(rev)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(revisionFile(historyDir, rev.File, rev.Id), out, 0o640)
+}
+
+// List returns all recorded revisions of the given configuration file,
+// ordered from the oldest to the newest one.
+func List(historyDir, file string) (revisions []Revision, err error) {
+	ids, err := listIds(historyDir, file)
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		rev, err := Get(historyDir, file, id)
+		if err != nil {
+			return revisions, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return
+}
+
+// Get returns the revision with the given id, or ErrNoRevision if no
+// such revision was recorded.
+func Get(historyDir, file string, id int) (rev Revision, err error) {
+	data, err := os.ReadFile(revisionFile(historyDir, file, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return rev, fmt.Errorf("%s: %d: %w", filepath.Base(file), id, ErrNoRevision)
+	} else if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(data, &rev)
+	return
+}
+
+// Latest returns the id of the newest recorded revision, or 0 if there
+// is no history for the file.
+func Latest(historyDir, file string) (int, error) {
+	ids, err := listIds(historyDir, file)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[len(ids)-1], nil
+}
+
+func listIds(historyDir, file string) (ids []int, err error) {
+	entries, err := os.ReadDir(revisionDir(historyDir, file))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		if name == entry.Name() {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Diff returns a unified diff between two versions of a file.
+func Diff(name, a, b string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+}
+
+// Restore writes the content of the given revision back to the
+// configuration file and records this as a new revision. The file is
+// locked while it is written.
+func Restore(historyDir, file string, id int) error {
+	rev, err := Get(historyDir, file, id)
+	if err != nil {
+		return err
+	}
+	handle, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	if err = unix.Flock(int(handle.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("could not lock %s: %w", file, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(handle.Fd()), unix.LOCK_UN)
+	}()
+	previous, err := io.ReadAll(handle)
+	if err != nil {
+		return err
+	}
+	if err = handle.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = handle.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err = handle.WriteString(rev.Content); err != nil {
+		return err
+	}
+	wwlog.Verbose("restored revision %d of %s", id, file)
+	return Record(historyDir, file, previous, []byte(rev.Content))
+}
+
+// InvokingUser returns the name of the user who changed the
+// configuration, preferring the user who called sudo.
+func InvokingUser() string {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		return sudoUser
+	}
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return strconv.Itoa(os.Getuid())
+}
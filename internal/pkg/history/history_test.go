@@ -0,0 +1,53 @@
+package history
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Record(t *testing.T) {
+	historyDir := t.TempDir()
+	file := path.Join(t.TempDir(), "nodes.conf")
+
+	assert.NoError(t, Record(historyDir, file, []byte("a: 1\n"), []byte("a: 1\n")))
+	revisions, err := List(historyDir, file)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 0, "unchanged content must not be recorded")
+
+	assert.NoError(t, Record(historyDir, file, []byte("a: 1\n"), []byte("a: 2\n")))
+	assert.NoError(t, Record(historyDir, file, []byte("a: 2\n"), []byte("a: 3\n")))
+	revisions, err = List(historyDir, file)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 3)
+	assert.Equal(t, "initial", revisions[0].Command)
+	assert.Equal(t, "a: 1\n", revisions[0].Content)
+	assert.Equal(t, "a: 3\n", revisions[2].Content)
+	assert.Contains(t, revisions[2].Diff, "-a: 2\n+a: 3\n")
+
+	latest, err := Latest(historyDir, file)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, latest)
+
+	_, err = Get(historyDir, file, 4)
+	assert.ErrorIs(t, err, ErrNoRevision)
+}
+
+func Test_Restore(t *testing.T) {
+	historyDir := t.TempDir()
+	file := path.Join(t.TempDir(), "warewulf.conf")
+	assert.NoError(t, os.WriteFile(file, []byte("a: 2\n"), 0o644))
+	assert.NoError(t, Record(historyDir, file, []byte("a: 1\n"), []byte("a: 2\n")))
+
+	assert.NoError(t, Restore(historyDir, file, 1))
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "a: 1\n", string(content))
+
+	revisions, err := List(historyDir, file)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 3)
+	assert.Equal(t, "a: 1\n", revisions[2].Content)
+}
@@ -59,6 +59,8 @@ func expand_iterate(list []string) ([]string, int) {
 					}
 				}
 			}
+		} else {
+			ret = append(ret, i)
 		}
 	}
 	return ret, count
@@ -26,6 +26,10 @@ func Test_Mixed_Range_Comma(t *testing.T) {
 	assert.Equal(t, []string{"node1", "node2", "node3"}, Expand([]string{"node[1,2-3]"}))
 }
 
+func Test_Mixed_Range_Plain(t *testing.T) {
+	assert.Equal(t, []string{"node1", "node2", "~@profile:gpu"}, Expand([]string{"node[1-2]", "~@profile:gpu"}))
+}
+
 // not currently supported
 //
 // func Test_External_Comma(t *testing.T) {
@@ -0,0 +1,70 @@
+package ipmi
+
+import "github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+
+// commands of the chassis network function
+const (
+	cmdGetChassisStatus     = 0x01
+	cmdChassisControl       = 0x02
+	cmdSetSystemBootOptions = 0x08
+)
+
+// actions of ChassisControl
+const (
+	ChassisPowerDown  = 0x00
+	ChassisPowerUp    = 0x01
+	ChassisPowerCycle = 0x02
+	ChassisHardReset  = 0x03
+	ChassisSoftOff    = 0x05
+)
+
+// devices of SetBootDevice
+const (
+	BootDevicePXE  = 0x04
+	BootDeviceDisk = 0x08
+	BootDeviceBIOS = 0x18
+)
+
+// parameters of the system boot options
+const (
+	bootParamInfoAck = 0x04
+	bootParamFlags   = 0x05
+)
+
+// ChassisControl powers the chassis up or down, cycles or resets it
+func (client *Client) ChassisControl(action byte) error {
+	_, err := client.Command(NetFnChassis, cmdChassisControl, []byte{action})
+	return err
+}
+
+// PowerOn returns whether the system power is on
+func (client *Client) PowerOn() (bool, error) {
+	data, err := client.Command(NetFnChassis, cmdGetChassisStatus, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(data) < 1 {
+		return false, rmcp.ErrShortPacket
+	}
+	return data[0]&0x01 != 0, nil
+}
+
+/*
+SetBootDevice sets the device the system boots from next, for every
+boot if persistent, and in EFI mode if efi.
+*/
+func (client *Client) SetBootDevice(device byte, persistent, efi bool) error {
+	// acknowledge the previous boot info, as ipmitool does
+	if _, err := client.Command(NetFnChassis, cmdSetSystemBootOptions, []byte{bootParamInfoAck, 0x01, 0x01}); err != nil {
+		return err
+	}
+	flags := byte(0x80)
+	if persistent {
+		flags |= 0x40
+	}
+	if efi {
+		flags |= 0x20
+	}
+	_, err := client.Command(NetFnChassis, cmdSetSystemBootOptions, []byte{bootParamFlags, flags, device, 0x00, 0x00, 0x00})
+	return err
+}
@@ -0,0 +1,285 @@
+package ipmi
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+)
+
+// privilege levels of a session
+const (
+	PrivilegeUser          = 0x02
+	PrivilegeOperator      = 0x03
+	PrivilegeAdministrator = 0x04
+)
+
+// commands of the App network function which manage sessions and
+// payloads
+const (
+	cmdGetDeviceID         = 0x01
+	cmdSetSessionPrivilege = 0x3b
+	cmdCloseSession        = 0x3c
+	cmdActivatePayload     = 0x48
+	cmdDeactivatePayload   = 0x49
+)
+
+var (
+	// requestTimeout is the time to wait for a response before a request
+	// is sent again
+	requestTimeout = time.Second
+	// requestRetries is the number of times a request is sent again
+	requestRetries = 3
+)
+
+/*
+Client is a session with a BMC. Commands must not be sent concurrently,
+except from an active SOL payload.
+*/
+type Client struct {
+	conn      net.Conn
+	address   string
+	username  []byte
+	password  []byte
+	privilege byte
+	consoleID uint32
+	bmcID     uint32
+	keys      *rmcp.Keys
+	deadline  time.Time
+
+	// lock protects the sequence numbers and writes to conn
+	lock  sync.Mutex
+	seq   uint32
+	rqSeq byte
+}
+
+/*
+Dial opens a session with administrator privilege with the BMC at
+address (host:port). If timeout isn't zero, establishing the session
+and all commands of the client must be completed within timeout.
+*/
+func Dial(address, username, password string, timeout time.Duration) (*Client, error) {
+	if len(username) > 16 {
+		return nil, fmt.Errorf("user name longer than 16 characters")
+	}
+	if len(password) > 20 {
+		return nil, fmt.Errorf("password longer than 20 characters")
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	client := &Client{
+		conn:      conn,
+		address:   address,
+		username:  []byte(username),
+		password:  []byte(password),
+		privilege: PrivilegeAdministrator,
+	}
+	if timeout > 0 {
+		client.deadline = time.Now().Add(timeout)
+	}
+	if err := client.open(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// open establishes the session: the algorithms are negotiated, the
+// user is authenticated with RAKP and the session keys are derived
+func (client *Client) open() error {
+	random := make([]byte, 20)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	client.consoleID = binary.LittleEndian.Uint32(random[16:]) | 1
+	consoleID := binary.LittleEndian.AppendUint32(nil, client.consoleID)
+	consoleRandom := random[:16]
+
+	request := append([]byte{0x00, client.privilege, 0x00, 0x00}, consoleID...)
+	response, err := client.handshake(rmcp.PayloadOpenSessionRequest, rmcp.PayloadOpenSessionResponse, append(request, rmcp.CipherSuite3...), 36)
+	if err != nil {
+		return fmt.Errorf("could not open session: %w", err)
+	}
+	if !bytes.Equal(response[4:8], consoleID) {
+		return fmt.Errorf("could not open session: unexpected session ID")
+	}
+	client.bmcID = binary.LittleEndian.Uint32(response[8:])
+	bmcID := response[8:12]
+
+	// RAKP1 and RAKP2
+	role := []byte{client.privilege, byte(len(client.username))}
+	request = append([]byte{0x00, 0x00, 0x00, 0x00}, bmcID...)
+	request = append(request, consoleRandom...)
+	request = append(request, client.privilege, 0x00, 0x00, byte(len(client.username)))
+	request = append(request, client.username...)
+	response, err = client.handshake(rmcp.PayloadRAKP1, rmcp.PayloadRAKP2, request, 60)
+	if err != nil {
+		return fmt.Errorf("could not authenticate: %w", err)
+	}
+	bmcRandom := response[8:24]
+	bmcGUID := response[24:40]
+	if !hmac.Equal(response[40:60], rmcp.HMACSHA1(client.password, consoleID, bmcID, consoleRandom, bmcRandom, bmcGUID, role, client.username)) {
+		return fmt.Errorf("could not authenticate: invalid password")
+	}
+	sik := rmcp.HMACSHA1(client.password, consoleRandom, bmcRandom, role, client.username)
+
+	// RAKP3 and RAKP4
+	request = append([]byte{0x00, 0x00, 0x00, 0x00}, bmcID...)
+	request = append(request, rmcp.HMACSHA1(client.password, bmcRandom, consoleID, role, client.username)...)
+	response, err = client.handshake(rmcp.PayloadRAKP3, rmcp.PayloadRAKP4, request, 8+rmcp.AuthCodeLength)
+	if err != nil {
+		return fmt.Errorf("could not authenticate: %w", err)
+	}
+	if !hmac.Equal(response[8:8+rmcp.AuthCodeLength], rmcp.HMACSHA1(sik, consoleRandom, bmcID, bmcGUID)[:rmcp.AuthCodeLength]) {
+		return fmt.Errorf("could not authenticate: invalid integrity check value")
+	}
+	client.keys = rmcp.NewKeys(sik)
+
+	if _, err := client.Command(NetFnApp, cmdSetSessionPrivilege, []byte{client.privilege}); err != nil {
+		client.Close()
+		return fmt.Errorf("could not set session privilege: %w", err)
+	}
+	return nil
+}
+
+// handshake sends a payload of the session establishment and returns
+// the response, which starts with the message tag and the status code
+func (client *Client) handshake(requestType, responseType byte, payload []byte, length int) ([]byte, error) {
+	reply, err := client.exchange(
+		func() rmcp.Packet { return rmcp.Packet{Type: requestType, Payload: payload} },
+		func(p rmcp.Packet) bool { return p.Type == responseType && len(p.Payload) >= 2 },
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status := reply.Payload[1]; status != 0 {
+		return nil, StatusError(status)
+	}
+	if len(reply.Payload) < length {
+		return nil, rmcp.ErrShortPacket
+	}
+	return reply.Payload, nil
+}
+
+/*
+exchange sends the packet returned by next and returns the first
+received packet which matches. The request is sent again if there is no
+response.
+*/
+func (client *Client) exchange(next func() rmcp.Packet, match func(rmcp.Packet) bool) (rmcp.Packet, error) {
+	buf := make([]byte, 1024)
+	for try := 0; try <= requestRetries; try++ {
+		if err := client.send(next()); err != nil {
+			return rmcp.Packet{}, err
+		}
+		timeout := time.Now().Add(requestTimeout)
+		if !client.deadline.IsZero() && client.deadline.Before(timeout) {
+			timeout = client.deadline
+		}
+		if err := client.conn.SetReadDeadline(timeout); err != nil {
+			return rmcp.Packet{}, err
+		}
+		for {
+			n, err := client.conn.Read(buf)
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			} else if err != nil {
+				return rmcp.Packet{}, err
+			}
+			p, err := rmcp.Decode(buf[:n], client.keys)
+			if err != nil {
+				continue
+			}
+			if client.keys != nil && p.SessionID != client.consoleID {
+				continue
+			}
+			if match(p) {
+				return p, nil
+			}
+		}
+		if !client.deadline.IsZero() && time.Now().After(client.deadline) {
+			break
+		}
+	}
+	return rmcp.Packet{}, fmt.Errorf("no response from %s", client.address)
+}
+
+// send sends a packet, in the session once it is established
+func (client *Client) send(p rmcp.Packet) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	if client.keys != nil {
+		client.seq++
+		p.SessionID = client.bmcID
+		p.Seq = client.seq
+	}
+	data, err := p.Encode(client.keys)
+	if err != nil {
+		return err
+	}
+	_, err = client.conn.Write(data)
+	return err
+}
+
+// request returns the next IPMI request message of the command
+func (client *Client) request(netFn, lun, cmd byte, data []byte) rmcp.Message {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	client.rqSeq = (client.rqSeq + 1) & 0x3f
+	return rmcp.Message{Target: rmcp.BMCAddress, NetFn: netFn, TargetLUN: lun, Source: rmcp.ConsoleAddress, Seq: client.rqSeq, Cmd: cmd, Data: data}
+}
+
+/*
+Command sends a command to the BMC and returns the response data after
+the completion code. A completion code other than zero is returned as
+CompletionError.
+*/
+func (client *Client) Command(netFn, cmd byte, data []byte) ([]byte, error) {
+	return client.CommandLUN(netFn, 0, cmd, data)
+}
+
+// CommandLUN sends a command to the logical unit lun of the BMC
+func (client *Client) CommandLUN(netFn, lun, cmd byte, data []byte) ([]byte, error) {
+	request := client.request(netFn, lun, cmd, data)
+	payload := request.Encode()
+	var response rmcp.Message
+	_, err := client.exchange(
+		func() rmcp.Packet { return rmcp.Packet{Type: rmcp.PayloadIPMI, Payload: payload} },
+		func(p rmcp.Packet) bool {
+			if p.Type != rmcp.PayloadIPMI {
+				return false
+			}
+			m, err := rmcp.DecodeMessage(p.Payload)
+			if err != nil || m.Seq != request.Seq || m.Cmd != cmd || m.NetFn != netFn+1 || len(m.Data) < 1 {
+				return false
+			}
+			response = m
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if code := response.Data[0]; code != 0 {
+		return nil, CompletionError(code)
+	}
+	return response.Data[1:], nil
+}
+
+// Close closes the session and the connection
+func (client *Client) Close() error {
+	if client.keys != nil {
+		_, _ = client.Command(NetFnApp, cmdCloseSession, binary.LittleEndian.AppendUint32(nil, client.bmcID))
+	}
+	return client.conn.Close()
+}
@@ -0,0 +1,173 @@
+package ipmi
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/ipmitest"
+)
+
+func Test_Session(t *testing.T) {
+	sim := ipmitest.New(t)
+
+	client, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sim.Sessions())
+	require.NoError(t, client.Close())
+	assert.Equal(t, 0, sim.Sessions())
+
+	_, err = Dial(sim.Address(), "admin", "wrong", 5*time.Second)
+	assert.ErrorContains(t, err, "invalid password")
+
+	_, err = Dial(sim.Address(), "root", "secret", 5*time.Second)
+	assert.ErrorIs(t, err, StatusError(0x0d))
+}
+
+func Test_SessionTimeout(t *testing.T) {
+	requestTimeout = 50 * time.Millisecond
+	defer func() { requestTimeout = time.Second }()
+	sim := ipmitest.New(t)
+	address := sim.Address()
+	sim.Close()
+
+	start := time.Now()
+	_, err := Dial(address, "admin", "secret", 120*time.Millisecond)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_Chassis(t *testing.T) {
+	sim := ipmitest.New(t)
+	client, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	on, err := client.PowerOn()
+	require.NoError(t, err)
+	assert.False(t, on)
+	require.NoError(t, client.ChassisControl(ChassisPowerUp))
+	on, err = client.PowerOn()
+	require.NoError(t, err)
+	assert.True(t, on)
+	assert.True(t, sim.Power())
+
+	require.NoError(t, client.SetBootDevice(BootDevicePXE, true, true))
+	assert.Equal(t, []byte{0xe0, BootDevicePXE, 0x00, 0x00, 0x00}, sim.BootFlags())
+
+	_, err = client.Command(NetFnChassis, 0x7f, nil)
+	assert.ErrorIs(t, err, CompletionError(0xc1))
+}
+
+func Test_Sensors(t *testing.T) {
+	sim := ipmitest.New(t)
+	sim.AddSensor(ipmitest.Sensor{Name: "CPU Temp", Number: 1, Unit: 1, M: 1, Raw: 92, States: 0x08,
+		ThresholdMask: 0x3b, Thresholds: [6]byte{10, 5, 0, 90, 95, 100}})
+	sim.AddSensor(ipmitest.Sensor{Name: "12V", Number: 2, Unit: 4, M: 63, RExp: -3, Raw: 192})
+	sim.AddSensor(ipmitest.Sensor{Name: "Fan1", Number: 3, Unit: 18, M: 100, Unavailable: true})
+	sim.AddSensor(ipmitest.Sensor{Name: "A very long sensor name", Number: 4, Discrete: true, Raw: 0, States: 0x01})
+	client, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	sensors, err := client.Sensors()
+	require.NoError(t, err)
+	require.Len(t, sensors, 4)
+	assert.Equal(t, "CPU Temp", sensors[0].Name)
+	assert.Equal(t, "degrees C", sensors[0].Unit)
+	assert.True(t, sensors[0].Threshold())
+	assert.Equal(t, "A very long sensor name", sensors[3].Name)
+	assert.False(t, sensors[3].Analog)
+
+	reading, err := client.SensorReading(sensors[0])
+	require.NoError(t, err)
+	require.NotNil(t, reading.Value)
+	assert.Equal(t, 92.0, *reading.Value)
+	assert.Equal(t, "nc", reading.Status(sensors[0]))
+	thresholds, err := client.SensorThresholds(sensors[0])
+	require.NoError(t, err)
+	assert.Nil(t, thresholds.LowerNonRecoverable)
+	assert.Equal(t, 5.0, *thresholds.LowerCritical)
+	assert.Equal(t, 100.0, *thresholds.UpperNonRecoverable)
+
+	reading, err = client.SensorReading(sensors[1])
+	require.NoError(t, err)
+	assert.InDelta(t, 12.096, *reading.Value, 0.0001)
+	assert.Equal(t, "ok", reading.Status(sensors[1]))
+
+	reading, err = client.SensorReading(sensors[2])
+	require.NoError(t, err)
+	assert.Nil(t, reading.Value)
+	assert.Equal(t, "ns", reading.Status(sensors[2]))
+
+	reading, err = client.SensorReading(sensors[3])
+	require.NoError(t, err)
+	assert.Nil(t, reading.Value)
+	assert.Equal(t, uint16(0x8001), reading.States)
+}
+
+func Test_Convert(t *testing.T) {
+	sensor := Sensor{Analog: true, format: 2, m: 2, b: 5, bExp: 1, rExp: -1}
+	assert.InDelta(t, -0.2+5, sensor.Convert(0xff), 0.0001)
+	sensor = Sensor{Analog: true, format: 1, m: 1}
+	assert.Equal(t, 0.0, sensor.Convert(0xff))
+	assert.Equal(t, -1.0, sensor.Convert(0xfe))
+	sensor = Sensor{Analog: true, m: 1, linearization: 7}
+	assert.Equal(t, 0.25, sensor.Convert(4))
+	assert.Equal(t, int16(-512), signed(0x200, 10))
+	assert.Equal(t, int16(-3), signed(0x0d, 4))
+}
+
+func Test_SOL(t *testing.T) {
+	sim := ipmitest.New(t)
+	client, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	sol, err := client.ActivateSOL()
+	require.NoError(t, err)
+
+	other, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	_, err = other.ActivateSOL()
+	assert.ErrorContains(t, err, "already active")
+	other.Close()
+
+	n, err := sol.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	buf := make([]byte, 6)
+	_, err = io.ReadFull(sol, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(buf))
+
+	require.NoError(t, sol.Close())
+	assert.Equal(t, 0, sim.Sessions())
+	_, err = sol.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func Test_SetUserPassword(t *testing.T) {
+	sim := ipmitest.New(t)
+	client, err := Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	id, err := client.UserID("admin")
+	require.NoError(t, err)
+	assert.Equal(t, byte(2), id)
+	_, err = client.UserID("root")
+	assert.ErrorContains(t, err, "user root not found")
+
+	for _, password := range []string{"short", "a twenty char passwd"} {
+		require.NoError(t, client.SetUserPassword(id, password))
+		other, err := Dial(sim.Address(), "admin", password, 5*time.Second)
+		require.NoError(t, err, password)
+		other.Close()
+	}
+	_, err = Dial(sim.Address(), "admin", "secret", 5*time.Second)
+	assert.ErrorContains(t, err, "invalid password")
+
+	assert.Error(t, client.SetUserPassword(id, "a password which is too long"))
+	assert.ErrorIs(t, client.SetUserPassword(3, "secret"), CompletionError(0xcc))
+}
@@ -0,0 +1,9 @@
+/*
+Package ipmi is a client for IPMI v2.0 over RMCP+ (the "lanplus"
+interface of ipmitool). It supports cipher suite 3 (RAKP-HMAC-SHA1
+authentication, HMAC-SHA1-96 integrity and AES-CBC-128 confidentiality)
+and the commands Warewulf needs: chassis power control and status, boot
+device, sensor readings from the SDR repository, serial over LAN and
+changing the password of a user.
+*/
+package ipmi
@@ -0,0 +1,66 @@
+package rmcp
+
+import (
+	"fmt"
+)
+
+// addresses of the BMC and of the remote console on the IPMB
+const (
+	BMCAddress     = 0x20
+	ConsoleAddress = 0x81
+)
+
+/*
+Message is an IPMI LAN message. Requests and responses have the same
+layout: the target of a request is the BMC, the target of a response is
+the remote console. The data of a response starts with the completion
+code.
+*/
+type Message struct {
+	Target    byte
+	NetFn     byte
+	TargetLUN byte
+	Source    byte
+	Seq       byte
+	SourceLUN byte
+	Cmd       byte
+	Data      []byte
+}
+
+// checksum returns the two's complement checksum of data
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+// Encode returns the message as payload of an RMCP+ packet
+func (m Message) Encode() []byte {
+	buf := []byte{m.Target, m.NetFn<<2 | m.TargetLUN&0x03}
+	buf = append(buf, checksum(buf))
+	buf = append(buf, m.Source, m.Seq<<2|m.SourceLUN&0x03, m.Cmd)
+	buf = append(buf, m.Data...)
+	return append(buf, checksum(buf[3:]))
+}
+
+// DecodeMessage decodes the payload of an RMCP+ packet
+func DecodeMessage(data []byte) (m Message, err error) {
+	if len(data) < 7 {
+		return m, fmt.Errorf("short IPMI message")
+	}
+	if checksum(data[:2]) != data[2] || checksum(data[3:len(data)-1]) != data[len(data)-1] {
+		return m, fmt.Errorf("invalid IPMI message checksum")
+	}
+	return Message{
+		Target:    data[0],
+		NetFn:     data[1] >> 2,
+		TargetLUN: data[1] & 0x03,
+		Source:    data[3],
+		Seq:       data[4] >> 2,
+		SourceLUN: data[4] & 0x03,
+		Cmd:       data[5],
+		Data:      data[6 : len(data)-1],
+	}, nil
+}
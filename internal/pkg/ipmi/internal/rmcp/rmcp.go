@@ -0,0 +1,204 @@
+/*
+Package rmcp encodes and decodes the RMCP+ packets and IPMI messages of
+IPMI v2.0 sessions with cipher suite 3, for the client of package ipmi
+and the simulated BMC of package ipmitest.
+*/
+package rmcp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// payload types of RMCP+ packets
+const (
+	PayloadIPMI                = 0x00
+	PayloadSOL                 = 0x01
+	PayloadOpenSessionRequest  = 0x10
+	PayloadOpenSessionResponse = 0x11
+	PayloadRAKP1               = 0x12
+	PayloadRAKP2               = 0x13
+	PayloadRAKP3               = 0x14
+	PayloadRAKP4               = 0x15
+)
+
+const (
+	rmcpVersion   = 0x06
+	rmcpClassIPMI = 0x07
+	// authTypeRMCPPlus marks an IPMI v2.0 session header
+	authTypeRMCPPlus = 0x06
+	// flags of the payload type
+	FlagEncrypted     = 0x80
+	FlagAuthenticated = 0x40
+	// AuthCodeLength is the length of the HMAC-SHA1-96 integrity data
+	AuthCodeLength = 12
+)
+
+// ErrShortPacket is returned for packets and payloads which are too short
+var ErrShortPacket = errors.New("short RMCP+ packet")
+
+// CipherSuite3 are the algorithms of cipher suite 3 as proposed in the
+// open session request: RAKP-HMAC-SHA1, HMAC-SHA1-96 and AES-CBC-128
+var CipherSuite3 = []byte{
+	0x00, 0x00, 0x00, 0x08, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x00, 0x08, 0x01, 0x00, 0x00, 0x00,
+	0x02, 0x00, 0x00, 0x08, 0x01, 0x00, 0x00, 0x00,
+}
+
+// Keys are the integrity (K1) and confidentiality (K2) keys of an
+// established session, which are derived from the session integrity
+// key
+type Keys struct {
+	integrity       []byte
+	confidentiality []byte
+}
+
+// NewKeys derives the keys of a session from its session integrity key
+func NewKeys(sik []byte) *Keys {
+	return &Keys{
+		integrity:       HMACSHA1(sik, bytes.Repeat([]byte{0x01}, 20)),
+		confidentiality: HMACSHA1(sik, bytes.Repeat([]byte{0x02}, 20))[:16],
+	}
+}
+
+// HMACSHA1 returns the HMAC-SHA1 of the concatenated data
+func HMACSHA1(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha1.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// Packet is a decoded RMCP+ packet
+type Packet struct {
+	Type      byte
+	SessionID uint32
+	Seq       uint32
+	Payload   []byte
+}
+
+/*
+Encode returns the RMCP+ datagram of the packet. Outside of a session,
+k is nil and the payload is sent in clear; inside a session it is
+encrypted and authenticated with k.
+*/
+func (p Packet) Encode(k *Keys) ([]byte, error) {
+	payloadType := p.Type
+	body := p.Payload
+	if k != nil {
+		payloadType |= FlagEncrypted | FlagAuthenticated
+		var err error
+		if body, err = encrypt(k.confidentiality, body); err != nil {
+			return nil, err
+		}
+	}
+	buf := []byte{rmcpVersion, 0x00, 0xff, rmcpClassIPMI, authTypeRMCPPlus, payloadType}
+	buf = binary.LittleEndian.AppendUint32(buf, p.SessionID)
+	buf = binary.LittleEndian.AppendUint32(buf, p.Seq)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(body)))
+	buf = append(buf, body...)
+	if k != nil {
+		// the authenticated data from the auth type to the next header
+		// field is padded to a multiple of four bytes
+		pad := (4 - (len(buf)-4+2)%4) % 4
+		buf = append(buf, bytes.Repeat([]byte{0xff}, pad)...)
+		buf = append(buf, byte(pad), rmcpClassIPMI)
+		buf = append(buf, HMACSHA1(k.integrity, buf[4:])[:AuthCodeLength]...)
+	}
+	return buf, nil
+}
+
+// Decode decodes an RMCP+ datagram, checking and decrypting it
+// with k if it is authenticated and encrypted
+func Decode(data []byte, k *Keys) (p Packet, err error) {
+	if len(data) < 16 {
+		return p, ErrShortPacket
+	}
+	if data[0] != rmcpVersion || data[3] != rmcpClassIPMI {
+		return p, fmt.Errorf("not an IPMI RMCP packet")
+	}
+	if data[4] != authTypeRMCPPlus {
+		return p, fmt.Errorf("not an IPMI v2.0 packet, auth type %d", data[4])
+	}
+	flags := data[5]
+	p.Type = flags & 0x3f
+	p.SessionID = binary.LittleEndian.Uint32(data[6:])
+	p.Seq = binary.LittleEndian.Uint32(data[10:])
+	length := int(binary.LittleEndian.Uint16(data[14:]))
+	if len(data) < 16+length {
+		return p, ErrShortPacket
+	}
+	body := data[16 : 16+length]
+	if flags&FlagAuthenticated != 0 {
+		if k == nil {
+			return p, fmt.Errorf("authenticated packet outside of a session")
+		}
+		if len(data) < 16+length+2+AuthCodeLength {
+			return p, ErrShortPacket
+		}
+		authCode := data[len(data)-AuthCodeLength:]
+		if !hmac.Equal(authCode, HMACSHA1(k.integrity, data[4:len(data)-AuthCodeLength])[:AuthCodeLength]) {
+			return p, fmt.Errorf("invalid integrity check value")
+		}
+	} else if k != nil {
+		return p, fmt.Errorf("unauthenticated packet in a session")
+	}
+	if flags&FlagEncrypted != 0 {
+		if k == nil {
+			return p, fmt.Errorf("encrypted packet outside of a session")
+		}
+		if body, err = decrypt(k.confidentiality, body); err != nil {
+			return p, err
+		}
+	}
+	p.Payload = body
+	return p, nil
+}
+
+// encrypt encrypts the payload with AES-CBC-128, prefixed with the
+// initialization vector. The payload is padded with 1, 2, 3, ...
+// followed by the pad length.
+func encrypt(key, payload []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pad := (aes.BlockSize - (len(payload)+1)%aes.BlockSize) % aes.BlockSize
+	plain := append([]byte{}, payload...)
+	for i := 1; i <= pad; i++ {
+		plain = append(plain, byte(i))
+	}
+	plain = append(plain, byte(pad))
+	out := make([]byte, aes.BlockSize+len(plain))
+	if _, err := rand.Read(out[:aes.BlockSize]); err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], plain)
+	return out, nil
+}
+
+// decrypt reverses encrypt
+func decrypt(key, data []byte) ([]byte, error) {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid encrypted payload length %d", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(plain, data[aes.BlockSize:])
+	pad := int(plain[len(plain)-1])
+	if pad >= aes.BlockSize {
+		return nil, fmt.Errorf("invalid confidentiality pad length %d", pad)
+	}
+	return plain[:len(plain)-1-pad], nil
+}
@@ -0,0 +1,51 @@
+package rmcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Packet(t *testing.T) {
+	k := NewKeys([]byte("session integrity key"))
+	for _, length := range []int{0, 1, 15, 16, 17, 100} {
+		payload := make([]byte, length)
+		for i := range payload {
+			payload[i] = byte(i)
+		}
+		data, err := Packet{Type: PayloadIPMI, SessionID: 0x12345678, Seq: 3, Payload: payload}.Encode(k)
+		require.NoError(t, err)
+		assert.Zero(t, (len(data)-4-AuthCodeLength)%4, "authenticated data is padded")
+		p, err := Decode(data, k)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(0x12345678), p.SessionID)
+		assert.Equal(t, uint32(3), p.Seq)
+		assert.Equal(t, payload, p.Payload)
+
+		data[20] ^= 0x01
+		_, err = Decode(data, k)
+		assert.Error(t, err, "tampered packet")
+	}
+
+	data, err := Packet{Type: PayloadOpenSessionRequest, Payload: []byte{1, 2, 3}}.Encode(nil)
+	require.NoError(t, err)
+	p, err := Decode(data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, p.Payload)
+	_, err = Decode(data, k)
+	assert.Error(t, err, "clear packet in a session")
+}
+
+func Test_Message(t *testing.T) {
+	// chassis control (0x00, 0x02) with power up (0x01)
+	m := Message{Target: BMCAddress, NetFn: 0x00, Source: ConsoleAddress, Seq: 5, Cmd: 0x02, Data: []byte{0x01}}
+	data := m.Encode()
+	assert.Equal(t, []byte{0x20, 0x00, 0xe0, 0x81, 0x14, 0x02, 0x01, 0x68}, data)
+	decoded, err := DecodeMessage(data)
+	require.NoError(t, err)
+	assert.Equal(t, m, decoded)
+	data[6] = 0x02
+	_, err = DecodeMessage(data)
+	assert.Error(t, err)
+}
@@ -0,0 +1,532 @@
+/*
+Package ipmitest provides a simulated BMC for the tests of the IPMI
+client and of the commands which use it.
+*/
+package ipmitest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+)
+
+// the user and password of the simulators started with New
+const (
+	Username = "admin"
+	Password = "secret"
+)
+
+// network functions, commands and parameters which the simulator
+// serves, as in the IPMI v2.0 specification
+const (
+	netFnChassis     = 0x00
+	netFnSensorEvent = 0x04
+	netFnApp         = 0x06
+	netFnStorage     = 0x0a
+
+	cmdGetDeviceID          = 0x01
+	cmdSetSessionPrivilege  = 0x3b
+	cmdCloseSession         = 0x3c
+	cmdGetUserAccess        = 0x44
+	cmdGetUserName          = 0x46
+	cmdSetUserPassword      = 0x47
+	cmdActivatePayload      = 0x48
+	cmdDeactivatePayload    = 0x49
+	cmdGetChassisStatus     = 0x01
+	cmdChassisControl       = 0x02
+	cmdSetSystemBootOptions = 0x08
+	cmdReserveSDRRepository = 0x22
+	cmdGetSDR               = 0x23
+	cmdGetSensorThresholds  = 0x27
+	cmdGetSensorReading     = 0x2d
+
+	chassisPowerDown  = 0x00
+	chassisPowerUp    = 0x01
+	chassisPowerCycle = 0x02
+	chassisHardReset  = 0x03
+	chassisSoftOff    = 0x05
+
+	bootParamFlags      = 0x05
+	recordFullSensor    = 0x01
+	recordCompactSensor = 0x02
+	eventTypeThreshold  = 0x01
+	userPasswordSet     = 0x02
+	userPassword20      = 0x80
+	// errSOLActive is the completion code of an already active SOL
+	// payload
+	errSOLActive = 0x80
+)
+
+/*
+Sensor is a sensor of the Simulator. Analog threshold sensors
+have a full record with the conversion factors M, B, RExp and BExp,
+discrete sensors a compact record.
+*/
+type Sensor struct {
+	Name     string
+	Number   byte
+	Unit     byte
+	Discrete bool
+	M        int16
+	B        int16
+	RExp     int8
+	BExp     int8
+	// the raw reading and threshold comparison status or discrete
+	// states
+	Raw         byte
+	States      byte
+	Unavailable bool
+	// ThresholdMask marks the readable thresholds in Thresholds, which
+	// are lnc, lcr, lnr, unc, ucr and unr
+	ThresholdMask byte
+	Thresholds    [6]byte
+}
+
+// record returns the SDR record of the sensor
+func (sensor Sensor) record(id uint16) []byte {
+	var record []byte
+	if sensor.Discrete {
+		record = make([]byte, 32, 32+len(sensor.Name))
+		record[3] = recordCompactSensor
+		record[13] = 0x6f
+		record[20] = 0xc0
+		record[31] = 0xc0 | byte(len(sensor.Name))
+	} else {
+		record = make([]byte, 48, 48+len(sensor.Name))
+		record[3] = recordFullSensor
+		record[13] = eventTypeThreshold
+		record[18] = sensor.ThresholdMask
+		record[24] = byte(sensor.M)
+		record[25] = byte(sensor.M>>2) & 0xc0
+		record[26] = byte(sensor.B)
+		record[27] = byte(sensor.B>>2) & 0xc0
+		record[29] = byte(sensor.RExp)<<4 | byte(sensor.BExp)&0x0f
+		record[47] = 0xc0 | byte(len(sensor.Name))
+	}
+	binary.LittleEndian.PutUint16(record, id)
+	record[2] = 0x51
+	record[5] = rmcp.BMCAddress
+	record[7] = sensor.Number
+	record[12] = 0x01
+	record[21] = sensor.Unit
+	record = append(record, sensor.Name...)
+	record[4] = byte(len(record) - 5)
+	return record
+}
+
+// simulatorGUID is the GUID of the simulated system
+var simulatorGUID = bytes.Repeat([]byte{0xa5}, 16)
+
+const (
+	// simulatorUsers is the number of user IDs of the simulator, of
+	// which only the first two are used: the anonymous user and the
+	// user of the simulator
+	simulatorUsers = 4
+	// simulatorUserID is the user ID of the user of the simulator
+	simulatorUserID = 2
+)
+
+// simulatorSession is a session of the Simulator
+type simulatorSession struct {
+	consoleID     []byte
+	bmcID         []byte
+	consoleRandom []byte
+	bmcRandom     []byte
+	role          []byte
+	username      []byte
+	keys          *rmcp.Keys
+	seq           uint32
+	solSeq        byte
+}
+
+/*
+Simulator is a BMC which serves IPMI v2.0 over RMCP+ on a local UDP port
+for tests. It authenticates a single user with cipher suite 3, whose
+password can be changed with Set User Password, keeps the chassis power
+state and boot flags, serves the SDR repository and the readings of its
+sensors and echoes the input of the SOL payload.
+*/
+type Simulator struct {
+	username []byte
+	password []byte
+	conn     *net.UDPConn
+
+	lock      sync.Mutex
+	power     bool
+	bootFlags []byte
+	sensors   []Sensor
+	sessions  map[uint32]*simulatorSession
+	solActive bool
+}
+
+// New starts a simulator with Username and Password, which is closed
+// when the test finishes
+func New(t testing.TB) *Simulator {
+	t.Helper()
+	sim, err := NewSimulator(Username, Password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sim.Close() })
+	return sim
+}
+
+// NewSimulator starts a simulated BMC on a free port of the loopback
+// interface
+func NewSimulator(username, password string) (*Simulator, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, err
+	}
+	sim := &Simulator{
+		username: []byte(username),
+		password: []byte(password),
+		conn:     conn,
+		sessions: make(map[uint32]*simulatorSession),
+	}
+	go sim.serve()
+	return sim, nil
+}
+
+// Address returns the address (host:port) of the simulator
+func (sim *Simulator) Address() string {
+	return sim.conn.LocalAddr().String()
+}
+
+// Host returns the IP address of the simulator
+func (sim *Simulator) Host() string {
+	return sim.conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+// Port returns the UDP port of the simulator
+func (sim *Simulator) Port() string {
+	return strconv.Itoa(sim.conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+// Close stops the simulator
+func (sim *Simulator) Close() error {
+	return sim.conn.Close()
+}
+
+// Power returns whether the chassis power is on
+func (sim *Simulator) Power() bool {
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	return sim.power
+}
+
+// SetPower sets the chassis power
+func (sim *Simulator) SetPower(on bool) {
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	sim.power = on
+}
+
+// BootFlags returns the last set boot flags parameter
+func (sim *Simulator) BootFlags() []byte {
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	return sim.bootFlags
+}
+
+// AddSensor adds a sensor to the SDR repository
+func (sim *Simulator) AddSensor(sensor Sensor) {
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	sim.sensors = append(sim.sensors, sensor)
+}
+
+// Sessions returns the number of open sessions
+func (sim *Simulator) Sessions() int {
+	sim.lock.Lock()
+	defer sim.lock.Unlock()
+	return len(sim.sessions)
+}
+
+func (sim *Simulator) serve() {
+	buf := make([]byte, 1024)
+	for {
+		n, addr, err := sim.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		sim.lock.Lock()
+		sim.handle(append([]byte{}, buf[:n]...), addr)
+		sim.lock.Unlock()
+	}
+}
+
+func (sim *Simulator) send(session *simulatorSession, p rmcp.Packet, addr *net.UDPAddr) {
+	var k *rmcp.Keys
+	if session != nil && session.keys != nil {
+		session.seq++
+		p.SessionID = binary.LittleEndian.Uint32(session.consoleID)
+		p.Seq = session.seq
+		k = session.keys
+	}
+	if data, err := p.Encode(k); err == nil {
+		_, _ = sim.conn.WriteToUDP(data, addr)
+	}
+}
+
+func (sim *Simulator) handle(data []byte, addr *net.UDPAddr) {
+	if len(data) < 16 {
+		return
+	}
+	session := sim.sessions[binary.LittleEndian.Uint32(data[6:])]
+	var k *rmcp.Keys
+	if session != nil {
+		k = session.keys
+	}
+	p, err := rmcp.Decode(data, k)
+	if err != nil {
+		return
+	}
+	switch p.Type {
+	case rmcp.PayloadOpenSessionRequest:
+		sim.openSession(p.Payload, addr)
+	case rmcp.PayloadRAKP1:
+		sim.rakp1(p.Payload, addr)
+	case rmcp.PayloadRAKP3:
+		sim.rakp3(p.Payload, addr)
+	case rmcp.PayloadIPMI:
+		if session != nil && session.keys != nil {
+			sim.command(session, p.Payload, addr)
+		}
+	case rmcp.PayloadSOL:
+		if session != nil && session.keys != nil && sim.solActive {
+			sim.sol(session, p.Payload, addr)
+		}
+	}
+}
+
+func (sim *Simulator) openSession(request []byte, addr *net.UDPAddr) {
+	if len(request) < 32 {
+		return
+	}
+	response := []byte{request[0], 0x00, request[1], 0x00}
+	if !bytes.Equal(request[8:32], rmcp.CipherSuite3) {
+		response[1] = 0x11
+		sim.send(nil, rmcp.Packet{Type: rmcp.PayloadOpenSessionResponse, Payload: response}, addr)
+		return
+	}
+	bmcID := make([]byte, 4)
+	_, _ = rand.Read(bmcID)
+	bmcID[0] |= 1
+	sim.sessions[binary.LittleEndian.Uint32(bmcID)] = &simulatorSession{
+		consoleID: append([]byte{}, request[4:8]...),
+		bmcID:     bmcID,
+	}
+	response = append(response, request[4:8]...)
+	response = append(response, bmcID...)
+	response = append(response, rmcp.CipherSuite3...)
+	sim.send(nil, rmcp.Packet{Type: rmcp.PayloadOpenSessionResponse, Payload: response}, addr)
+}
+
+func (sim *Simulator) rakp1(request []byte, addr *net.UDPAddr) {
+	if len(request) < 28 || len(request) < 28+int(request[27]) {
+		return
+	}
+	session := sim.sessions[binary.LittleEndian.Uint32(request[4:])]
+	if session == nil {
+		sim.send(nil, rmcp.Packet{Type: rmcp.PayloadRAKP2, Payload: []byte{request[0], 0x02, 0x00, 0x00}}, addr)
+		return
+	}
+	session.consoleRandom = append([]byte{}, request[8:24]...)
+	session.role = []byte{request[24], request[27]}
+	session.username = append([]byte{}, request[28:28+int(request[27])]...)
+	if !bytes.Equal(session.username, sim.username) {
+		sim.send(nil, rmcp.Packet{Type: rmcp.PayloadRAKP2, Payload: []byte{request[0], 0x0d, 0x00, 0x00}}, addr)
+		return
+	}
+	session.bmcRandom = make([]byte, 16)
+	_, _ = rand.Read(session.bmcRandom)
+	response := append([]byte{request[0], 0x00, 0x00, 0x00}, session.consoleID...)
+	response = append(response, session.bmcRandom...)
+	response = append(response, simulatorGUID...)
+	response = append(response, rmcp.HMACSHA1(sim.password, session.consoleID, session.bmcID, session.consoleRandom,
+		session.bmcRandom, simulatorGUID, session.role, session.username)...)
+	sim.send(nil, rmcp.Packet{Type: rmcp.PayloadRAKP2, Payload: response}, addr)
+}
+
+func (sim *Simulator) rakp3(request []byte, addr *net.UDPAddr) {
+	if len(request) < 28 {
+		return
+	}
+	session := sim.sessions[binary.LittleEndian.Uint32(request[4:])]
+	if session == nil || session.bmcRandom == nil {
+		return
+	}
+	response := append([]byte{request[0], 0x00, 0x00, 0x00}, session.consoleID...)
+	if !hmac.Equal(request[8:28], rmcp.HMACSHA1(sim.password, session.bmcRandom, session.consoleID, session.role, session.username)) {
+		response[1] = 0x0f
+		sim.send(nil, rmcp.Packet{Type: rmcp.PayloadRAKP4, Payload: response}, addr)
+		return
+	}
+	sik := rmcp.HMACSHA1(sim.password, session.consoleRandom, session.bmcRandom, session.role, session.username)
+	response = append(response, rmcp.HMACSHA1(sik, session.consoleRandom, session.bmcID, simulatorGUID)[:rmcp.AuthCodeLength]...)
+	sim.send(nil, rmcp.Packet{Type: rmcp.PayloadRAKP4, Payload: response}, addr)
+	session.keys = rmcp.NewKeys(sik)
+}
+
+// command handles an IPMI request of an established session
+func (sim *Simulator) command(session *simulatorSession, payload []byte, addr *net.UDPAddr) {
+	request, err := rmcp.DecodeMessage(payload)
+	if err != nil {
+		return
+	}
+	data := request.Data
+	response := []byte{0x00}
+	// the length of the request data of the commands which have data
+	lengths := map[[2]byte]int{
+		{netFnApp, cmdSetSessionPrivilege}:         1,
+		{netFnApp, cmdCloseSession}:                4,
+		{netFnApp, cmdGetUserAccess}:               2,
+		{netFnApp, cmdGetUserName}:                 1,
+		{netFnApp, cmdSetUserPassword}:             18,
+		{netFnChassis, cmdChassisControl}:          1,
+		{netFnChassis, cmdSetSystemBootOptions}:    2,
+		{netFnStorage, cmdGetSDR}:                  6,
+		{netFnSensorEvent, cmdGetSensorReading}:    1,
+		{netFnSensorEvent, cmdGetSensorThresholds}: 1,
+	}
+	switch {
+	case len(data) < lengths[[2]byte{request.NetFn, request.Cmd}]:
+		response[0] = 0xc7
+	case request.NetFn == netFnApp && request.Cmd == cmdGetDeviceID:
+		response = append(response, 0x20, 0x01, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	case request.NetFn == netFnApp && request.Cmd == cmdSetSessionPrivilege:
+		response = append(response, data[0])
+	case request.NetFn == netFnApp && request.Cmd == cmdCloseSession:
+		delete(sim.sessions, binary.LittleEndian.Uint32(session.bmcID))
+	case request.NetFn == netFnApp && request.Cmd == cmdGetUserAccess:
+		response = append(response, simulatorUsers, 0x01, 0x00, 0x34)
+	case request.NetFn == netFnApp && request.Cmd == cmdGetUserName:
+		switch data[0] {
+		case 1:
+			response = append(response, make([]byte, 16)...)
+		case simulatorUserID:
+			response = append(response, sim.username...)
+			response = append(response, make([]byte, 16-len(sim.username))...)
+		default:
+			response[0] = 0xcc
+		}
+	case request.NetFn == netFnApp && request.Cmd == cmdSetUserPassword:
+		size := 16
+		if data[0]&userPassword20 != 0 {
+			size = 20
+		}
+		switch {
+		case data[0]&0x3f != simulatorUserID || data[1] != userPasswordSet:
+			response[0] = 0xcc
+		case len(data) != 2+size:
+			response[0] = 0xc7
+		default:
+			sim.password = bytes.TrimRight(data[2:], "\x00")
+		}
+	case request.NetFn == netFnApp && request.Cmd == cmdActivatePayload:
+		if sim.solActive {
+			response[0] = errSOLActive
+			break
+		}
+		sim.solActive = true
+		port := uint16(sim.conn.LocalAddr().(*net.UDPAddr).Port)
+		response = append(response, 0x00, 0x00, 0x00, 0x00, 0xc8, 0x00, 0xc8, 0x00)
+		response = binary.LittleEndian.AppendUint16(response, port)
+		response = append(response, 0xff, 0xff)
+	case request.NetFn == netFnApp && request.Cmd == cmdDeactivatePayload:
+		sim.solActive = false
+	case request.NetFn == netFnChassis && request.Cmd == cmdGetChassisStatus:
+		state := byte(0x00)
+		if sim.power {
+			state = 0x01
+		}
+		response = append(response, state, 0x00, 0x00)
+	case request.NetFn == netFnChassis && request.Cmd == cmdChassisControl:
+		switch data[0] {
+		case chassisPowerDown, chassisSoftOff:
+			sim.power = false
+		case chassisPowerUp, chassisPowerCycle, chassisHardReset:
+			sim.power = true
+		}
+	case request.NetFn == netFnChassis && request.Cmd == cmdSetSystemBootOptions:
+		if data[0] == bootParamFlags {
+			sim.bootFlags = append([]byte{}, data[1:]...)
+		}
+	case request.NetFn == netFnStorage && request.Cmd == cmdReserveSDRRepository:
+		response = append(response, 0x01, 0x00)
+	case request.NetFn == netFnStorage && request.Cmd == cmdGetSDR:
+		id := int(binary.LittleEndian.Uint16(data[2:]))
+		offset, length := int(data[4]), int(data[5])
+		if id >= len(sim.sensors) {
+			response[0] = 0xcb
+			break
+		}
+		record := sim.sensors[id].record(uint16(id))
+		next := uint16(id + 1)
+		if id+1 == len(sim.sensors) {
+			next = 0xffff
+		}
+		if length == 0xff || offset+length > len(record) {
+			length = len(record) - offset
+		}
+		response = binary.LittleEndian.AppendUint16(response, next)
+		response = append(response, record[offset:offset+length]...)
+	case request.NetFn == netFnSensorEvent && (request.Cmd == cmdGetSensorReading || request.Cmd == cmdGetSensorThresholds):
+		sensor := sim.sensor(data[0])
+		if sensor == nil {
+			response[0] = 0xcb
+		} else if request.Cmd == cmdGetSensorReading {
+			flags := byte(0x40)
+			if sensor.Unavailable {
+				flags |= 0x20
+			}
+			response = append(response, sensor.Raw, flags, sensor.States, 0x80)
+		} else {
+			response = append(response, sensor.ThresholdMask)
+			response = append(response, sensor.Thresholds[:]...)
+		}
+	default:
+		response[0] = 0xc1
+	}
+	reply := rmcp.Message{
+		Target:    request.Source,
+		NetFn:     request.NetFn + 1,
+		TargetLUN: request.SourceLUN,
+		Source:    rmcp.BMCAddress,
+		Seq:       request.Seq,
+		SourceLUN: request.TargetLUN,
+		Cmd:       request.Cmd,
+		Data:      response,
+	}
+	sim.send(session, rmcp.Packet{Type: rmcp.PayloadIPMI, Payload: reply.Encode()}, addr)
+}
+
+func (sim *Simulator) sensor(number byte) *Sensor {
+	for i := range sim.sensors {
+		if sim.sensors[i].Number == number {
+			return &sim.sensors[i]
+		}
+	}
+	return nil
+}
+
+// sol acknowledges the input of the SOL payload and echoes it
+func (sim *Simulator) sol(session *simulatorSession, payload []byte, addr *net.UDPAddr) {
+	if len(payload) < 4 || payload[0] == 0 {
+		return
+	}
+	data := payload[4:]
+	sim.send(session, rmcp.Packet{Type: rmcp.PayloadSOL, Payload: []byte{0x00, payload[0], byte(len(data)), 0x00}}, addr)
+	if len(data) > 0 {
+		session.solSeq = session.solSeq%15 + 1
+		sim.send(session, rmcp.Packet{Type: rmcp.PayloadSOL, Payload: append([]byte{session.solSeq, 0x00, 0x00, 0x00}, data...)}, addr)
+	}
+}
@@ -0,0 +1,70 @@
+package ipmi
+
+import (
+	"fmt"
+)
+
+// network functions of requests; the response has the next (odd)
+// network function
+const (
+	NetFnChassis     = 0x00
+	NetFnSensorEvent = 0x04
+	NetFnApp         = 0x06
+	NetFnStorage     = 0x0a
+)
+
+// CompletionError is the completion code of a failed command
+type CompletionError byte
+
+var completionCodes = map[CompletionError]string{
+	0xc0: "node busy",
+	0xc1: "invalid command",
+	0xc3: "timeout",
+	0xc5: "reservation canceled or invalid",
+	0xc7: "request data length invalid",
+	0xc9: "parameter out of range",
+	0xcb: "requested sensor, data, or record not present",
+	0xcc: "invalid data field in request",
+	0xcd: "command illegal for specified sensor or record type",
+	0xd4: "insufficient privilege level",
+	0xd5: "command not supported in present state",
+	0xff: "unspecified error",
+}
+
+func (code CompletionError) Error() string {
+	if text, ok := completionCodes[code]; ok {
+		return fmt.Sprintf("completion code 0x%02x: %s", byte(code), text)
+	}
+	return fmt.Sprintf("completion code 0x%02x", byte(code))
+}
+
+// StatusError is the status code of a failed session establishment
+type StatusError byte
+
+var statusCodes = map[StatusError]string{
+	0x01: "insufficient resources to create a session",
+	0x02: "invalid session ID",
+	0x03: "invalid payload type",
+	0x04: "invalid authentication algorithm",
+	0x05: "invalid integrity algorithm",
+	0x06: "no matching authentication payload",
+	0x07: "no matching integrity payload",
+	0x08: "inactive session ID",
+	0x09: "invalid role",
+	0x0a: "unauthorized role or privilege level requested",
+	0x0b: "insufficient resources to create a session at the requested role",
+	0x0c: "invalid name length",
+	0x0d: "unauthorized name",
+	0x0e: "unauthorized GUID",
+	0x0f: "invalid integrity check value",
+	0x10: "invalid confidentiality algorithm",
+	0x11: "no cipher suite match with proposed security algorithms",
+	0x12: "illegal or unrecognized parameter",
+}
+
+func (code StatusError) Error() string {
+	if text, ok := statusCodes[code]; ok {
+		return fmt.Sprintf("RMCP+ status 0x%02x: %s", byte(code), text)
+	}
+	return fmt.Sprintf("RMCP+ status 0x%02x", byte(code))
+}
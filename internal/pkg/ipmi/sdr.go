@@ -0,0 +1,335 @@
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+)
+
+// commands of the storage and sensor/event network functions
+const (
+	cmdReserveSDRRepository = 0x22
+	cmdGetSDR               = 0x23
+	cmdGetSensorThresholds  = 0x27
+	cmdGetSensorReading     = 0x2d
+)
+
+// types of SDR records
+const (
+	recordFullSensor    = 0x01
+	recordCompactSensor = 0x02
+)
+
+// eventTypeThreshold is the event/reading type of threshold sensors
+const eventTypeThreshold = 0x01
+
+// sdrChunk is the number of bytes read from a record at once, which all
+// BMCs support
+const sdrChunk = 16
+
+// units are the names of the sensor base units, as ipmitool shows them
+var units = []string{
+	"unspecified", "degrees C", "degrees F", "degrees K", "Volts", "Amps",
+	"Watts", "Joules", "Coulombs", "VA", "Nits", "lumen", "lux", "Candela",
+	"kPa", "PSI", "Newton", "CFM", "RPM", "Hz", "microsecond", "millisecond",
+	"second", "minute", "hour",
+}
+
+/*
+Sensor is a sensor from a full or a compact sensor record of the SDR
+repository. Only full records of analog sensors have the factors to
+convert raw readings and thresholds.
+*/
+type Sensor struct {
+	Name      string
+	Number    byte
+	OwnerID   byte
+	LUN       byte
+	Type      byte
+	EventType byte
+	Unit      string
+	Analog    bool
+
+	format        byte
+	linearization byte
+	m             int16
+	b             int16
+	rExp          int8
+	bExp          int8
+}
+
+// Threshold returns whether the sensor is a threshold based sensor
+func (sensor Sensor) Threshold() bool {
+	return sensor.EventType == eventTypeThreshold
+}
+
+/*
+Convert returns the value of a raw reading or threshold of an analog
+sensor: y = L[(M*x + B*10^Bexp) * 10^Rexp].
+*/
+func (sensor Sensor) Convert(raw byte) float64 {
+	x := float64(raw)
+	switch sensor.format {
+	case 1: // one's complement
+		x = float64(int8(raw))
+		if raw&0x80 != 0 {
+			x++
+		}
+	case 2: // two's complement
+		x = float64(int8(raw))
+	}
+	y := (float64(sensor.m)*x + float64(sensor.b)*math.Pow10(int(sensor.bExp))) * math.Pow10(int(sensor.rExp))
+	switch sensor.linearization {
+	case 1:
+		y = math.Log(y)
+	case 2:
+		y = math.Log10(y)
+	case 3:
+		y = math.Log2(y)
+	case 4:
+		y = math.Exp(y)
+	case 5:
+		y = math.Pow(10, y)
+	case 6:
+		y = math.Exp2(y)
+	case 7:
+		y = 1 / y
+	case 8:
+		y = y * y
+	case 9:
+		y = y * y * y
+	case 10:
+		y = math.Sqrt(y)
+	case 11:
+		y = math.Cbrt(y)
+	}
+	return y
+}
+
+// signed returns the value of the two's complement number of bits bits
+func signed(value uint16, bits uint) int16 {
+	return int16(value<<(16-bits)) >> (16 - bits)
+}
+
+// parseSensor returns the sensor of a full or compact sensor record,
+// including its five byte header
+func parseSensor(record []byte) (sensor Sensor, ok bool) {
+	if len(record) < 5 {
+		return sensor, false
+	}
+	nameOffset := 0
+	switch {
+	case record[3] == recordFullSensor && len(record) >= 48:
+		nameOffset = 47
+	case record[3] == recordCompactSensor && len(record) >= 32:
+		nameOffset = 31
+	default:
+		return sensor, false
+	}
+	sensor = Sensor{
+		OwnerID:   record[5],
+		LUN:       record[6] & 0x03,
+		Number:    record[7],
+		Type:      record[12],
+		EventType: record[13],
+		Unit:      units[0],
+	}
+	if int(record[21]) < len(units) {
+		sensor.Unit = units[record[21]]
+	}
+	if record[20]&0x01 != 0 {
+		sensor.Unit = "percent"
+	}
+	if record[3] == recordFullSensor {
+		sensor.format = record[20] >> 6
+		sensor.Analog = sensor.format != 3
+		sensor.linearization = record[23] & 0x7f
+		sensor.m = signed(uint16(record[24])|uint16(record[25]&0xc0)<<2, 10)
+		sensor.b = signed(uint16(record[26])|uint16(record[27]&0xc0)<<2, 10)
+		sensor.rExp = int8(signed(uint16(record[29]>>4), 4))
+		sensor.bExp = int8(signed(uint16(record[29]&0x0f), 4))
+	}
+	length := int(record[nameOffset] & 0x1f)
+	if nameOffset+1+length > len(record) {
+		length = len(record) - nameOffset - 1
+	}
+	sensor.Name = string(record[nameOffset+1 : nameOffset+1+length])
+	return sensor, true
+}
+
+/*
+Sensors returns the sensors of the SDR repository which are owned by the
+BMC, in the order of the repository.
+*/
+func (client *Client) Sensors() (sensors []Sensor, err error) {
+	reservation, err := client.reserveSDR()
+	if err != nil {
+		return nil, err
+	}
+	for id := uint16(0); id != 0xffff; {
+		var next uint16
+		var record []byte
+		for try := 0; ; try++ {
+			next, record, err = client.readSDR(reservation, id)
+			var code CompletionError
+			if try < 3 && errors.As(err, &code) && code == 0xc5 {
+				// the reservation was canceled by a change of the repository
+				if reservation, err = client.reserveSDR(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sensor, ok := parseSensor(record); ok && sensor.OwnerID == rmcp.BMCAddress {
+			sensors = append(sensors, sensor)
+		}
+		if next == id {
+			break
+		}
+		id = next
+	}
+	return sensors, nil
+}
+
+func (client *Client) reserveSDR() ([]byte, error) {
+	data, err := client.Command(NetFnStorage, cmdReserveSDRRepository, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 {
+		return nil, rmcp.ErrShortPacket
+	}
+	return data[:2], nil
+}
+
+// readSDR reads the record with the id in chunks and returns it with the
+// id of the next record
+func (client *Client) readSDR(reservation []byte, id uint16) (next uint16, record []byte, err error) {
+	read := func(offset, length byte) ([]byte, error) {
+		request := append(append([]byte{}, reservation...), byte(id), byte(id>>8), offset, length)
+		data, err := client.Command(NetFnStorage, cmdGetSDR, request)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 2+int(length) {
+			return nil, rmcp.ErrShortPacket
+		}
+		next = binary.LittleEndian.Uint16(data)
+		return data[2 : 2+int(length)], nil
+	}
+	if record, err = read(0, 5); err != nil {
+		return 0, nil, err
+	}
+	total := 5 + int(record[4])
+	for len(record) < total {
+		length := min(sdrChunk, total-len(record))
+		chunk, err := read(byte(len(record)), byte(length))
+		if err != nil {
+			return 0, nil, err
+		}
+		record = append(record, chunk...)
+	}
+	return next, record, nil
+}
+
+/*
+Reading is the reading of a sensor. Value is only set for available
+readings of analog sensors; threshold sensors have the threshold
+comparison status in States, discrete sensors their asserted states.
+*/
+type Reading struct {
+	Raw       byte
+	Value     *float64
+	Available bool
+	States    uint16
+}
+
+// SensorReading returns the current reading of the sensor
+func (client *Client) SensorReading(sensor Sensor) (reading Reading, err error) {
+	data, err := client.CommandLUN(NetFnSensorEvent, sensor.LUN, cmdGetSensorReading, []byte{sensor.Number})
+	var code CompletionError
+	if errors.As(err, &code) && code == 0xcb {
+		return reading, nil
+	} else if err != nil {
+		return reading, err
+	}
+	if len(data) < 2 {
+		return reading, rmcp.ErrShortPacket
+	}
+	reading.Raw = data[0]
+	// the reading is available and scanning is enabled
+	reading.Available = data[1]&0x20 == 0 && data[1]&0x40 != 0
+	if len(data) >= 3 {
+		reading.States = uint16(data[2])
+	}
+	if len(data) >= 4 {
+		reading.States |= uint16(data[3]) << 8
+	}
+	if reading.Available && sensor.Analog {
+		value := sensor.Convert(reading.Raw)
+		reading.Value = &value
+	}
+	return reading, nil
+}
+
+/*
+Status returns the status of a reading as ipmitool shows it: "ns" if
+there is no reading, for threshold sensors "nr", "cr" or "nc" if a non
+recoverable, critical or non critical threshold is crossed and "ok"
+otherwise.
+*/
+func (reading Reading) Status(sensor Sensor) string {
+	switch {
+	case !reading.Available:
+		return "ns"
+	case !sensor.Threshold():
+		return "ok"
+	case reading.States&0x24 != 0:
+		return "nr"
+	case reading.States&0x12 != 0:
+		return "cr"
+	case reading.States&0x09 != 0:
+		return "nc"
+	}
+	return "ok"
+}
+
+// Thresholds are the thresholds of a sensor, nil if not readable
+type Thresholds struct {
+	LowerNonRecoverable *float64
+	LowerCritical       *float64
+	LowerNonCritical    *float64
+	UpperNonCritical    *float64
+	UpperCritical       *float64
+	UpperNonRecoverable *float64
+}
+
+// SensorThresholds returns the thresholds of an analog threshold sensor
+func (client *Client) SensorThresholds(sensor Sensor) (thresholds Thresholds, err error) {
+	if !sensor.Threshold() || !sensor.Analog {
+		return thresholds, nil
+	}
+	data, err := client.CommandLUN(NetFnSensorEvent, sensor.LUN, cmdGetSensorThresholds, []byte{sensor.Number})
+	if err != nil {
+		return thresholds, err
+	}
+	if len(data) < 7 {
+		return thresholds, rmcp.ErrShortPacket
+	}
+	for i, threshold := range []**float64{
+		&thresholds.LowerNonCritical, &thresholds.LowerCritical, &thresholds.LowerNonRecoverable,
+		&thresholds.UpperNonCritical, &thresholds.UpperCritical, &thresholds.UpperNonRecoverable,
+	} {
+		if data[0]&(1<<i) != 0 {
+			value := sensor.Convert(data[1+i])
+			*threshold = &value
+		}
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,252 @@
+package ipmi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+)
+
+// status bits of SOL packets of the BMC
+const (
+	solNACK         = 0x40
+	solDeactivating = 0x10
+)
+
+// errSOLActive is the completion code of Activate Payload if SOL is
+// active in another session
+const errSOLActive CompletionError = 0x80
+
+var (
+	// solRetransmit is the time to wait for the acknowledgement of SOL
+	// data before it is sent again
+	solRetransmit = 500 * time.Millisecond
+	// solRetries is the number of times SOL data is sent again
+	solRetries = 5
+	// solKeepalive is the interval in which a command is sent to keep
+	// the session of an idle SOL payload open
+	solKeepalive = 30 * time.Second
+)
+
+// solAck is the acknowledgement of a SOL packet
+type solAck struct {
+	seq      byte
+	accepted byte
+	nack     bool
+}
+
+/*
+SOL is an active serial over LAN Payload: the serial output of the
+system is read and input is written to the serial port. The SOL payload
+owns the session; closing it deactivates the payload and closes the
+session.
+*/
+type SOL struct {
+	client  *Client
+	reader  *io.PipeReader
+	writer  *io.PipeWriter
+	acks    chan solAck
+	done    chan struct{}
+	wg      sync.WaitGroup
+	maxData int
+
+	writeLock sync.Mutex
+	seq       byte
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// ActivateSOL activates the SOL payload in the session
+func (client *Client) ActivateSOL() (*SOL, error) {
+	data, err := client.Command(NetFnApp, cmdActivatePayload, []byte{rmcp.PayloadSOL, 0x01, rmcp.FlagEncrypted | rmcp.FlagAuthenticated, 0x00, 0x00, 0x00})
+	if errors.Is(err, errSOLActive) {
+		return nil, fmt.Errorf("SOL is already active in another session")
+	} else if err != nil {
+		return nil, fmt.Errorf("could not activate SOL: %w", err)
+	}
+	if len(data) < 10 {
+		return nil, rmcp.ErrShortPacket
+	}
+	if port := binary.LittleEndian.Uint16(data[8:]); port != 0 {
+		if _, bmcPort, err := net.SplitHostPort(client.address); err == nil && bmcPort != strconv.Itoa(int(port)) {
+			client.deactivateSOL()
+			return nil, fmt.Errorf("SOL on the separate port %d is not supported", port)
+		}
+	}
+	// the inbound payload size includes the four byte SOL header
+	maxData := int(binary.LittleEndian.Uint16(data[4:])) - 4
+	if maxData <= 0 || maxData > 200 {
+		maxData = 200
+	}
+	reader, writer := io.Pipe()
+	sol := &SOL{
+		client:  client,
+		reader:  reader,
+		writer:  writer,
+		acks:    make(chan solAck, 16),
+		done:    make(chan struct{}),
+		maxData: maxData,
+	}
+	// the payload is active until it is closed
+	client.deadline = time.Time{}
+	if err := client.conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	sol.wg.Add(2)
+	go sol.receive()
+	go sol.keepalive()
+	return sol, nil
+}
+
+func (client *Client) deactivateSOL() {
+	_, _ = client.Command(NetFnApp, cmdDeactivatePayload, []byte{rmcp.PayloadSOL, 0x01, 0x00, 0x00, 0x00, 0x00})
+}
+
+// sendSOL sends a SOL packet
+func (sol *SOL) sendSOL(seq, ack, accepted byte, data []byte) error {
+	payload := append([]byte{seq, ack, accepted, 0x00}, data...)
+	return sol.client.send(rmcp.Packet{Type: rmcp.PayloadSOL, Payload: payload})
+}
+
+// receive receives the SOL packets of the BMC: data is acknowledged and
+// passed to the reader, acknowledgements are passed to the writer
+func (sol *SOL) receive() {
+	defer sol.wg.Done()
+	buf := make([]byte, 1024)
+	var last byte
+	for {
+		n, err := sol.client.conn.Read(buf)
+		if err != nil {
+			select {
+			case <-sol.done:
+			default:
+				sol.writer.CloseWithError(err)
+			}
+			return
+		}
+		p, err := rmcp.Decode(buf[:n], sol.client.keys)
+		if err != nil || p.SessionID != sol.client.consoleID || p.Type != rmcp.PayloadSOL || len(p.Payload) < 4 {
+			continue
+		}
+		seq, ack, accepted, status := p.Payload[0], p.Payload[1], p.Payload[2], p.Payload[3]
+		data := p.Payload[4:]
+		if ack != 0 {
+			select {
+			case sol.acks <- solAck{seq: ack, accepted: accepted, nack: status&solNACK != 0}:
+			default:
+			}
+		}
+		if seq != 0 {
+			_ = sol.sendSOL(0, seq, byte(len(data)), nil)
+			// a packet which is sent again because the acknowledgement got
+			// lost has the same sequence number
+			if seq != last && len(data) > 0 {
+				if _, err := sol.writer.Write(data); err != nil {
+					return
+				}
+			}
+			last = seq
+		}
+		if status&solDeactivating != 0 {
+			sol.writer.Close()
+			return
+		}
+	}
+}
+
+// keepalive sends a command in the interval of solKeepalive, so that
+// the BMC doesn't close the session of an idle console. The response
+// is dropped by receive.
+func (sol *SOL) keepalive() {
+	defer sol.wg.Done()
+	ticker := time.NewTicker(solKeepalive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sol.done:
+			return
+		case <-ticker.C:
+			request := sol.client.request(NetFnApp, 0, cmdGetDeviceID, nil)
+			_ = sol.client.send(rmcp.Packet{Type: rmcp.PayloadIPMI, Payload: request.Encode()})
+		}
+	}
+}
+
+// Read reads the serial output of the system
+func (sol *SOL) Read(p []byte) (int, error) {
+	return sol.reader.Read(p)
+}
+
+// Write writes p to the serial port of the system, waiting for the BMC
+// to acknowledge it
+func (sol *SOL) Write(p []byte) (int, error) {
+	sol.writeLock.Lock()
+	defer sol.writeLock.Unlock()
+	written := 0
+	for written < len(p) {
+		chunk := p[written:min(len(p), written+sol.maxData)]
+		accepted, err := sol.transmit(chunk)
+		if err != nil {
+			return written, err
+		}
+		written += accepted
+	}
+	return written, nil
+}
+
+// transmit sends data until the BMC accepts at least a part of it and
+// returns the number of accepted bytes
+func (sol *SOL) transmit(data []byte) (int, error) {
+	sol.seq = sol.seq%15 + 1
+	for try := 0; try <= solRetries; try++ {
+		if err := sol.sendSOL(sol.seq, 0, 0, data); err != nil {
+			return 0, err
+		}
+		timeout := time.After(solRetransmit)
+	wait:
+		for {
+			select {
+			case <-sol.done:
+				return 0, io.ErrClosedPipe
+			case <-timeout:
+				break wait
+			case ack := <-sol.acks:
+				if ack.seq != sol.seq {
+					continue
+				}
+				if ack.nack {
+					break wait
+				}
+				if ack.accepted == 0 || int(ack.accepted) > len(data) {
+					return len(data), nil
+				}
+				return int(ack.accepted), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("SOL data not acknowledged by %s", sol.client.address)
+}
+
+// Close deactivates the SOL payload and closes the session
+func (sol *SOL) Close() error {
+	sol.closeOnce.Do(func() {
+		close(sol.done)
+		// stop receive, the session is needed for the deactivation
+		_ = sol.client.conn.SetReadDeadline(time.Now())
+		sol.wg.Wait()
+		sol.writer.Close()
+		sol.client.deactivateSOL()
+		sol.closeErr = sol.client.Close()
+		if errors.Is(sol.closeErr, os.ErrDeadlineExceeded) {
+			sol.closeErr = nil
+		}
+	})
+	return sol.closeErr
+}
@@ -0,0 +1,67 @@
+package ipmi
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/internal/rmcp"
+)
+
+// commands of the App network function which manage the users
+const (
+	cmdGetUserAccess   = 0x44
+	cmdGetUserName     = 0x46
+	cmdSetUserPassword = 0x47
+)
+
+const (
+	// channelCurrent is the channel the request is received on
+	channelCurrent = 0x0e
+	// userPasswordSet is the operation of Set User Password which sets
+	// the password
+	userPasswordSet = 0x02
+	// userPassword20 marks a password stored with 20 instead of 16 bytes
+	userPassword20 = 0x80
+)
+
+// UserID returns the ID of the user with the given name
+func (client *Client) UserID(name string) (byte, error) {
+	data, err := client.Command(NetFnApp, cmdGetUserAccess, []byte{channelCurrent, 0x01})
+	if err != nil {
+		return 0, fmt.Errorf("could not get user access: %w", err)
+	}
+	if len(data) < 1 {
+		return 0, rmcp.ErrShortPacket
+	}
+	maxID := data[0] & 0x3f
+	for id := byte(1); id <= maxID; id++ {
+		data, err := client.Command(NetFnApp, cmdGetUserName, []byte{id})
+		if err != nil {
+			continue
+		}
+		if string(bytes.TrimRight(data, "\x00")) == name {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("user %s not found", name)
+}
+
+/*
+SetUserPassword sets the password of the user with the given ID. A
+password longer than 16 characters is stored as a 20 byte password,
+which requires IPMI v2.0 to log in.
+*/
+func (client *Client) SetUserPassword(id byte, password string) error {
+	if len(password) > 20 {
+		return fmt.Errorf("password longer than 20 characters")
+	}
+	size := 16
+	if len(password) > 16 {
+		size = 20
+		id |= userPassword20
+	}
+	data := append([]byte{id, userPasswordSet}, password...)
+	data = append(data, make([]byte, size-len(password))...)
+	_, err := client.Command(NetFnApp, cmdSetUserPassword, data)
+	return err
+}
@@ -17,13 +17,19 @@ Checks if for NodeConf all values can be parsed according to their type.
 func (nodeConf *Node) Check() (err error) {
 	nodeInfoType := reflect.TypeOf(nodeConf)
 	nodeInfoVal := reflect.ValueOf(nodeConf)
-	return check(nodeInfoType, nodeInfoVal)
+	if err = check(nodeInfoType, nodeInfoVal); err != nil {
+		return err
+	}
+	return nodeConf.CheckFields()
 }
 
 func (profileConf *Profile) Check() (err error) {
 	profileInfoType := reflect.TypeOf(profileConf)
 	profileInfoVal := reflect.ValueOf(profileConf)
-	return check(profileInfoType, profileInfoVal)
+	if err = check(profileInfoType, profileInfoVal); err != nil {
+		return err
+	}
+	return profileConf.CheckFields()
 }
 
 func check(infoType reflect.Type, infoVal reflect.Value) (err error) {
@@ -20,7 +20,13 @@ func New() (NodesYaml, error) {
 	if err != nil {
 		return NodesYaml{}, err
 	}
-	return Parse(data)
+	nodeList, err := Parse(data)
+	if err != nil {
+		return nodeList, err
+	}
+	nodeList.sourceFile = nodesConf
+	nodeList.sourceHash = contentHash(data)
+	return nodeList, nil
 }
 
 // Parse constructs a new nodeDb object from an input YAML
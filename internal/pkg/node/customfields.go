@@ -0,0 +1,327 @@
+package node
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+	"github.com/warewulf/warewulf/internal/pkg/wwtype"
+	"gopkg.in/yaml.v3"
+)
+
+// customFieldTypes are the valid types of custom fields
+var customFieldTypes = []string{"string", "IP", "MAC", "uint", "bool", "enum", "list"}
+
+/*
+CustomField is the declaration of a custom field. Custom fields are
+site-defined node and profile fields, declared in fields.conf with a
+type, an optional default value and a help text:
+
+	rack:
+	  type: string
+	  help: Rack the node is mounted in
+	role:
+	  type: enum
+	  values: [compute, login, storage]
+	  default: compute
+	bmcvlan:
+	  type: uint
+
+Their values are stored as strings in the Fields map of nodes and
+profiles, can be set with --x-<name> flags and are validated according
+to their type. Overlay templates get typed values with the CustomField
+method.
+*/
+type CustomField struct {
+	Type    string   `yaml:"type,omitempty"`
+	Default string   `yaml:"default,omitempty"`
+	Help    string   `yaml:"help,omitempty"`
+	Values  []string `yaml:"values,omitempty"`
+}
+
+var customFieldsCache struct {
+	sync.Mutex
+	file    string
+	modTime time.Time
+	fields  map[string]*CustomField
+}
+
+/*
+CustomFields returns the custom fields declared in fields.conf. If the
+file doesn't exist, no custom fields are declared. The declarations are
+cached until the file changes.
+*/
+func CustomFields() map[string]*CustomField {
+	fieldsConf := warewulfconf.Get().Paths.FieldsConf()
+	info, err := os.Stat(fieldsConf)
+	if err != nil {
+		return map[string]*CustomField{}
+	}
+	customFieldsCache.Lock()
+	defer customFieldsCache.Unlock()
+	if customFieldsCache.file == fieldsConf && customFieldsCache.modTime.Equal(info.ModTime()) {
+		return customFieldsCache.fields
+	}
+	fields, err := ReadCustomFields(fieldsConf)
+	if err != nil {
+		wwlog.Warn("could not read custom fields: %s", err)
+		fields = map[string]*CustomField{}
+	}
+	customFieldsCache.file = fieldsConf
+	customFieldsCache.modTime = info.ModTime()
+	customFieldsCache.fields = fields
+	return fields
+}
+
+// ReadCustomFields reads and validates the custom field declarations
+// from the given file
+func ReadCustomFields(fieldsConf string) (fields map[string]*CustomField, err error) {
+	data, err := os.ReadFile(fieldsConf)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%s: %w", fieldsConf, err)
+	}
+	if fields == nil {
+		fields = map[string]*CustomField{}
+	}
+	for name, field := range fields {
+		if field == nil {
+			field = new(CustomField)
+			fields[name] = field
+		}
+		if field.Type == "" {
+			field.Type = "string"
+		}
+		if !util.InSlice(customFieldTypes, field.Type) {
+			return nil, fmt.Errorf("%s: custom field %s has invalid type %s, must be one of %s", fieldsConf, name, field.Type, strings.Join(customFieldTypes, ", "))
+		}
+		if field.Type == "enum" && len(field.Values) == 0 {
+			return nil, fmt.Errorf("%s: custom field %s of type enum has no values", fieldsConf, name)
+		}
+		if field.Default != "" {
+			if _, err := field.Check(field.Default); err != nil {
+				return nil, fmt.Errorf("%s: invalid default for custom field %s: %w", fieldsConf, name, err)
+			}
+		}
+	}
+	return fields, nil
+}
+
+/*
+Check validates the value for the custom field and returns it in its
+canonical form, e.g. "yes" becomes "true" for bool fields.
+*/
+func (field *CustomField) Check(value string) (string, error) {
+	if value == "" || util.InSlice(wwtype.GetUnsetVerbs(), value) {
+		return value, nil
+	}
+	switch field.Type {
+	case "enum":
+		if !util.InSlice(field.Values, value) {
+			return value, fmt.Errorf("%s is not one of %s", value, strings.Join(field.Values, ", "))
+		}
+	case "list":
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), nil
+	case "IP", "MAC", "uint", "bool":
+		niceValue, err := checker(value, field.Type)
+		if err != nil {
+			return value, err
+		}
+		if niceValue != "" {
+			return niceValue, nil
+		}
+	}
+	return value, nil
+}
+
+// typed returns the value converted to the type of the custom field
+func (field *CustomField) typed(value string) interface{} {
+	switch field.Type {
+	case "IP":
+		return net.ParseIP(value)
+	case "MAC":
+		mac, _ := net.ParseMAC(value)
+		return mac
+	case "uint":
+		number, _ := strconv.ParseUint(value, 10, 64)
+		return number
+	case "bool":
+		return wwtype.WWbool(value).Bool()
+	case "list":
+		if value == "" {
+			return []string{}
+		}
+		return strings.Split(value, ",")
+	}
+	return value
+}
+
+/*
+CustomField returns the value of the custom field with the given name,
+converted to its declared type: net.IP for IP, net.HardwareAddr for
+MAC, uint64 for uint, bool for bool, []string for list and string for
+string and enum fields. Aimed for the use in templates.
+*/
+func (profile Profile) CustomField(name string) interface{} {
+	value := profile.Fields[name]
+	if field, ok := CustomFields()[name]; ok {
+		if value == "" {
+			value = field.Default
+		}
+		return field.typed(value)
+	}
+	return value
+}
+
+// CheckFields validates the values of all custom fields against
+// their declarations in fields.conf
+func (profile *Profile) CheckFields() error {
+	declared := CustomFields()
+	var names []string
+	for name := range profile.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := declared[name]
+		if !ok {
+			return fmt.Errorf("custom field %s is not declared in %s", name, warewulfconf.Get().Paths.FieldsConf())
+		}
+		if _, err := field.Check(profile.Fields[name]); err != nil {
+			return fmt.Errorf("custom field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+/*
+applyCustomFields sets the defaults of custom fields which aren't set
+by the node or any of its profiles, and appends list fields over all
+levels like built-in list fields.
+*/
+func (config *NodesYaml) applyCustomFields(node *Node, fields fieldMap) {
+	declared := CustomFields()
+	if len(declared) == 0 {
+		return
+	}
+	var names []string
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := declared[name]
+		fieldName := fmt.Sprintf("Fields[%s]", name)
+		if node.Fields == nil {
+			node.Fields = make(map[string]string)
+		}
+		if field.Type == "list" {
+			var items, sources []string
+			addLevel := func(source string, values map[string]string) {
+				if value := values[name]; value != "" && !util.InSlice(wwtype.GetUnsetVerbs(), value) {
+					items = append(items, strings.Split(value, ",")...)
+					sources = append(sources, source)
+				}
+			}
+			for _, profileID := range config.getNodeProfiles(node.id) {
+				if profile, ok := config.NodeProfiles[profileID]; ok {
+					addLevel(profileID, profile.Fields)
+				}
+			}
+			if own, ok := config.Nodes[node.id]; ok {
+				addLevel(node.id, own.Fields)
+			}
+			if len(items) > 0 {
+				node.Fields[name] = strings.Join(items, ",")
+				source := strings.Join(sources, ",")
+				if len(sources) == 1 && sources[0] == node.id {
+					source = ""
+				}
+				delete(fields, fieldName)
+				fields.Set(fieldName, source, node.Fields[name])
+			}
+		}
+		if node.Fields[name] == "" && field.Default != "" {
+			node.Fields[name] = field.Default
+			fields.Set(fieldName, "(default)", field.Default)
+		}
+	}
+}
+
+// customFieldValue is a pflag.Value which validates the value of a
+// custom field and stores it in a Fields map
+type customFieldValue struct {
+	fields *map[string]string
+	name   string
+	field  *CustomField
+}
+
+func (value *customFieldValue) String() string {
+	if *value.fields == nil {
+		return ""
+	}
+	return (*value.fields)[value.name]
+}
+
+func (value *customFieldValue) Set(str string) error {
+	niceValue, err := value.field.Check(str)
+	if err != nil {
+		return err
+	}
+	if *value.fields == nil {
+		*value.fields = make(map[string]string)
+	}
+	(*value.fields)[value.name] = niceValue
+	return nil
+}
+
+func (value *customFieldValue) Type() string {
+	return value.field.Type
+}
+
+var _ pflag.Value = (*customFieldValue)(nil)
+
+// createCustomFlags creates a --x-<name> flag for every custom field
+func createCustomFlags(fields *map[string]string, baseCmd *cobra.Command) {
+	declared := CustomFields()
+	var names []string
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := declared[name]
+		help := field.Help
+		if help == "" {
+			help = fmt.Sprintf("Set the custom field %s", name)
+		}
+		if field.Type == "enum" {
+			help += fmt.Sprintf(" (%s)", strings.Join(field.Values, ", "))
+		}
+		if field.Default != "" {
+			help += fmt.Sprintf(" (default: %s)", field.Default)
+		}
+		baseCmd.PersistentFlags().Var(&customFieldValue{fields: fields, name: name, field: field}, "x-"+name, help)
+		if field.Type == "bool" {
+			baseCmd.Flag("x-" + name).NoOptDefVal = "true"
+		}
+	}
+}
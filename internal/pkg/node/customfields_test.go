@@ -0,0 +1,158 @@
+package node
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+const testFieldsConf = `
+rack:
+  help: Rack the node is mounted in
+role:
+  type: enum
+  values: [compute, login]
+  default: compute
+bmcvlan:
+  type: uint
+mgmt:
+  type: IP
+licensed:
+  type: bool
+features:
+  type: list
+`
+
+func Test_ReadCustomFields(t *testing.T) {
+	tests := map[string]struct {
+		conf string
+		err  string
+	}{
+		"valid": {
+			conf: testFieldsConf,
+		},
+		"invalid type": {
+			conf: "rack:\n  type: float\n",
+			err:  "custom field rack has invalid type float",
+		},
+		"enum without values": {
+			conf: "role:\n  type: enum\n",
+			err:  "custom field role of type enum has no values",
+		},
+		"invalid default": {
+			conf: "bmcvlan:\n  type: uint\n  default: abc\n",
+			err:  "invalid default for custom field bmcvlan",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			env.WriteFile("etc/warewulf/fields.conf", tt.conf)
+			fields, err := ReadCustomFields(env.GetPath("etc/warewulf/fields.conf"))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "string", fields["rack"].Type)
+				assert.Equal(t, "compute", fields["role"].Default)
+			}
+		})
+	}
+}
+
+func Test_CustomFieldCheck(t *testing.T) {
+	tests := []struct {
+		field CustomField
+		value string
+		want  string
+		err   bool
+	}{
+		{field: CustomField{Type: "string"}, value: "r01", want: "r01"},
+		{field: CustomField{Type: "enum", Values: []string{"a", "b"}}, value: "b", want: "b"},
+		{field: CustomField{Type: "enum", Values: []string{"a", "b"}}, value: "c", err: true},
+		{field: CustomField{Type: "uint"}, value: "42", want: "42"},
+		{field: CustomField{Type: "uint"}, value: "-1", err: true},
+		{field: CustomField{Type: "bool"}, value: "yes", want: "true"},
+		{field: CustomField{Type: "bool"}, value: "maybe", err: true},
+		{field: CustomField{Type: "IP"}, value: "10.0.0.1", want: "10.0.0.1"},
+		{field: CustomField{Type: "IP"}, value: "10.0.0", err: true},
+		{field: CustomField{Type: "MAC"}, value: "AA:BB:CC:DD:EE:FF", want: "aa:bb:cc:dd:ee:ff"},
+		{field: CustomField{Type: "list"}, value: "a, b,,c", want: "a,b,c"},
+		{field: CustomField{Type: "uint"}, value: "UNSET", want: "UNSET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field.Type+":"+tt.value, func(t *testing.T) {
+			got, err := tt.field.Check(tt.value)
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_MergeCustomFields(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/fields.conf", testFieldsConf)
+
+	nodes, err := Parse([]byte(`
+nodeprofiles:
+  default:
+    fields:
+      rack: r01
+      features: ib
+  gpu:
+    fields:
+      features: gpu
+      licensed: "true"
+nodes:
+  n01:
+    profiles:
+    - default
+    - gpu
+    fields:
+      rack: r02
+      bmcvlan: "42"
+      mgmt: 10.0.0.1
+      features: nvme
+  n02:
+    profiles:
+    - default
+`))
+	assert.NoError(t, err)
+
+	n01, fields, err := nodes.MergeNode("n01")
+	assert.NoError(t, err)
+	assert.Equal(t, "r02", n01.Fields["rack"])
+	assert.Equal(t, "SUPERSEDED", fields.Source("Fields[rack]"))
+	assert.Equal(t, "ib,gpu,nvme", n01.Fields["features"])
+	assert.Equal(t, "default,gpu,n01", fields.Source("Fields[features]"))
+	assert.Equal(t, "compute", n01.Fields["role"])
+	assert.Equal(t, "(default)", fields.Source("Fields[role]"))
+
+	assert.Equal(t, "r02", n01.CustomField("rack"))
+	assert.Equal(t, "compute", n01.CustomField("role"))
+	assert.Equal(t, uint64(42), n01.CustomField("bmcvlan"))
+	assert.Equal(t, net.ParseIP("10.0.0.1"), n01.CustomField("mgmt"))
+	assert.Equal(t, true, n01.CustomField("licensed"))
+	assert.Equal(t, []string{"ib", "gpu", "nvme"}, n01.CustomField("features"))
+
+	n02, fields, err := nodes.MergeNode("n02")
+	assert.NoError(t, err)
+	assert.Equal(t, "ib", n02.Fields["features"])
+	assert.Equal(t, "default", fields.Source("Fields[features]"))
+	assert.Equal(t, false, n02.CustomField("licensed"))
+	assert.Equal(t, uint64(0), n02.CustomField("bmcvlan"))
+
+	n02Only, _ := nodes.GetNodeOnly("n02")
+	n02Only.Fields = map[string]string{"bmcvlan": "abc"}
+	assert.ErrorContains(t, n02Only.CheckFields(), "custom field bmcvlan")
+	n02Only.Fields = map[string]string{"unknown": "x"}
+	assert.ErrorContains(t, n02Only.CheckFields(), "custom field unknown is not declared")
+}
@@ -17,17 +17,25 @@ Structure of which goes to disk
 type NodesYaml struct {
 	NodeProfiles map[string]*Profile
 	Nodes        map[string]*Node
+	// file and content hash the database was read from, used to detect
+	// concurrent modifications when persisting
+	sourceFile string
+	sourceHash string
 }
 
 /*
 Node is the datastructure describing a node and a profile which in disk format.
 */
 type Node struct {
-	id    string
-	valid bool // Is set true, if called by the constructor
+	id       string
+	valid    bool     // Is set true, if called by the constructor
+	profiles []string // all profiles merged into the node, including nested ones
 	// exported values
 	Discoverable wwtype.WWbool     `yaml:"discoverable,omitempty" lopt:"discoverable" sopt:"e" comment:"Make discoverable in given network (true/false)"`
 	AssetKey     string            `yaml:"asset key,omitempty" lopt:"asset" comment:"Set the node's Asset tag (key)"`
+	State        string            `yaml:"state,omitempty"`         // lifecycle state, see NodeStates
+	StateReason  string            `yaml:"state reason,omitempty"`  // why the state was set
+	StateChanged string            `yaml:"state changed,omitempty"` // when the state was set (RFC 3339)
 	Profile      `yaml:"-,inline"` // include all values set in the profile, but inline them in yaml output if these are part of Node
 }
 
@@ -50,23 +58,28 @@ type Profile struct {
 	Root           string                 `yaml:"root,omitempty" lopt:"root" comment:"Define the rootfs" `
 	NetDevs        map[string]*NetDev     `yaml:"network devices,omitempty"`
 	Tags           map[string]string      `yaml:"tags,omitempty"`
+	Fields         map[string]string      `yaml:"fields,omitempty"`
 	PrimaryNetDev  string                 `yaml:"primary network,omitempty" lopt:"primarynet" sopt:"p" comment:"Set the primary network interface"`
 	Disks          map[string]*Disk       `yaml:"disks,omitempty"`
 	FileSystems    map[string]*FileSystem `yaml:"filesystems,omitempty"`
 }
 
 type IpmiConf struct {
-	UserName   string            `yaml:"username,omitempty" lopt:"ipmiuser" comment:"Set the IPMI username"`
-	Password   string            `yaml:"password,omitempty" lopt:"ipmipass" comment:"Set the IPMI password"`
-	Ipaddr     net.IP            `yaml:"ipaddr,omitempty" lopt:"ipmiaddr" comment:"Set the IPMI IP address" type:"IP"`
-	Gateway    net.IP            `yaml:"gateway,omitempty" lopt:"ipmigateway" comment:"Set the IPMI gateway" type:"IP"`
-	Netmask    net.IP            `yaml:"netmask,omitempty" lopt:"ipminetmask" comment:"Set the IPMI netmask" type:"IP"`
-	Port       string            `yaml:"port,omitempty" lopt:"ipmiport" comment:"Set the IPMI port"`
-	Interface  string            `yaml:"interface,omitempty" lopt:"ipmiinterface" comment:"Set the node's IPMI interface (defaults: 'lan')"`
-	EscapeChar string            `yaml:"escapechar,omitempty" lopt:"ipmiescapechar" comment:"Set the IPMI escape character (defaults: '~')"`
-	Write      wwtype.WWbool     `yaml:"write,omitempty" lopt:"ipmiwrite" comment:"Enable the write of impi configuration (true/false)"`
-	Template   string            `yaml:"template,omitempty" lopt:"ipmitemplate" comment:"template used for ipmi command"`
-	Tags       map[string]string `yaml:"tags,omitempty"`
+	UserName    string            `yaml:"username,omitempty" lopt:"ipmiuser" comment:"Set the IPMI username"`
+	Password    string            `yaml:"password,omitempty" lopt:"ipmipass" comment:"Set the IPMI password"`
+	Ipaddr      net.IP            `yaml:"ipaddr,omitempty" lopt:"ipmiaddr" comment:"Set the IPMI IP address" type:"IP"`
+	Pool        string            `yaml:"pool,omitempty" lopt:"ipmipool" comment:"Allocate the IPMI IP address from the given address pool"`
+	Gateway     net.IP            `yaml:"gateway,omitempty" lopt:"ipmigateway" comment:"Set the IPMI gateway" type:"IP"`
+	Netmask     net.IP            `yaml:"netmask,omitempty" lopt:"ipminetmask" comment:"Set the IPMI netmask" type:"IP"`
+	Port        string            `yaml:"port,omitempty" lopt:"ipmiport" comment:"Set the IPMI port"`
+	Interface   string            `yaml:"interface,omitempty" lopt:"ipmiinterface" comment:"Set the node's IPMI interface (defaults: 'lan')"`
+	EscapeChar  string            `yaml:"escapechar,omitempty" lopt:"ipmiescapechar" comment:"Set the IPMI escape character (defaults: '~')"`
+	Write       wwtype.WWbool     `yaml:"write,omitempty" lopt:"ipmiwrite" comment:"Enable the write of impi configuration (true/false)"`
+	Template    string            `yaml:"template,omitempty" lopt:"ipmitemplate" comment:"template used for ipmi command"`
+	Backend     string            `yaml:"backend,omitempty" lopt:"ipmibackend" comment:"Set the BMC backend: ipmi (template based, default), lanplus (native IPMI) or redfish"`
+	Insecure    wwtype.WWbool     `yaml:"insecure,omitempty" lopt:"ipmiinsecure" comment:"Don't verify the TLS certificate of the BMC (true/false)"`
+	RedfishPort string            `yaml:"redfish port,omitempty" lopt:"redfishport" comment:"Set the port of the Redfish service (default: 443)" type:"uint"`
+	Tags        map[string]string `yaml:"tags,omitempty"`
 }
 
 type KernelConf struct {
@@ -75,18 +88,26 @@ type KernelConf struct {
 }
 
 type NetDev struct {
-	Type    string            `yaml:"type,omitempty" lopt:"type" sopt:"T" comment:"Set device type of given network"`
-	OnBoot  wwtype.WWbool     `yaml:"onboot,omitempty" lopt:"onboot" comment:"Enable/disable network device (true/false)"`
-	Device  string            `yaml:"device,omitempty" lopt:"netdev" sopt:"N" comment:"Set the device for given network"`
-	Hwaddr  string            `yaml:"hwaddr,omitempty" lopt:"hwaddr" sopt:"H" comment:"Set the device's HW address for given network" type:"MAC"`
-	Ipaddr  net.IP            `yaml:"ipaddr,omitempty" comment:"IPv4 address in given network" sopt:"I" lopt:"ipaddr" type:"IP"`
-	Ipaddr6 net.IP            `yaml:"ip6addr,omitempty" lopt:"ipaddr6" comment:"IPv6 address" type:"IP"`
-	Prefix  net.IP            `yaml:"prefix,omitempty"`
-	Netmask net.IP            `yaml:"netmask,omitempty" lopt:"netmask" sopt:"M" comment:"Set the networks netmask" type:"IP"`
-	Gateway net.IP            `yaml:"gateway,omitempty" lopt:"gateway" sopt:"G" comment:"Set the node's network device gateway" type:"IP"`
-	MTU     string            `yaml:"mtu,omitempty" lopt:"mtu" comment:"Set the mtu" type:"uint"`
-	Tags    map[string]string `yaml:"tags,omitempty"`
-	primary bool
+	Type        string            `yaml:"type,omitempty" lopt:"type" sopt:"T" comment:"Set device type of given network"`
+	Network     string            `yaml:"network,omitempty" lopt:"network" comment:"Inherit netmask, gateway, MTU, DNS and VLAN id from the named network"`
+	OnBoot      wwtype.WWbool     `yaml:"onboot,omitempty" lopt:"onboot" comment:"Enable/disable network device (true/false)"`
+	Device      string            `yaml:"device,omitempty" lopt:"netdev" sopt:"N" comment:"Set the device for given network"`
+	Hwaddr      string            `yaml:"hwaddr,omitempty" lopt:"hwaddr" sopt:"H" comment:"Set the device's HW address for given network" type:"MAC"`
+	Ipaddr      net.IP            `yaml:"ipaddr,omitempty" comment:"IPv4 address in given network" sopt:"I" lopt:"ipaddr" type:"IP"`
+	Pool        string            `yaml:"pool,omitempty" lopt:"pool" comment:"Allocate the IPv4 address from the given address pool"`
+	Ipaddr6     net.IP            `yaml:"ip6addr,omitempty" lopt:"ipaddr6" comment:"IPv6 address" type:"IP"`
+	Prefix      net.IP            `yaml:"prefix,omitempty"`
+	Netmask     net.IP            `yaml:"netmask,omitempty" lopt:"netmask" sopt:"M" comment:"Set the networks netmask" type:"IP"`
+	Gateway     net.IP            `yaml:"gateway,omitempty" lopt:"gateway" sopt:"G" comment:"Set the node's network device gateway" type:"IP"`
+	MTU         string            `yaml:"mtu,omitempty" lopt:"mtu" comment:"Set the mtu" type:"uint"`
+	Parent      string            `yaml:"parent,omitempty" lopt:"parent" comment:"Set the parent network device of a VLAN"`
+	VlanId      string            `yaml:"vlan id,omitempty" lopt:"vlanid" comment:"Set the VLAN id" type:"uint"`
+	Members     []string          `yaml:"members,omitempty" lopt:"members" comment:"Set the member network devices of a bond (comma separated)"`
+	BondMode    string            `yaml:"bond mode,omitempty" lopt:"bondmode" comment:"Set the bond mode, e.g. 802.3ad or active-backup"`
+	BondOptions string            `yaml:"bond options,omitempty" lopt:"bondopts" comment:"Set additional bond options, e.g. miimon=100,lacp_rate=fast"`
+	BridgePorts []string          `yaml:"bridge ports,omitempty" lopt:"bridgeports" comment:"Set the ports of a bridge (comma separated)"`
+	Tags        map[string]string `yaml:"tags,omitempty"`
+	primary     bool
 }
 
 /*
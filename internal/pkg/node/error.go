@@ -4,3 +4,4 @@ import "errors"
 
 var ErrNotFound = errors.New("node/profile not found")
 var ErrNoUnconfigured = errors.New("no unconfigured node")
+var ErrConflict = errors.New("node configuration was modified by someone else since it was loaded")
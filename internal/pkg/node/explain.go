@@ -0,0 +1,130 @@
+package node
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+// FieldLevel is the value of a field at a single level of the
+// resolution chain of a node: one of its profiles, the node itself or
+// a named network.
+type FieldLevel struct {
+	Source  string   `json:"source" yaml:"source"`
+	Value   string   `json:"value" yaml:"value"`
+	Negated []string `json:"negated,omitempty" yaml:"negated,omitempty"`
+	Used    bool     `json:"used" yaml:"used"`
+}
+
+// FieldExplanation explains how the merged value of a field was
+// resolved. Levels are listed in the order they are merged; for
+// scalar fields the last level wins, list fields are appended at
+// every level.
+type FieldExplanation struct {
+	Field  string       `json:"field" yaml:"field"`
+	Value  string       `json:"value" yaml:"value"`
+	Source string       `json:"source,omitempty" yaml:"source,omitempty"`
+	List   bool         `json:"list,omitempty" yaml:"list,omitempty"`
+	Levels []FieldLevel `json:"levels" yaml:"levels"`
+}
+
+// NodeExplanation is the resolution chain of all fields of a node
+type NodeExplanation struct {
+	Node     string             `json:"node" yaml:"node"`
+	Profiles []string           `json:"profiles" yaml:"profiles"`
+	Excluded []string           `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+	Fields   []FieldExplanation `json:"fields" yaml:"fields"`
+}
+
+/*
+ExplainNode returns the resolution chain for every field of the node
+with the given id: the profiles in the order they are merged, the
+value of each field at every profile and at the node itself,
+negations with '~' and which level provided the merged value.
+*/
+func (config *NodesYaml) ExplainNode(id string) (explanation NodeExplanation, err error) {
+	merged, fields, err := config.MergeNode(id)
+	if err != nil {
+		return explanation, err
+	}
+	explanation.Node = id
+	explanation.Profiles = config.getNodeProfiles(id)
+	explanation.Excluded = config.excludedProfiles(id)
+
+	levels := make(map[string][]FieldLevel)
+	lists := make(map[string]bool)
+	addLevels := func(source string, obj interface{}) {
+		for _, name := range listFields(obj) {
+			value, err := getNestedFieldValue(obj, name)
+			if err != nil || valueStr(value) == "" {
+				continue
+			}
+			level := FieldLevel{Source: source, Value: valueStr(value)}
+			if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String {
+				lists[name] = true
+				for i := 0; i < value.Len(); i++ {
+					if item := value.Index(i).String(); strings.HasPrefix(item, "~") {
+						level.Negated = append(level.Negated, strings.TrimPrefix(item, "~"))
+					}
+				}
+			}
+			levels[name] = append(levels[name], level)
+		}
+	}
+	for _, profileID := range explanation.Profiles {
+		if profile, err := config.GetProfile(profileID); err == nil {
+			addLevels(profileID, profile)
+		}
+	}
+	if node, err := config.GetNodeOnly(id); err == nil {
+		addLevels(id, node)
+	}
+
+	for _, name := range listFields(merged) {
+		field, ok := fields[name]
+		if !ok && len(levels[name]) == 0 {
+			continue
+		}
+		explained := FieldExplanation{Field: name, List: lists[name], Levels: levels[name]}
+		if ok {
+			explained.Value = field.Value
+			explained.Source = field.Source
+		} else {
+			explained.Value, _ = getNestedFieldString(merged, name)
+		}
+		if strings.HasPrefix(explained.Source, "network:") {
+			explained.Levels = append(explained.Levels, FieldLevel{Source: explained.Source, Value: explained.Value})
+		}
+		if explained.List {
+			for i := range explained.Levels {
+				explained.Levels[i].Used = true
+			}
+		} else if len(explained.Levels) > 0 {
+			explained.Levels[len(explained.Levels)-1].Used = true
+		}
+		explanation.Fields = append(explanation.Fields, explained)
+	}
+	return explanation, nil
+}
+
+// excludedProfiles returns the profiles which are negated with '~' by
+// the node or by one of its profiles
+func (config *NodesYaml) excludedProfiles(id string) (excluded []string) {
+	add := func(profiles []string) {
+		for _, profileID := range profiles {
+			if name := strings.TrimPrefix(profileID, "~"); name != profileID && !util.InSlice(excluded, name) {
+				excluded = append(excluded, name)
+			}
+		}
+	}
+	if node, ok := config.Nodes[id]; ok {
+		add(node.Profiles)
+	}
+	for _, profileID := range config.getNodeProfiles(id) {
+		if profile, ok := config.NodeProfiles[profileID]; ok {
+			add(profile.Profiles)
+		}
+	}
+	return excluded
+}
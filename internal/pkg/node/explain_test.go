@@ -0,0 +1,71 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExplainNode(t *testing.T) {
+	nodes, err := Parse([]byte(`
+nodeprofiles:
+  default:
+    kernel:
+      args: quiet crashkernel=no
+    runtime overlay:
+    - generic
+    profiles:
+    - legacy
+  legacy:
+    container name: old
+  gpu:
+    kernel:
+      args: quiet nouveau.modeset=0
+    runtime overlay:
+    - nvidia
+    - ~generic
+nodes:
+  n01:
+    profiles:
+    - default
+    - gpu
+    - ~legacy
+    kernel:
+      args: debug
+`))
+	assert.NoError(t, err)
+
+	explanation, err := nodes.ExplainNode("n01")
+	assert.NoError(t, err)
+	assert.Equal(t, "n01", explanation.Node)
+	assert.Equal(t, []string{"default", "gpu"}, explanation.Profiles)
+	assert.Equal(t, []string{"legacy"}, explanation.Excluded)
+
+	fields := make(map[string]FieldExplanation)
+	for _, field := range explanation.Fields {
+		fields[field.Field] = field
+	}
+
+	args := fields["Kernel.Args"]
+	assert.Equal(t, "debug", args.Value)
+	assert.Equal(t, "SUPERSEDED", args.Source)
+	assert.False(t, args.List)
+	assert.Equal(t, []FieldLevel{
+		{Source: "default", Value: "quiet crashkernel=no"},
+		{Source: "gpu", Value: "quiet nouveau.modeset=0"},
+		{Source: "n01", Value: "debug", Used: true},
+	}, args.Levels)
+
+	overlays := fields["RuntimeOverlay"]
+	assert.True(t, overlays.List)
+	assert.Equal(t, []FieldLevel{
+		{Source: "default", Value: "generic", Used: true},
+		{Source: "gpu", Value: "nvidia,~generic", Negated: []string{"generic"}, Used: true},
+	}, overlays.Levels)
+
+	_, ok := fields["ContainerName"]
+	assert.False(t, ok)
+
+	_, err = nodes.ExplainNode("n02")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -6,7 +6,11 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwtype"
 )
 
 // Field represents a single attribute field (typically to be used with a Node or a Profile),
@@ -92,6 +96,21 @@ func GetFieldList(obj interface{}) (fields []Field) {
 	return fields
 }
 
+/*
+GetField returns the value of a single field of a node or profile by its
+name as listed by GetFieldList, e.g. "Ipmi.Password" or "Tags[key]", or
+by the key of a selector term like "tag.key".
+*/
+func GetField(obj interface{}, name string) (string, error) {
+	return getNestedFieldString(obj, selectorField(name))
+}
+
+// SetField sets a single field of a node or profile, given as pointer,
+// by a name as accepted by GetField
+func SetField(obj interface{}, name, value string) error {
+	return setNestedFieldString(obj, selectorField(name), value)
+}
+
 var mapFieldElement *regexp.Regexp
 
 func init() {
@@ -129,8 +148,20 @@ func getNestedFieldValue(obj interface{}, name string) (value reflect.Value, err
 			err = fmt.Errorf("no value: %v", name)
 			return
 		}
+		if value.Kind() != reflect.Struct {
+			err = fmt.Errorf("no value: %v", name)
+			return
+		}
 		value = value.FieldByName(fieldName)
+		if !value.IsValid() {
+			err = fmt.Errorf("no such field: %v", name)
+			return
+		}
 		if key != "" {
+			if value.Kind() != reflect.Map {
+				err = fmt.Errorf("no value: %v", name)
+				return
+			}
 			value = value.MapIndex(reflect.ValueOf(key))
 			if !value.IsValid() {
 				err = fmt.Errorf("no value: %v", name)
@@ -153,6 +184,127 @@ func getNestedFieldString(obj interface{}, name string) (string, error) {
 	}
 }
 
+// setNestedFieldString sets a nested field, addressed as described for
+// getNestedFieldValue, from its string representation as returned by
+// getNestedFieldString. Nil pointers and maps along the path are
+// allocated. Values which are unset verbs reset the field to its zero
+// value, or remove the key of a map element.
+//
+// obj must be a pointer. Values are validated according to the type
+// tag of the field, like the values of command line flags.
+func setNestedFieldString(obj interface{}, name, str string) error {
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("cannot set %v: not a pointer", name)
+	}
+	value = value.Elem()
+
+	fieldNames := strings.Split(name, ".")
+	for i, fieldName := range fieldNames {
+		var key string
+		fieldName, key = parseMapField(fieldName)
+		last := i == len(fieldNames)-1
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			return fmt.Errorf("no such field: %v", name)
+		}
+		field, ok := value.Type().FieldByName(fieldName)
+		if !ok || !field.IsExported() || field.Anonymous {
+			return fmt.Errorf("no such field: %v", name)
+		}
+		value = value.FieldByIndex(field.Index)
+		if key == "" {
+			if last {
+				return setValueString(value, field.Tag.Get("type"), str)
+			}
+			continue
+		}
+
+		if value.Kind() != reflect.Map {
+			return fmt.Errorf("no such field: %v", name)
+		}
+		elementType := value.Type().Elem()
+		mapKey := reflect.ValueOf(key)
+		if last {
+			if elementType.Kind() != reflect.String {
+				return fmt.Errorf("no such field: %v", name)
+			}
+			if util.InSlice(wwtype.GetUnsetVerbs(), str) {
+				if !value.IsNil() {
+					value.SetMapIndex(mapKey, reflect.Value{})
+				}
+				return nil
+			}
+			if value.IsNil() {
+				value.Set(reflect.MakeMap(value.Type()))
+			}
+			value.SetMapIndex(mapKey, reflect.ValueOf(str).Convert(elementType))
+			return nil
+		}
+		if elementType.Kind() != reflect.Pointer || elementType.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("no such field: %v", name)
+		}
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		element := value.MapIndex(mapKey)
+		if !element.IsValid() || element.IsNil() {
+			element = reflect.New(elementType.Elem())
+			value.SetMapIndex(mapKey, element)
+		}
+		value = element
+	}
+	return nil
+}
+
+// setValueString sets a single value from its string representation.
+// Slices are split at commas.
+func setValueString(value reflect.Value, valType, str string) error {
+	if util.InSlice(wwtype.GetUnsetVerbs(), str) {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	if value.Kind() == reflect.Bool || value.Type() == reflect.TypeOf(wwtype.WWbool("")) {
+		valType = "bool"
+	}
+	niceValue, err := checker(str, valType)
+	if err != nil {
+		return err
+	}
+	if niceValue != "" {
+		str = niceValue
+	}
+	switch {
+	case value.Type() == reflect.TypeOf(net.IP{}):
+		addr := net.ParseIP(str)
+		if addr == nil {
+			return fmt.Errorf("%s can't be parsed to ip address", str)
+		}
+		value.Set(reflect.ValueOf(addr))
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		items := reflect.MakeSlice(value.Type(), 0, 0)
+		for _, item := range strings.Split(str, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(value.Type().Elem()))
+			}
+		}
+		value.Set(items)
+	case value.Kind() == reflect.Bool:
+		myBool, _ := strconv.ParseBool(str)
+		value.SetBool(myBool)
+	case value.Kind() == reflect.String:
+		value.SetString(str)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
 // parseMapField extracts the map key if the field name represents a map access (e.g. "Fields[key]" returns "Fields", "key").
 // If there is no key specified, it simply returns the field name as is.
 func parseMapField(name string) (field, key string) {
@@ -240,6 +392,8 @@ func valueStr(value reflect.Value) (output string) {
 		if !value.IsZero() {
 			output = fmt.Sprintf("%s", value)
 		}
+	} else if value.Kind() == reflect.Bool {
+		output = strconv.FormatBool(value.Bool())
 	} else if value.Kind() == reflect.Slice {
 		var sliceStrs []string
 		for i := 0; i < value.Len(); i++ {
@@ -108,6 +108,9 @@ func Test_listFields(t *testing.T) {
 			fields: []string{
 				"Discoverable",
 				"AssetKey",
+				"State",
+				"StateReason",
+				"StateChanged",
 				"Profiles",
 				"Comment",
 				"ClusterName",
@@ -120,6 +123,7 @@ func Test_listFields(t *testing.T) {
 				"Ipmi.UserName",
 				"Ipmi.Password",
 				"Ipmi.Ipaddr",
+				"Ipmi.Pool",
 				"Ipmi.Gateway",
 				"Ipmi.Netmask",
 				"Ipmi.Port",
@@ -127,18 +131,29 @@ func Test_listFields(t *testing.T) {
 				"Ipmi.EscapeChar",
 				"Ipmi.Write",
 				"Ipmi.Template",
+				"Ipmi.Backend",
+				"Ipmi.Insecure",
+				"Ipmi.RedfishPort",
 				"Init",
 				"Root",
 				"NetDevs[default].Type",
+				"NetDevs[default].Network",
 				"NetDevs[default].OnBoot",
 				"NetDevs[default].Device",
 				"NetDevs[default].Hwaddr",
 				"NetDevs[default].Ipaddr",
+				"NetDevs[default].Pool",
 				"NetDevs[default].Ipaddr6",
 				"NetDevs[default].Prefix",
 				"NetDevs[default].Netmask",
 				"NetDevs[default].Gateway",
 				"NetDevs[default].MTU",
+				"NetDevs[default].Parent",
+				"NetDevs[default].VlanId",
+				"NetDevs[default].Members",
+				"NetDevs[default].BondMode",
+				"NetDevs[default].BondOptions",
+				"NetDevs[default].BridgePorts",
 				"NetDevs[default].Tags[nettag]",
 				"Tags[tag]",
 				"PrimaryNetDev",
@@ -170,6 +185,7 @@ func Test_listFields(t *testing.T) {
 				"Ipmi.UserName",
 				"Ipmi.Password",
 				"Ipmi.Ipaddr",
+				"Ipmi.Pool",
 				"Ipmi.Gateway",
 				"Ipmi.Netmask",
 				"Ipmi.Port",
@@ -177,18 +193,29 @@ func Test_listFields(t *testing.T) {
 				"Ipmi.EscapeChar",
 				"Ipmi.Write",
 				"Ipmi.Template",
+				"Ipmi.Backend",
+				"Ipmi.Insecure",
+				"Ipmi.RedfishPort",
 				"Init",
 				"Root",
 				"NetDevs[default].Type",
+				"NetDevs[default].Network",
 				"NetDevs[default].OnBoot",
 				"NetDevs[default].Device",
 				"NetDevs[default].Hwaddr",
 				"NetDevs[default].Ipaddr",
+				"NetDevs[default].Pool",
 				"NetDevs[default].Ipaddr6",
 				"NetDevs[default].Prefix",
 				"NetDevs[default].Netmask",
 				"NetDevs[default].Gateway",
 				"NetDevs[default].MTU",
+				"NetDevs[default].Parent",
+				"NetDevs[default].VlanId",
+				"NetDevs[default].Members",
+				"NetDevs[default].BondMode",
+				"NetDevs[default].BondOptions",
+				"NetDevs[default].BridgePorts",
 				"NetDevs[default].Tags[nettag]",
 				"Tags[tag]",
 				"PrimaryNetDev",
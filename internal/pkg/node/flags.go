@@ -33,10 +33,12 @@ back to strings.
 */
 func (nodeConf *Node) CreateFlags(baseCmd *cobra.Command) {
 	recursiveCreateFlags(nodeConf, baseCmd)
+	createCustomFlags(&nodeConf.Fields, baseCmd)
 }
 
 func (profileConf *Profile) CreateFlags(baseCmd *cobra.Command) {
 	recursiveCreateFlags(profileConf, baseCmd)
+	createCustomFlags(&profileConf.Fields, baseCmd)
 }
 
 func (del *NodeConfDel) CreateDelFlags(baseCmd *cobra.Command) {
@@ -33,3 +33,11 @@ func (config *NodesYaml) StringHash() string {
 	buffer := config.Hash()
 	return hex.EncodeToString(buffer[:])
 }
+
+/*
+Return the hash of the raw content of a configuration file as string
+*/
+func contentHash(data []byte) string {
+	buffer := sha256.Sum256(data)
+	return hex.EncodeToString(buffer[:])
+}
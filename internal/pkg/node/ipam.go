@@ -0,0 +1,140 @@
+package node
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+AllocateAddresses assigns an IPv4 address to every network device and
+to the IPMI interface of the given node which references an address
+pool of warewulf.conf but has no address yet. The next free address
+of the pool is used, where every address already assigned to a
+network device or an IPMI interface of any node, the address of the
+Warewulf server, the gateway and the reserved addresses of the pool
+are not free. The netmask and the gateway are set from the pool if
+they are not set already.
+
+The allocated addresses are written to the node itself, not to its
+profiles, so the configuration has to be persisted afterwards.
+*/
+func (config *NodesYaml) AllocateAddresses(nodeID string) error {
+	nodePtr, err := config.GetNodeOnlyPtr(nodeID)
+	if err != nil {
+		return err
+	}
+	merged, err := config.GetNode(nodeID)
+	if err != nil {
+		return err
+	}
+	used, err := config.usedAddresses()
+	if err != nil {
+		return err
+	}
+	var netdevs []string
+	for name := range merged.NetDevs {
+		netdevs = append(netdevs, name)
+	}
+	sort.Strings(netdevs)
+	for _, name := range netdevs {
+		netdev := merged.NetDevs[name]
+		if netdev.Pool == "" || !isUnset(netdev.Ipaddr) {
+			continue
+		}
+		pool, ip, err := allocate(netdev.Pool, used)
+		if err != nil {
+			return fmt.Errorf("%s: network device %s: %w", nodeID, name, err)
+		}
+		if nodePtr.NetDevs == nil {
+			nodePtr.NetDevs = make(map[string]*NetDev)
+		}
+		if _, ok := nodePtr.NetDevs[name]; !ok {
+			nodePtr.NetDevs[name] = new(NetDev)
+		}
+		own := nodePtr.NetDevs[name]
+		own.Ipaddr = ip
+		if isUnset(netdev.Netmask) {
+			own.Netmask = pool.Netmask()
+		}
+		if isUnset(netdev.Gateway) && pool.Gateway != "" {
+			own.Gateway = net.ParseIP(pool.Gateway)
+		}
+		wwlog.Verbose("%s: allocated %s for network device %s from pool %s", nodeID, ip, name, netdev.Pool)
+	}
+	if merged.Ipmi != nil && merged.Ipmi.Pool != "" && isUnset(merged.Ipmi.Ipaddr) {
+		pool, ip, err := allocate(merged.Ipmi.Pool, used)
+		if err != nil {
+			return fmt.Errorf("%s: ipmi: %w", nodeID, err)
+		}
+		if nodePtr.Ipmi == nil {
+			nodePtr.Ipmi = new(IpmiConf)
+		}
+		nodePtr.Ipmi.Ipaddr = ip
+		if isUnset(merged.Ipmi.Netmask) {
+			nodePtr.Ipmi.Netmask = pool.Netmask()
+		}
+		if isUnset(merged.Ipmi.Gateway) && pool.Gateway != "" {
+			nodePtr.Ipmi.Gateway = net.ParseIP(pool.Gateway)
+		}
+		wwlog.Verbose("%s: allocated %s for ipmi from pool %s", nodeID, ip, merged.Ipmi.Pool)
+	}
+	return nil
+}
+
+// usedAddresses returns all IPv4 addresses which are assigned to a
+// network device or an IPMI interface of any node, and the address of
+// the Warewulf server.
+func (config *NodesYaml) usedAddresses() (map[string]bool, error) {
+	used := make(map[string]bool)
+	if ip := net.ParseIP(warewulfconf.Get().Ipaddr); ip != nil {
+		used[ip.String()] = true
+	}
+	nodes, err := config.FindAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		for _, netdev := range n.NetDevs {
+			if !isUnset(netdev.Ipaddr) {
+				used[netdev.Ipaddr.String()] = true
+			}
+		}
+		if n.Ipmi != nil && !isUnset(n.Ipmi.Ipaddr) {
+			used[n.Ipmi.Ipaddr.String()] = true
+		}
+	}
+	return used, nil
+}
+
+// allocate returns the next free address of the named pool and marks
+// it as used.
+func allocate(poolName string, used map[string]bool) (*warewulfconf.AddressPool, net.IP, error) {
+	pool, ok := warewulfconf.Get().AddressPools[poolName]
+	if !ok || pool == nil {
+		return nil, nil, fmt.Errorf("address pool not found: %s", poolName)
+	}
+	start, end, err := pool.Range()
+	if err != nil {
+		return nil, nil, fmt.Errorf("address pool %s: %w", poolName, err)
+	}
+	for ip := start; ; ip = util.IncrementIPv4(ip, 1).To4() {
+		if !used[ip.String()] && !pool.IsReserved(ip) {
+			used[ip.String()] = true
+			return pool, ip, nil
+		}
+		if ip.Equal(end) {
+			break
+		}
+	}
+	return nil, nil, fmt.Errorf("address pool %s is exhausted", poolName)
+}
+
+// isUnset returns true if no address is set
+func isUnset(ip net.IP) bool {
+	return ip == nil || ip.IsUnspecified()
+}
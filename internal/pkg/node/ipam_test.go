@@ -0,0 +1,188 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_AllocateAddresses(t *testing.T) {
+	tests := map[string]struct {
+		nodesConf string
+		node      string
+		ipaddr    string
+		ipmiaddr  string
+		netmask   string
+		gateway   string
+		err       bool
+	}{
+		"first free address": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        pool: cluster`,
+			node:    "n01",
+			ipaddr:  "10.0.0.10",
+			netmask: "255.255.255.0",
+			gateway: "10.0.0.1",
+		},
+		"skip used and reserved addresses": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.0.0.10
+  n02:
+    ipmi:
+      ipaddr: 10.0.0.11
+  n03:
+    network devices:
+      default:
+        pool: cluster`,
+			node:    "n03",
+			ipaddr:  "10.0.0.13",
+			netmask: "255.255.255.0",
+			gateway: "10.0.0.1",
+		},
+		"pool from profile": {
+			nodesConf: `
+nodeprofiles:
+  default:
+    network devices:
+      default:
+        pool: cluster
+        netmask: 255.255.0.0
+    ipmi:
+      pool: bmc
+nodes:
+  n01:
+    profiles:
+    - default`,
+			node:     "n01",
+			ipaddr:   "10.0.0.10",
+			ipmiaddr: "10.1.0.1",
+		},
+		"address already set": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.0.0.50
+        pool: cluster`,
+			node:   "n01",
+			ipaddr: "10.0.0.50",
+		},
+		"unknown pool": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        pool: unknown`,
+			node: "n01",
+			err:  true,
+		},
+		"exhausted pool": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.2.0.1
+    ipmi:
+      ipaddr: 10.2.0.2
+  n02:
+    network devices:
+      default:
+        pool: small`,
+			node: "n02",
+			err:  true,
+		},
+		"point to point pool": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        ipaddr: 10.3.0.0
+  n02:
+    network devices:
+      default:
+        pool: p2p`,
+			node:    "n02",
+			ipaddr:  "10.3.0.1",
+			netmask: "255.255.255.254",
+		},
+		"single address pool": {
+			nodesConf: `
+nodes:
+  n01:
+    network devices:
+      default:
+        pool: single`,
+			node:    "n01",
+			ipaddr:  "10.4.0.7",
+			netmask: "255.255.255.255",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := testenv.New(t)
+			defer env.RemoveAll()
+			conf := warewulfconf.Get()
+			conf.Ipaddr = "10.0.0.1"
+			conf.AddressPools = map[string]*warewulfconf.AddressPool{
+				"cluster": {
+					Network:    "10.0.0.0/24",
+					Gateway:    "10.0.0.1",
+					RangeStart: "10.0.0.10",
+					Reserved:   []string{"10.0.0.12"},
+				},
+				"bmc": {
+					Network: "10.1.0.0/24",
+				},
+				"small": {
+					Network: "10.2.0.0/30",
+				},
+				"p2p": {
+					Network: "10.3.0.0/31",
+				},
+				"single": {
+					Network: "10.4.0.7/32",
+				},
+			}
+			defer func() { conf.AddressPools = nil; conf.Ipaddr = "" }()
+
+			nodes, err := Parse([]byte(tt.nodesConf))
+			assert.NoError(t, err)
+			err = nodes.AllocateAddresses(tt.node)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			own, err := nodes.GetNodeOnly(tt.node)
+			assert.NoError(t, err)
+			merged, err := nodes.GetNode(tt.node)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ipaddr, merged.NetDevs["default"].Ipaddr.String())
+			if tt.netmask != "" {
+				assert.Equal(t, tt.netmask, own.NetDevs["default"].Netmask.String())
+			}
+			if tt.gateway != "" {
+				assert.Equal(t, tt.gateway, own.NetDevs["default"].Gateway.String())
+			}
+			if tt.ipmiaddr != "" {
+				assert.Equal(t, tt.ipmiaddr, own.Ipmi.Ipaddr.String())
+			}
+		})
+	}
+}
@@ -38,6 +38,36 @@ func copyProfile(original Profile) (Profile, error) {
 	}
 }
 
+// Copy creates a deep copy of the node. Like copyProfile, it uses
+// encoding/gob for the exported fields, and then sets the unexported
+// fields which gob skips, so that e.g. Id() of the copy still works.
+//
+// Returns:
+// - A new Node object that is a deep copy of the node.
+// - An error if serialization or deserialization fails.
+func (node *Node) Copy() (Node, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	dec := gob.NewDecoder(&buf)
+	nodeCopy := Node{}
+	if err := enc.Encode(node); err != nil {
+		return nodeCopy, err
+	}
+	if err := dec.Decode(&nodeCopy); err != nil {
+		return nodeCopy, err
+	}
+	nodeCopy.id = node.id
+	nodeCopy.valid = node.valid
+	nodeCopy.profiles = append([]string(nil), node.profiles...)
+	nodeCopy.Profile.id = node.Profile.id
+	for name, netdev := range node.NetDevs {
+		if netdev != nil && nodeCopy.NetDevs[name] != nil {
+			nodeCopy.NetDevs[name].primary = netdev.primary
+		}
+	}
+	return nodeCopy, nil
+}
+
 // getNodeProfiles retrieves a list of profile IDs associated with a specific node ID.
 // It retrives nested profiles and ensures the list is cleaned of duplicates
 // and negations (denoted with a '~' prefix).
@@ -169,6 +199,9 @@ func (config *NodesYaml) MergeNode(id string) (node Node, fields fieldMap, err e
 
 	node.id = id
 	node.valid = true
+	node.profiles = config.getNodeProfiles(id)
+	config.applyCustomFields(&node, fields)
+	node.applyNetworks(fields)
 	node.updatePrimaryNetDev()
 	return node, fields, nil
 }
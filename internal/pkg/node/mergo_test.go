@@ -798,3 +798,35 @@ nodeprofiles:
 		})
 	}
 }
+
+func Test_NodeCopy(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("/etc/warewulf/nodes.conf", `
+nodes:
+  n1:
+    profiles:
+    - p1
+    network devices:
+      default:
+        device: eth0
+nodeprofiles:
+  p1:
+    comment: p1 comment`)
+
+	registry, err := New()
+	assert.NoError(t, err)
+	original, _, err := registry.MergeNode("n1")
+	assert.NoError(t, err)
+
+	copied, err := original.Copy()
+	assert.NoError(t, err)
+	assert.Equal(t, "n1", copied.Id())
+	assert.True(t, copied.Valid())
+	assert.Equal(t, original.profiles, copied.profiles)
+	assert.True(t, copied.NetDevs["default"].Primary())
+	assert.Equal(t, "p1 comment", copied.Comment)
+
+	copied.NetDevs["default"].Device = "eth1"
+	assert.Equal(t, "eth0", original.NetDevs["default"].Device)
+}
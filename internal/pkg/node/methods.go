@@ -9,6 +9,7 @@ import (
 
 	"github.com/warewulf/warewulf/internal/pkg/util"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+	"github.com/warewulf/warewulf/internal/pkg/wwtype"
 )
 
 type nodeList []Node
@@ -30,30 +31,12 @@ func (a profileList) Less(i, j int) bool { return a[i].id < a[j].id }
  *********/
 
 /*
-Filter a given slice of NodeConf against a given
-regular expression
+Filter a given slice of NodeConf against the given node selectors,
+which are either regular expressions for the node name or selectors on
+node attributes, see SelectNodes.
 */
-func FilterNodeListByName(set []Node, searchList []string) []Node {
-	var ret []Node
-	unique := make(map[string]Node)
-
-	if len(searchList) > 0 {
-		for _, search := range searchList {
-			for _, entry := range set {
-				if match, _ := regexp.MatchString("^"+search+"$", entry.id); match {
-					unique[entry.id] = entry
-				}
-			}
-		}
-		for _, n := range unique {
-			ret = append(ret, n)
-		}
-	} else {
-		ret = set
-	}
-
-	sort.Sort(nodeList(ret))
-	return ret
+func FilterNodeListByName(set []Node, searchList []string) ([]Node, error) {
+	return SelectNodes(set, searchList)
 }
 
 /*
@@ -125,6 +108,7 @@ or *KernelConf, these pointer will set to nil. This will remove something like
 ipmi: {} from nodes.conf
 */
 func (info *Node) Flatten() {
+	info.Profile.flattenFields()
 	recursiveFlatten(info)
 }
 
@@ -134,9 +118,21 @@ or *KernelConf, these pointer will set to nil. This will remove something like
 ipmi: {} from nodes.conf
 */
 func (info *Profile) Flatten() {
+	info.flattenFields()
 	recursiveFlatten(info)
 }
 
+/*
+Removes custom fields which are set to an unset verb like UNSET
+*/
+func (info *Profile) flattenFields() {
+	for name, value := range info.Fields {
+		if value == "" || util.InSlice(wwtype.GetUnsetVerbs(), value) {
+			delete(info.Fields, name)
+		}
+	}
+}
+
 func recursiveFlatten(obj interface{}) (hasContent bool) {
 	valObj := reflect.ValueOf(obj)
 	typeObj := reflect.TypeOf(obj)
@@ -3,12 +3,16 @@ package node
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
 	"gopkg.in/yaml.v3"
 
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/history"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 )
 
@@ -113,6 +117,13 @@ func (config *NodesYaml) Persist() error {
 	return config.PersistToFile(warewulfconf.Get().Paths.NodesConf())
 }
 
+/*
+Write the NodeYaml to the given file. The file is locked while it is
+written. If the NodeYaml was read from the same file, the content on
+disk is compared against the content which was loaded and ErrConflict
+is returned if it was changed in the meantime. Every change of the
+configured nodes.conf is recorded in the configuration history.
+*/
 func (config *NodesYaml) PersistToFile(configFile string) error {
 	if configFile == "" {
 		configFile = warewulfconf.Get().Paths.NodesConf()
@@ -122,17 +133,44 @@ func (config *NodesYaml) PersistToFile(configFile string) error {
 		wwlog.Error("%s", dumpErr)
 		return dumpErr
 	}
-	file, err := os.OpenFile(configFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	file, err := os.OpenFile(configFile, os.O_RDWR|os.O_CREATE, 0o644)
 	if err != nil {
 		wwlog.Error("%s", err)
 		return err
 	}
 	defer file.Close()
-	_, err = file.WriteString(string(out))
+	wwlog.Debug("locking: %s", configFile)
+	if err = unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("could not lock %s: %w", configFile, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(file.Fd()), unix.LOCK_UN)
+	}()
+	current, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if config.sourceHash != "" && config.sourceFile == configFile && contentHash(current) != config.sourceHash {
+		return fmt.Errorf("%s: %w, reload and retry", configFile, ErrConflict)
+	}
+	if err = file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = file.Write(out)
 	if err != nil {
 		return err
 	}
+	config.sourceFile = configFile
+	config.sourceHash = contentHash(out)
 	wwlog.Debug("persisted: %s", configFile)
+	if history.SameFile(configFile, warewulfconf.Get().Paths.NodesConf()) {
+		if err = history.Record(warewulfconf.Get().Paths.HistoryDir(), configFile, current, out); err != nil {
+			wwlog.Warn("could not record history of %s: %s", configFile, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,86 @@
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/history"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_Persist_Conflict(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `nodeprofiles: {}
+nodes:
+  n01: {}
+`)
+
+	first, err := New()
+	assert.NoError(t, err)
+	second, err := New()
+	assert.NoError(t, err)
+
+	_, err = first.AddNode("n02")
+	assert.NoError(t, err)
+	assert.NoError(t, first.Persist())
+
+	_, err = second.AddNode("n03")
+	assert.NoError(t, err)
+	err = second.Persist()
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	current, err := New()
+	assert.NoError(t, err)
+	assert.Contains(t, current.Nodes, "n02")
+	assert.NotContains(t, current.Nodes, "n03")
+
+	t.Run("persist twice after own write", func(t *testing.T) {
+		_, err = first.AddNode("n04")
+		assert.NoError(t, err)
+		assert.NoError(t, first.Persist())
+	})
+}
+
+func Test_Persist_Unloaded(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+
+	nodes, err := Parse([]byte(`nodes:
+  n01: {}
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, nodes.Persist())
+	assert.YAMLEq(t, `nodeprofiles: {}
+nodes:
+  n01: {}
+`, env.ReadFile("etc/warewulf/nodes.conf"))
+}
+
+func Test_Persist_History(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("etc/warewulf/nodes.conf", `nodes:
+  n01: {}
+`)
+	historyDir := warewulfconf.Get().Paths.HistoryDir()
+	nodesConf := warewulfconf.Get().Paths.NodesConf()
+
+	nodes, err := New()
+	assert.NoError(t, err)
+	_, err = nodes.AddNode("n02")
+	assert.NoError(t, err)
+	assert.NoError(t, nodes.PersistToFile(env.GetPath("upgraded.conf")))
+	revisions, err := history.List(historyDir, nodesConf)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions, "a copy is not recorded")
+
+	assert.NoError(t, nodes.Persist())
+	revisions, err = history.List(historyDir, nodesConf)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+}
@@ -0,0 +1,259 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+/*
+Network devices may be combined to bonds, VLANs and bridges. The
+relationships are modeled by the names of the network devices of the
+node:
+
+	bond0:   members: [eth0, eth1], bond mode: 802.3ad
+	vlan100: parent: bond0, vlan id: "100"
+	br0:     bridge ports: [vlan100]
+
+The helpers below are meant to be used by the network overlay
+templates, so that they all render these relationships in the same way.
+*/
+
+// bondModes lists the valid values for NetDev.BondMode
+var bondModes = []string{
+	"balance-rr", "active-backup", "balance-xor", "broadcast",
+	"802.3ad", "balance-tlb", "balance-alb",
+	"0", "1", "2", "3", "4", "5", "6",
+}
+
+// defaultBondOptions are used for bonds without mode and options
+var defaultBondOptions = map[string]string{
+	"mode":             "802.3ad",
+	"miimon":           "100",
+	"xmit_hash_policy": "layer2+3",
+	"downdelay":        "0",
+	"updelay":          "0",
+}
+
+// IsBond returns true if the network device is a bond
+func (dev *NetDev) IsBond() bool {
+	return dev.Type == "bond" || len(dev.Members) > 0
+}
+
+// IsVlan returns true if the network device is a VLAN
+func (dev *NetDev) IsVlan() bool {
+	return dev.Type == "vlan" || dev.VlanId != ""
+}
+
+// IsBridge returns true if the network device is a bridge
+func (dev *NetDev) IsBridge() bool {
+	return dev.Type == "bridge" || len(dev.BridgePorts) > 0
+}
+
+// Vlan returns the VLAN id of the network device, falling back to the
+// vlan_id tag.
+func (dev *NetDev) Vlan() string {
+	if dev.VlanId != "" {
+		return dev.VlanId
+	}
+	return dev.Tags["vlan_id"]
+}
+
+/*
+BondOpts returns the bond mode and options of the network device as
+map, e.g. {"mode": "802.3ad", "miimon": "100"}. If neither a mode nor
+options are set, a LACP bond with link monitoring is returned.
+*/
+func (dev *NetDev) BondOpts() map[string]string {
+	opts := make(map[string]string)
+	if dev.BondMode == "" && dev.BondOptions == "" {
+		for key, value := range defaultBondOptions {
+			opts[key] = value
+		}
+		return opts
+	}
+	for _, opt := range strings.FieldsFunc(dev.BondOptions, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if key, value, ok := strings.Cut(opt, "="); ok {
+			opts[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if dev.BondMode != "" {
+		opts["mode"] = dev.BondMode
+	} else if _, ok := opts["mode"]; !ok {
+		opts["mode"] = defaultBondOptions["mode"]
+	}
+	return opts
+}
+
+// DeviceName returns the device of the named network device, or the
+// name itself if the network device has no device set.
+func (node *Node) DeviceName(name string) string {
+	if dev, ok := node.NetDevs[name]; ok && dev != nil && dev.Device != "" {
+		return dev.Device
+	}
+	return name
+}
+
+// BondMaster returns the name of the bond the named network device is
+// a member of, or an empty string.
+func (node *Node) BondMaster(name string) string {
+	for _, master := range node.sortedNetDevs() {
+		if util.InSlice(node.NetDevs[master].Members, name) {
+			return master
+		}
+	}
+	return ""
+}
+
+// BridgeMaster returns the name of the bridge the named network device
+// is a port of, or an empty string.
+func (node *Node) BridgeMaster(name string) string {
+	for _, master := range node.sortedNetDevs() {
+		if util.InSlice(node.NetDevs[master].BridgePorts, name) {
+			return master
+		}
+	}
+	return ""
+}
+
+// VlanParent returns the device of the parent of the named VLAN,
+// falling back to the parent_device tag.
+func (node *Node) VlanParent(name string) string {
+	dev, ok := node.NetDevs[name]
+	if !ok || dev == nil {
+		return ""
+	}
+	if dev.Parent != "" {
+		return node.DeviceName(dev.Parent)
+	}
+	return dev.Tags["parent_device"]
+}
+
+/*
+DefaultRoute returns true if the default route of the node is set on
+the named network device: the primary network device if it has a
+gateway, otherwise the first network device with a gateway, so that a
+node gets a single default route.
+*/
+func (node *Node) DefaultRoute(name string) bool {
+	if dev, ok := node.NetDevs[node.PrimaryNetDev]; ok && dev != nil && !isUnset(dev.Gateway) {
+		return name == node.PrimaryNetDev
+	}
+	for _, devName := range node.sortedNetDevs() {
+		if !isUnset(node.NetDevs[devName].Gateway) {
+			return name == devName
+		}
+	}
+	return false
+}
+
+/*
+CheckNetDevs validates the bonds, VLANs and bridges of the node: all
+referenced network devices must exist, a network device may only be
+member of one bond or bridge, VLAN ids and bond modes must be valid,
+every VLAN must have a parent and the relationships must not contain
+cycles. It is meant to be called on a merged node, as the network
+devices may be defined in different profiles or inherit their VLAN id
+from their network.
+*/
+func (node *Node) CheckNetDevs() error {
+	names := node.sortedNetDevs()
+	masters := make(map[string]string)
+	for _, name := range names {
+		dev := node.NetDevs[name]
+		if dev.Parent != "" {
+			if err := node.checkReference(name, "parent", dev.Parent); err != nil {
+				return err
+			}
+		}
+		if vlan := dev.Vlan(); vlan != "" {
+			if id, err := strconv.ParseUint(vlan, 10, 16); err != nil || id < 1 || id > 4094 {
+				return fmt.Errorf("network device %s: invalid VLAN id: %s", name, vlan)
+			}
+		}
+		if dev.IsVlan() && node.VlanParent(name) == "" && !isVlanDevice(dev.Device) {
+			return fmt.Errorf("network device %s: VLAN has no parent", name)
+		}
+		if dev.BondMode != "" && !util.InSlice(bondModes, dev.BondMode) {
+			return fmt.Errorf("network device %s: invalid bond mode: %s", name, dev.BondMode)
+		}
+		for _, member := range append(append([]string{}, dev.Members...), dev.BridgePorts...) {
+			if err := node.checkReference(name, "member", member); err != nil {
+				return err
+			}
+			if master, ok := masters[member]; ok && master != name {
+				return fmt.Errorf("network device %s is member of %s and %s", member, master, name)
+			}
+			masters[member] = name
+		}
+	}
+	for _, name := range names {
+		if err := node.checkCycle(name, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isVlanDevice returns true if the device name is of the form
+// PARENT.ID, from which the parent and the id of a VLAN are taken
+// without further configuration
+func isVlanDevice(device string) bool {
+	i := strings.LastIndex(device, ".")
+	if i < 1 {
+		return false
+	}
+	_, err := strconv.ParseUint(device[i+1:], 10, 16)
+	return err == nil
+}
+
+// checkReference checks that a referenced network device exists
+func (node *Node) checkReference(name, kind, ref string) error {
+	if ref == name {
+		return fmt.Errorf("network device %s: %s refers to itself", name, kind)
+	}
+	if dev, ok := node.NetDevs[ref]; !ok || dev == nil {
+		return fmt.Errorf("network device %s: %s %s does not exist", name, kind, ref)
+	}
+	return nil
+}
+
+// checkCycle follows the parent, members and bridge ports of a network
+// device and returns an error if it reaches a device on path again
+func (node *Node) checkCycle(name string, path []string) error {
+	if util.InSlice(path, name) {
+		return fmt.Errorf("network devices form a cycle: %s", strings.Join(append(path, name), " -> "))
+	}
+	dev, ok := node.NetDevs[name]
+	if !ok || dev == nil {
+		return nil
+	}
+	path = append(path, name)
+	var next []string
+	if dev.Parent != "" {
+		next = append(next, dev.Parent)
+	}
+	next = append(next, dev.Members...)
+	next = append(next, dev.BridgePorts...)
+	for _, ref := range next {
+		if err := node.checkCycle(ref, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedNetDevs returns the names of the network devices in a stable
+// order
+func (node *Node) sortedNetDevs() (names []string) {
+	for name, dev := range node.NetDevs {
+		if dev != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
@@ -0,0 +1,209 @@
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckNetDevs(t *testing.T) {
+	tests := map[string]struct {
+		netdevs string
+		err     string
+	}{
+		"bond, vlan and bridge": {
+			netdevs: `
+eth0: {}
+eth1: {}
+bond0:
+  members: [eth0, eth1]
+  bond mode: 802.3ad
+  bond options: miimon=100
+vlan100:
+  parent: bond0
+  vlan id: "100"
+br0:
+  bridge ports: [vlan100]`,
+		},
+		"legacy vlan tags": {
+			netdevs: `
+tagged:
+  type: vlan
+  tags:
+    vlan_id: "902"
+    parent_device: eth0`,
+		},
+		"missing member": {
+			netdevs: `
+eth0: {}
+bond0:
+  members: [eth0, eth1]`,
+			err: "network device bond0: member eth1 does not exist",
+		},
+		"missing parent": {
+			netdevs: `
+vlan100:
+  parent: bond0
+  vlan id: "100"`,
+			err: "network device vlan100: parent bond0 does not exist",
+		},
+		"invalid vlan id": {
+			netdevs: `
+eth0: {}
+vlan:
+  parent: eth0
+  vlan id: "5000"`,
+			err: "network device vlan: invalid VLAN id: 5000",
+		},
+		"vlan id from device name": {
+			netdevs: `
+vlan:
+  type: vlan
+  device: eth0.902`,
+		},
+		"vlan without parent": {
+			netdevs: `
+eth0: {}
+vlan100:
+  vlan id: "100"`,
+			err: "network device vlan100: VLAN has no parent",
+		},
+		"invalid bond mode": {
+			netdevs: `
+eth0: {}
+bond0:
+  members: [eth0]
+  bond mode: lacp`,
+			err: "network device bond0: invalid bond mode: lacp",
+		},
+		"member of two bonds": {
+			netdevs: `
+eth0: {}
+bond0:
+  members: [eth0]
+bond1:
+  members: [eth0]`,
+			err: "network device eth0 is member of bond0 and bond1",
+		},
+		"self reference": {
+			netdevs: `
+bond0:
+  members: [bond0]`,
+			err: "network device bond0: member refers to itself",
+		},
+		"cycle": {
+			netdevs: `
+bond0:
+  members: [vlan100]
+vlan100:
+  parent: bond0
+  vlan id: "100"`,
+			err: "network devices form a cycle: bond0 -> vlan100 -> bond0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes, err := Parse([]byte("nodes:\n  n01:\n    network devices:\n" + indent(tt.netdevs, "      ")))
+			assert.NoError(t, err)
+			n01, err := nodes.GetNode("n01")
+			assert.NoError(t, err)
+			err = n01.CheckNetDevs()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_NetDevHelpers(t *testing.T) {
+	nodes, err := Parse([]byte(`
+nodes:
+  n01:
+    network devices:
+      eth0:
+        device: enp1s0
+      eth1:
+        device: enp2s0
+      bond0:
+        device: bond0
+        members: [eth0, eth1]
+        bond mode: active-backup
+        bond options: miimon=200, primary=enp1s0
+      vlan100:
+        device: bond0.100
+        parent: bond0
+        vlan id: "100"
+      br0:
+        device: br0
+        bridge ports: [vlan100]
+`))
+	assert.NoError(t, err)
+	n01, err := nodes.GetNode("n01")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "bond0", n01.BondMaster("eth1"))
+	assert.Equal(t, "", n01.BondMaster("vlan100"))
+	assert.Equal(t, "br0", n01.BridgeMaster("vlan100"))
+	assert.Equal(t, "bond0", n01.VlanParent("vlan100"))
+	assert.Equal(t, "enp1s0", n01.DeviceName("eth0"))
+	assert.True(t, n01.NetDevs["bond0"].IsBond())
+	assert.True(t, n01.NetDevs["vlan100"].IsVlan())
+	assert.True(t, n01.NetDevs["br0"].IsBridge())
+	assert.Equal(t, map[string]string{"mode": "active-backup", "miimon": "200", "primary": "enp1s0"}, n01.NetDevs["bond0"].BondOpts())
+	assert.Equal(t, "802.3ad", (&NetDev{Type: "bond"}).BondOpts()["mode"])
+}
+
+func Test_DefaultRoute(t *testing.T) {
+	nodes, err := Parse([]byte(`
+nodes:
+  n01:
+    network devices:
+      default:
+        gateway: 192.168.3.1
+      secondary:
+        gateway: 192.168.3.1
+  n02:
+    primary network: secondary
+    network devices:
+      default:
+        gateway: 192.168.3.1
+      secondary:
+        gateway: 192.168.4.1
+  n03:
+    network devices:
+      bond0:
+        members: [eth0]
+      br0:
+        bridge ports: [bond0]
+        gateway: 192.168.3.1
+      eth0: {}
+`))
+	assert.NoError(t, err)
+	n01, err := nodes.GetNode("n01")
+	assert.NoError(t, err)
+	assert.True(t, n01.DefaultRoute("default"))
+	assert.False(t, n01.DefaultRoute("secondary"))
+	n02, err := nodes.GetNode("n02")
+	assert.NoError(t, err)
+	assert.False(t, n02.DefaultRoute("default"))
+	assert.True(t, n02.DefaultRoute("secondary"))
+	// the primary network device bond0 has no gateway
+	n03, err := nodes.GetNode("n03")
+	assert.NoError(t, err)
+	assert.True(t, n03.DefaultRoute("br0"))
+	assert.False(t, n03.DefaultRoute("bond0"))
+}
+
+// indent prefixes every non-empty line of text
+func indent(text, prefix string) (ret string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line != "" {
+			ret += prefix + line + "\n"
+		}
+	}
+	return
+}
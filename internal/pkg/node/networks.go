@@ -0,0 +1,90 @@
+package node
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+applyNetworks fills the attributes of every network device which
+references a named network of warewulf.conf from that network, unless
+they were set for the network device itself. The source of each
+inherited field is recorded as "network:NAME" in fields.
+
+The network devices are copied before they are modified, as they may
+still be shared with the node configuration.
+*/
+func (node *Node) applyNetworks(fields fieldMap) {
+	networks := warewulfconf.Get().Networks
+	netdevs := make(map[string]*NetDev, len(node.NetDevs))
+	for name, netdev := range node.NetDevs {
+		netdevs[name] = netdev
+		if netdev == nil || netdev.Network == "" {
+			continue
+		}
+		copied := *netdev
+		copied.Tags = make(map[string]string, len(netdev.Tags))
+		for key, value := range netdev.Tags {
+			copied.Tags[key] = value
+		}
+		netdev = &copied
+		netdevs[name] = netdev
+		network, ok := networks[netdev.Network]
+		if !ok || network == nil {
+			wwlog.Warn("%s: network device %s: network not found: %s", node.id, name, netdev.Network)
+			continue
+		}
+		source := "network:" + netdev.Network
+		field := func(attr string) string {
+			return fmt.Sprintf("NetDevs[%s].%s", name, attr)
+		}
+		if isUnset(netdev.Netmask) {
+			if netmask := network.Netmask(); netmask != nil {
+				netdev.Netmask = netmask
+				fields.Set(field("Netmask"), source, netmask.String())
+			}
+		}
+		if isUnset(netdev.Gateway) && network.Gateway != "" {
+			if gateway := net.ParseIP(network.Gateway); gateway != nil {
+				netdev.Gateway = gateway
+				fields.Set(field("Gateway"), source, gateway.String())
+			}
+		}
+		if netdev.MTU == "" && network.MTU != "" {
+			netdev.MTU = network.MTU
+			fields.Set(field("MTU"), source, network.MTU)
+		}
+		if netdev.Pool == "" && network.Pool != "" {
+			netdev.Pool = network.Pool
+			fields.Set(field("Pool"), source, network.Pool)
+		}
+		if netdev.Vlan() == "" && network.VlanId != "" {
+			netdev.VlanId = network.VlanId
+			fields.Set(field("VlanId"), source, network.VlanId)
+		}
+		if !hasDNSTags(netdev.Tags) {
+			for i, dns := range network.DNS {
+				key := fmt.Sprintf("DNS%d", i+1)
+				netdev.Tags[key] = dns
+				fields.Set(field("Tags["+key+"]"), source, dns)
+			}
+		}
+	}
+	if node.NetDevs != nil {
+		node.NetDevs = netdevs
+	}
+}
+
+// hasDNSTags returns true if any DNS server is set as tag
+func hasDNSTags(tags map[string]string) bool {
+	for key := range tags {
+		if strings.HasPrefix(key, "DNS") {
+			return true
+		}
+	}
+	return false
+}
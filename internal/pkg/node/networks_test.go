@@ -0,0 +1,87 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_applyNetworks(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	conf := warewulfconf.Get()
+	conf.Networks = map[string]*warewulfconf.NetworkConf{
+		"cluster": {
+			Network: "10.0.0.0/16",
+			Gateway: "10.0.0.1",
+			MTU:     "9000",
+			DNS:     []string{"10.0.0.2", "10.0.0.3"},
+			VlanId:  "42",
+		},
+	}
+	defer func() { conf.Networks = nil }()
+
+	nodes, err := Parse([]byte(`
+nodeprofiles:
+  default:
+    network devices:
+      default:
+        network: cluster
+nodes:
+  n01:
+    profiles:
+    - default
+    network devices:
+      default:
+        ipaddr: 10.0.1.1
+  n02:
+    profiles:
+    - default
+    network devices:
+      default:
+        ipaddr: 10.0.1.2
+        netmask: 255.255.255.0
+        mtu: "1500"
+        tags:
+          DNS1: 8.8.8.8
+      other:
+        network: unknown
+`))
+	assert.NoError(t, err)
+
+	n01, fields, err := nodes.MergeNode("n01")
+	assert.NoError(t, err)
+	netdev := n01.NetDevs["default"]
+	assert.Equal(t, "255.255.0.0", netdev.Netmask.String())
+	assert.Equal(t, "10.0.0.1", netdev.Gateway.String())
+	assert.Equal(t, "9000", netdev.MTU)
+	assert.Equal(t, map[string]string{"DNS1": "10.0.0.2", "DNS2": "10.0.0.3"}, netdev.Tags)
+	assert.Equal(t, "42", netdev.VlanId)
+	assert.Equal(t, "network:cluster", fields.Source("NetDevs[default].Netmask"))
+	assert.Equal(t, "network:cluster", fields.Source("NetDevs[default].Tags[DNS2]"))
+	// the VLAN id is inherited, but the network device has no parent
+	assert.EqualError(t, n01.CheckNetDevs(), "network device default: VLAN has no parent")
+
+	n02, _, err := nodes.MergeNode("n02")
+	assert.NoError(t, err)
+	netdev = n02.NetDevs["default"]
+	assert.Equal(t, "255.255.255.0", netdev.Netmask.String())
+	assert.Equal(t, "10.0.0.1", netdev.Gateway.String())
+	assert.Equal(t, "1500", netdev.MTU)
+	assert.Equal(t, map[string]string{"DNS1": "8.8.8.8"}, netdev.Tags)
+	assert.Equal(t, "42", netdev.VlanId)
+	assert.Nil(t, n02.NetDevs["other"].Netmask)
+
+	t.Run("configuration is not modified", func(t *testing.T) {
+		own, err := nodes.GetNodeOnly("n01")
+		assert.NoError(t, err)
+		assert.Nil(t, own.NetDevs["default"].Gateway)
+		assert.Empty(t, own.NetDevs["default"].Tags)
+		profile, err := nodes.GetProfile("default")
+		assert.NoError(t, err)
+		assert.Empty(t, profile.NetDevs["default"].MTU)
+	})
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+/*
+NodeRecord is the flat representation of a node which is used to export
+and import nodes as CSV and JSON: the values of all fields which are set
+on the node, by the field names listed by GetFieldList, e.g.
+"Kernel.Args", "NetDevs[default].Ipaddr" or "Tags[rack]".
+*/
+type NodeRecord map[string]string
+
+// Record returns the fields which are set on the node as a NodeRecord
+func (node *Node) Record() NodeRecord {
+	record := make(NodeRecord)
+	for _, name := range listFields(*node) {
+		value, err := getNestedFieldValue(node, name)
+		if err != nil || !value.IsValid() || value.IsZero() {
+			continue
+		}
+		if str := valueStr(value); str != "" {
+			record[name] = str
+		}
+	}
+	return record
+}
+
+/*
+ApplyRecord sets the fields of the node from the given record. Empty
+values are skipped, unset verbs like UNDEF reset a field.
+*/
+func (node *Node) ApplyRecord(record NodeRecord) error {
+	var names []string
+	for name := range record {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if record[name] == "" {
+			continue
+		}
+		if err := setNestedFieldString(node, name, record[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+/*
+RecordFields returns the names of all fields which are set on any of
+the given nodes, in the order of GetFieldList.
+*/
+func RecordFields(nodes ...Node) (fields []string) {
+	for _, n := range nodes {
+		record := n.Record()
+		for _, name := range listFields(n) {
+			if _, ok := record[name]; ok && !util.InSlice(fields, name) {
+				fields = append(fields, name)
+			}
+		}
+	}
+	return fields
+}
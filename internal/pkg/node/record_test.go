@@ -0,0 +1,129 @@
+package node
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_NodeRecord(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("/etc/warewulf/nodes.conf", `
+nodes:
+  n1:
+    profiles:
+    - default
+    - gpu
+    comment: rack 1
+    discoverable: "true"
+    kernel:
+      args: quiet
+    ipmi:
+      ipaddr: 192.168.1.1
+      write: "true"
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:01
+        ipaddr: 10.0.0.1
+        netmask: 255.255.255.0
+      bond0:
+        members:
+        - eth0
+        - eth1
+    tags:
+      rack: r1
+    disks:
+      /dev/vda:
+        wipe_table: true
+        partitions:
+          scratch:
+            number: "1"
+            should_exist: true
+    filesystems:
+      /dev/disk/by-partlabel/scratch:
+        format: btrfs
+        path: /scratch
+        options:
+        - -f`)
+
+	registry, err := New()
+	assert.NoError(t, err)
+	n1, err := registry.GetNodeOnly("n1")
+	assert.NoError(t, err)
+
+	record := n1.Record()
+	assert.Equal(t, NodeRecord{
+		"Discoverable":              "true",
+		"Profiles":                  "default,gpu",
+		"Comment":                   "rack 1",
+		"Kernel.Args":               "quiet",
+		"Ipmi.Ipaddr":               "192.168.1.1",
+		"Ipmi.Write":                "true",
+		"NetDevs[bond0].Members":    "eth0,eth1",
+		"NetDevs[default].Hwaddr":   "00:00:00:00:00:01",
+		"NetDevs[default].Ipaddr":   "10.0.0.1",
+		"NetDevs[default].Netmask":  "255.255.255.0",
+		"Tags[rack]":                "r1",
+		"Disks[/dev/vda].WipeTable": "true",
+		"Disks[/dev/vda].Partitions[scratch].Number":          "1",
+		"Disks[/dev/vda].Partitions[scratch].ShouldExist":     "true",
+		"FileSystems[/dev/disk/by-partlabel/scratch].Format":  "btrfs",
+		"FileSystems[/dev/disk/by-partlabel/scratch].Path":    "/scratch",
+		"FileSystems[/dev/disk/by-partlabel/scratch].Options": "-f",
+	}, record)
+
+	copied := NewNode("n2")
+	assert.NoError(t, copied.ApplyRecord(record))
+	assert.Equal(t, record, copied.Record())
+	assert.Equal(t, net.ParseIP("10.0.0.1"), copied.NetDevs["default"].Ipaddr)
+	assert.True(t, copied.Disks["/dev/vda"].Partitions["scratch"].ShouldExist)
+
+	assert.Equal(t, []string{
+		"Discoverable",
+		"Profiles",
+		"Comment",
+		"Kernel.Args",
+		"Ipmi.Ipaddr",
+		"Ipmi.Write",
+	}, RecordFields(n1)[:6])
+}
+
+func Test_ApplyRecord(t *testing.T) {
+	n := NewNode("n1")
+	assert.NoError(t, n.ApplyRecord(NodeRecord{
+		"Comment":                 "compute",
+		"Tags[rack]":              "r1",
+		"NetDevs[default].Hwaddr": "00-00-00-00-00-0A",
+		"NetDevs[default].MTU":    "9000",
+		"Ipmi.Write":              "yes",
+	}))
+	assert.Equal(t, "compute", n.Comment)
+	assert.Equal(t, "00:00:00:00:00:0a", n.NetDevs["default"].Hwaddr)
+	assert.Equal(t, "true", string(n.Ipmi.Write))
+
+	assert.NoError(t, n.ApplyRecord(NodeRecord{
+		"Comment":     "UNDEF",
+		"Tags[rack]":  "UNDEF",
+		"Kernel.Args": "",
+	}))
+	assert.Equal(t, "", n.Comment)
+	assert.NotContains(t, n.Tags, "rack")
+
+	for name, record := range map[string]NodeRecord{
+		"unknown field":      {"Unknown": "value"},
+		"unexported field":   {"id": "n2"},
+		"struct field":       {"Kernel": "value"},
+		"invalid address":    {"NetDevs[default].Ipaddr": "10.0.0"},
+		"invalid number":     {"NetDevs[default].MTU": "large"},
+		"map of structs":     {"NetDevs[default]": "value"},
+		"key of plain field": {"Comment[key]": "value"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			n := NewNode("n1")
+			assert.Error(t, n.ApplyRecord(record))
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package node
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+/*
+Node selectors select nodes by their name or by their merged attributes.
+Every argument is a selector expression and the nodes matching any of
+them are selected. An argument which isn't a selector expression is a
+regular expression for the node name, e.g. n.+ or node[0-9]+.
+
+An expression is one or more terms joined by +, all of which must match.
+A term may be negated with ~; if the first term is negated, the nodes
+matched by the expression are removed from the selection, and if only
+such expressions are given, they are removed from the list of all nodes.
+A term is one of:
+
+	@profile:gpu        nodes which include the profile, also nested
+	name=n0[1-4]        the node name
+	cluster=east        the cluster name
+	container=rocky9    the container name
+	kernel=6.1.0        the kernel version
+	profile=gpu         same as @profile:gpu
+	tag.rack=r12        the node tag rack
+	ipmi.tag.KEY=VALUE  the ipmi tag KEY
+	FIELD=VALUE         any field as shown by 'wwctl node list -a', e.g. Kernel.Args
+	KEY!=VALUE          nodes where the value does not match
+
+An argument is only a selector expression if all of its terms have one
+of the keys above, so that name patterns containing +, = or ~ keep
+their meaning. Values are matched as regular expressions against the
+complete value. Node name ranges like n[01-10] are expanded by
+hostlist.Expand before.
+*/
+
+// selectorAliases maps the short selector keys to the field names
+// returned by listFields
+var selectorAliases = map[string]string{
+	"cluster":   "ClusterName",
+	"container": "ContainerName",
+	"kernel":    "Kernel.Version",
+	"comment":   "Comment",
+	"primary":   "PrimaryNetDev",
+	"ipxe":      "Ipxe",
+}
+
+// IsSelector returns true if the argument is a selector expression on
+// node attributes rather than a node name.
+func IsSelector(arg string) bool {
+	expr := strings.TrimPrefix(arg, "~")
+	for _, tok := range strings.Split(expr, "+") {
+		if !isSelectorTerm(strings.TrimPrefix(tok, "~")) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSelectorTerm returns true if tok is a term with a known key
+func isSelectorTerm(tok string) bool {
+	if key, _, ok := strings.Cut(tok, ":"); ok && strings.HasPrefix(key, "@") {
+		return key == "@profile"
+	}
+	key, _, ok := strings.Cut(tok, "=")
+	if !ok {
+		return false
+	}
+	return knownSelectorKey(strings.TrimSuffix(key, "!"))
+}
+
+// knownSelectorKey returns true if key is the key of a selector term,
+// i.e. name, profile, an alias, a tag or a node field
+func knownSelectorKey(key string) bool {
+	switch {
+	case key == "name" || key == "profile":
+		return true
+	case strings.HasPrefix(key, "tag.") && len(key) > len("tag."):
+		return true
+	case strings.HasPrefix(key, "ipmi.tag.") && len(key) > len("ipmi.tag."):
+		return true
+	}
+	if _, ok := selectorAliases[strings.ToLower(key)]; ok {
+		return true
+	}
+	return isNodeField(key)
+}
+
+// isNodeField returns true if key is the name of a field of a node,
+// e.g. Kernel.Args or NetDevs[default].Device, ignoring the case
+func isNodeField(key string) bool {
+	t := reflect.TypeOf(Node{})
+	for _, part := range strings.Split(key, ".") {
+		name, mapKey := parseMapField(part)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		field, ok := t.FieldByNameFunc(func(fieldName string) bool { return strings.EqualFold(fieldName, name) })
+		if !ok || !field.IsExported() {
+			return false
+		}
+		t = field.Type
+		if mapKey != "" {
+			if t.Kind() != reflect.Map {
+				return false
+			}
+			t = t.Elem()
+		}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Struct
+}
+
+// selectorTerm is a single condition of a selector expression
+type selectorTerm struct {
+	field   string
+	value   *regexp.Regexp
+	negate  bool
+	name    bool
+	profile bool
+}
+
+// parseSelector parses a selector expression into its terms. An
+// expression which isn't a selector is a single name term.
+func parseSelector(expr string) (terms []selectorTerm, err error) {
+	if !IsSelector(expr) {
+		term := selectorTerm{name: true}
+		if term.value, err = regexp.Compile("^" + expr + "$"); err != nil {
+			return nil, fmt.Errorf("invalid node name pattern: %s: %w", expr, err)
+		}
+		return []selectorTerm{term}, nil
+	}
+	for _, tok := range strings.Split(expr, "+") {
+		var term selectorTerm
+		if strings.HasPrefix(tok, "~") {
+			term.negate = true
+			tok = tok[1:]
+		}
+		var key, value string
+		if strings.HasPrefix(tok, "@") {
+			key, value, _ = strings.Cut(tok[1:], ":")
+		} else if k, v, ok := strings.Cut(tok, "!="); ok {
+			key, value = k, v
+			term.negate = !term.negate
+		} else {
+			key, value, _ = strings.Cut(tok, "=")
+		}
+		if value == "" {
+			return nil, fmt.Errorf("invalid selector: %s, value is missing", tok)
+		}
+		if term.value, err = regexp.Compile("^" + value + "$"); err != nil {
+			return nil, fmt.Errorf("invalid selector: %s: %w", tok, err)
+		}
+		switch {
+		case key == "name":
+			term.name = true
+		case key == "profile":
+			term.profile = true
+		default:
+			term.field = selectorField(key)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// selectorField returns the field name for the key of a selector term
+func selectorField(key string) string {
+	switch {
+	case strings.HasPrefix(key, "tag."):
+		return fmt.Sprintf("Tags[%s]", strings.TrimPrefix(key, "tag."))
+	case strings.HasPrefix(key, "ipmi.tag."):
+		return fmt.Sprintf("Ipmi.Tags[%s]", strings.TrimPrefix(key, "ipmi.tag."))
+	}
+	if alias, ok := selectorAliases[strings.ToLower(key)]; ok {
+		return alias
+	}
+	return key
+}
+
+// fieldValue returns the value of the field of the node, allowing case
+// insensitive field names like kernel.args
+func fieldValue(node Node, field string) string {
+	value, err := getNestedFieldString(node, field)
+	if err != nil {
+		for _, name := range listFields(node) {
+			if strings.EqualFold(name, field) {
+				value, _ = getNestedFieldString(node, name)
+				break
+			}
+		}
+	}
+	return value
+}
+
+/*
+SelectorValue returns the value of a field of the node given like the
+key of a selector term, e.g. cluster, tag.rack or Kernel.Args. The
+value is empty for unknown fields.
+*/
+func (node *Node) SelectorValue(key string) string {
+	return fieldValue(*node, selectorField(key))
+}
+
+// match returns true if the term matches the given node
+func (term selectorTerm) match(node Node) (match bool) {
+	switch {
+	case term.name:
+		match = term.value.MatchString(node.id)
+	case term.profile:
+		profiles := node.profiles
+		if profiles == nil {
+			profiles = node.Profiles
+		}
+		for _, profile := range profiles {
+			if term.value.MatchString(profile) {
+				match = true
+				break
+			}
+		}
+	default:
+		match = term.value.MatchString(fieldValue(node, term.field))
+	}
+	return match != term.negate
+}
+
+// matchSelector returns true if all terms match the given node
+func matchSelector(terms []selectorTerm, node Node) bool {
+	for _, term := range terms {
+		if !term.match(node) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+SelectNodes returns the nodes of set which are matched by the given
+selector expressions, sorted by their name. If no selectors are given,
+set is returned.
+*/
+func SelectNodes(set []Node, selectors []string) (ret []Node, err error) {
+	if len(selectors) == 0 {
+		ret = set
+		sort.Sort(nodeList(ret))
+		return
+	}
+	var include, exclude [][]selectorTerm
+	for _, selector := range selectors {
+		negate := IsSelector(selector) && strings.HasPrefix(selector, "~")
+		expr := selector
+		if negate {
+			expr = selector[1:]
+		}
+		terms, err := parseSelector(expr)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			exclude = append(exclude, terms)
+		} else {
+			include = append(include, terms)
+		}
+	}
+	for _, entry := range set {
+		found := len(include) == 0
+		for _, terms := range include {
+			if matchSelector(terms, entry) {
+				found = true
+				break
+			}
+		}
+		for _, terms := range exclude {
+			if found && matchSelector(terms, entry) {
+				found = false
+			}
+		}
+		if found {
+			ret = append(ret, entry)
+		}
+	}
+	sort.Sort(nodeList(ret))
+	wwlog.Debug("selected %d of %d nodes", len(ret), len(set))
+	return ret, nil
+}
+
+/*
+SelectNodeNames returns the names of the nodes matched by the given
+arguments. If none of the arguments is a selector on node attributes,
+they are returned unchanged so that the caller can still report
+unknown node names.
+*/
+func (config *NodesYaml) SelectNodeNames(args []string) (names []string, err error) {
+	hasSelector := false
+	for _, arg := range args {
+		hasSelector = hasSelector || IsSelector(arg)
+	}
+	if !hasSelector {
+		return args, nil
+	}
+	nodes, err := config.FindAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	selected, err := SelectNodes(nodes, args)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range selected {
+		names = append(names, n.id)
+	}
+	return names, nil
+}
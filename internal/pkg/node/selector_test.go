@@ -0,0 +1,123 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SelectNodes(t *testing.T) {
+	nodesConf := `
+nodeprofiles:
+  default:
+    cluster name: west
+  gpu:
+    profiles:
+    - default
+    container name: rocky9-cuda
+  east:
+    cluster name: east
+nodes:
+  n01:
+    profiles:
+    - default
+    container name: rocky9
+    tags:
+      rack: r12
+  n02:
+    profiles:
+    - gpu
+    tags:
+      rack: r12
+  n03:
+    profiles:
+    - default
+    - east
+    container name: rocky9
+    tags:
+      rack: r13
+  n04:
+    profiles:
+    - gpu
+    - east
+    kernel:
+      args: quiet
+`
+	registry, err := Parse([]byte(nodesConf))
+	assert.NoError(t, err)
+	nodes, err := registry.FindAllNodes()
+	assert.NoError(t, err)
+
+	var tests = map[string]struct {
+		selectors []string
+		nodes     []string
+		wantErr   bool
+	}{
+		"no selector":              {selectors: nil, nodes: []string{"n01", "n02", "n03", "n04"}},
+		"names":                    {selectors: []string{"n01", "n03"}, nodes: []string{"n01", "n03"}},
+		"name regex":               {selectors: []string{"n0[12]"}, nodes: []string{"n01", "n02"}},
+		"profile":                  {selectors: []string{"@profile:gpu"}, nodes: []string{"n02", "n04"}},
+		"nested profile":           {selectors: []string{"profile=default"}, nodes: []string{"n01", "n02", "n03", "n04"}},
+		"cluster":                  {selectors: []string{"cluster=east"}, nodes: []string{"n03", "n04"}},
+		"container":                {selectors: []string{"container=rocky9"}, nodes: []string{"n01", "n03"}},
+		"container regex":          {selectors: []string{"container=rocky9.*"}, nodes: []string{"n01", "n02", "n03", "n04"}},
+		"tag":                      {selectors: []string{"tag.rack=r12"}, nodes: []string{"n01", "n02"}},
+		"field":                    {selectors: []string{"Kernel.Args=quiet"}, nodes: []string{"n04"}},
+		"field case insensitive":   {selectors: []string{"kernel.args=quiet"}, nodes: []string{"n04"}},
+		"inequality":               {selectors: []string{"tag.rack!=r12"}, nodes: []string{"n03", "n04"}},
+		"negation only":            {selectors: []string{"~@profile:gpu"}, nodes: []string{"n01", "n03"}},
+		"names and negation":       {selectors: []string{"n01", "n02", "n03", "~@profile:gpu"}, nodes: []string{"n01", "n03"}},
+		"union":                    {selectors: []string{"tag.rack=r13", "@profile:gpu"}, nodes: []string{"n02", "n03", "n04"}},
+		"intersection":             {selectors: []string{"cluster=east+@profile:gpu"}, nodes: []string{"n04"}},
+		"intersection with negate": {selectors: []string{"cluster=east+~@profile:gpu"}, nodes: []string{"n03"}},
+		"name pattern with +":      {selectors: []string{"n.+"}, nodes: []string{"n01", "n02", "n03", "n04"}},
+		"name pattern with range":  {selectors: []string{"n[0-9]+"}, nodes: []string{"n01", "n02", "n03", "n04"}},
+		"name pattern with ~":      {selectors: []string{"~n01"}, nodes: nil},
+		"name key":                 {selectors: []string{"~name=n0[12]"}, nodes: []string{"n03", "n04"}},
+		"unknown key":              {selectors: []string{"foo=bar"}, nodes: nil},
+		"map field":                {selectors: []string{"tags[rack]=r13"}, nodes: []string{"n03"}},
+		"unknown profile selector": {selectors: []string{"@foo:bar"}, nodes: nil},
+		"missing value":            {selectors: []string{"@profile:"}, wantErr: true},
+		"invalid name pattern":     {selectors: []string{"n(01"}, wantErr: true},
+		"invalid regex":            {selectors: []string{"cluster=(east"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, err := SelectNodes(nodes, tt.selectors)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var names []string
+			for _, n := range selected {
+				names = append(names, n.Id())
+			}
+			assert.Equal(t, tt.nodes, names)
+		})
+	}
+}
+
+func Test_SelectorValue(t *testing.T) {
+	registry, err := Parse([]byte(`
+nodeprofiles:
+  default:
+    cluster name: east
+nodes:
+  n01:
+    profiles:
+    - default
+    tags:
+      rack: r12
+    kernel:
+      args: quiet`))
+	assert.NoError(t, err)
+	n01, err := registry.GetNode("n01")
+	assert.NoError(t, err)
+	assert.Equal(t, "east", n01.SelectorValue("cluster"))
+	assert.Equal(t, "r12", n01.SelectorValue("tag.rack"))
+	assert.Equal(t, "quiet", n01.SelectorValue("kernel.args"))
+	assert.Equal(t, "", n01.SelectorValue("tag.row"))
+	assert.Equal(t, "", n01.SelectorValue("unknown"))
+}
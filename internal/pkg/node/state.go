@@ -0,0 +1,66 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+// Lifecycle states of a node
+const (
+	StateActive      = "active"
+	StateMaintenance = "maintenance"
+	StateQuarantined = "quarantined"
+	StateRetired     = "retired"
+)
+
+// NodeStates are the valid lifecycle states of a node
+var NodeStates = []string{StateActive, StateMaintenance, StateQuarantined, StateRetired}
+
+// LifecycleState returns the lifecycle state of the node, nodes without
+// a state are active
+func (node *Node) LifecycleState() string {
+	if node.State == "" {
+		return StateActive
+	}
+	return node.State
+}
+
+/*
+SetState sets the lifecycle state of the node together with the reason
+for the change and the time it was made.
+*/
+func (node *Node) SetState(state, reason string, changed time.Time) error {
+	state = strings.ToLower(state)
+	if !util.InSlice(NodeStates, state) {
+		return fmt.Errorf("invalid state %s, must be one of %s", state, strings.Join(NodeStates, ", "))
+	}
+	node.State = state
+	node.StateReason = reason
+	node.StateChanged = changed.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// Bootable returns false for nodes which must not be provisioned:
+// quarantined and retired nodes
+func (node *Node) Bootable() bool {
+	state := node.LifecycleState()
+	return state != StateQuarantined && state != StateRetired
+}
+
+/*
+FilterNodeListByState splits the given nodes into the ones which are not
+in any of the given states and the ones which are.
+*/
+func FilterNodeListByState(set []Node, states ...string) (keep, skipped []Node) {
+	for _, n := range set {
+		if util.InSlice(states, n.LifecycleState()) {
+			skipped = append(skipped, n)
+		} else {
+			keep = append(keep, n)
+		}
+	}
+	return keep, skipped
+}
@@ -0,0 +1,48 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SetState(t *testing.T) {
+	var node Node
+	assert.Equal(t, StateActive, node.LifecycleState())
+	assert.True(t, node.Bootable())
+
+	changed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, node.SetState("Retired", "decommissioned", changed))
+	assert.Equal(t, StateRetired, node.LifecycleState())
+	assert.Equal(t, "decommissioned", node.StateReason)
+	assert.Equal(t, "2024-05-01T12:00:00Z", node.StateChanged)
+	assert.False(t, node.Bootable())
+
+	assert.NoError(t, node.SetState(StateMaintenance, "", changed))
+	assert.True(t, node.Bootable())
+	assert.Equal(t, "", node.StateReason)
+
+	assert.Error(t, node.SetState("broken", "", changed))
+	assert.Equal(t, StateMaintenance, node.LifecycleState())
+}
+
+func Test_FilterNodeListByState(t *testing.T) {
+	nodes := []Node{
+		NewNode("n1"),
+		NewNode("n2"),
+		NewNode("n3"),
+	}
+	nodes[1].State = StateMaintenance
+	nodes[2].State = StateRetired
+
+	keep, skipped := FilterNodeListByState(nodes, StateMaintenance)
+	assert.Len(t, keep, 2)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, "n2", skipped[0].Id())
+
+	keep, skipped = FilterNodeListByState(nodes, StateMaintenance, StateRetired)
+	assert.Len(t, keep, 1)
+	assert.Equal(t, "n1", keep[0].Id())
+	assert.Len(t, skipped, 2)
+}
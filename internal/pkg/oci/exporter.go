@@ -0,0 +1,91 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	imgSpecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/umoci"
+	"github.com/opencontainers/umoci/mutate"
+	"github.com/opencontainers/umoci/oci/cas/dir"
+	"github.com/opencontainers/umoci/oci/casext"
+	"github.com/opencontainers/umoci/oci/layer"
+)
+
+/*
+Export writes rootfs as an image with a single layer to dst, in the
+format of its transport. The image is for linux on the architecture
+arch, runs cmd and carries annotations in its manifest.
+*/
+func Export(ctx context.Context, rootfs string, dst types.ImageReference, arch string, cmd []string, annotations map[string]string) error {
+	tmpDir, err := os.MkdirTemp("", "oci-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	layoutDir := filepath.Join(tmpDir, "layout")
+	if err := dir.Create(layoutDir); err != nil {
+		return fmt.Errorf("unable to create oci layout: %v", err)
+	}
+	engine, err := dir.Open(layoutDir)
+	if err != nil {
+		return fmt.Errorf("unable to open oci layout: %v", err)
+	}
+	defer engine.Close()
+	engineExt := casext.NewEngine(engine)
+	if err := umoci.NewImage(engineExt, "export"); err != nil {
+		return fmt.Errorf("unable to create image: %v", err)
+	}
+	descriptorPaths, err := engineExt.ResolveReference(ctx, "export")
+	if err != nil || len(descriptorPaths) != 1 {
+		return fmt.Errorf("unable to resolve new image: %v", err)
+	}
+	mutator, err := mutate.New(engine, descriptorPaths[0])
+	if err != nil {
+		return err
+	}
+
+	created := time.Now()
+	reader := layer.GenerateInsertLayer(rootfs, "/", false, &layer.RepackOptions{})
+	defer reader.Close()
+	history := &imgSpecs.History{Created: &created, CreatedBy: "wwctl container export"}
+	if _, err := mutator.Add(ctx, imgSpecs.MediaTypeImageLayer, reader, history, mutate.GzipCompressor); err != nil {
+		return fmt.Errorf("unable to add rootfs layer: %v", err)
+	}
+	config, err := mutator.Config(ctx)
+	if err != nil {
+		return err
+	}
+	config.Cmd = cmd
+	meta := mutate.Meta{Created: created, Architecture: arch, OS: "linux"}
+	if err := mutator.Set(ctx, config, meta, annotations, nil); err != nil {
+		return err
+	}
+	newDescriptorPath, err := mutator.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to commit image: %v", err)
+	}
+	if err := engineExt.UpdateReference(ctx, "export", newDescriptorPath.Root()); err != nil {
+		return err
+	}
+
+	srcRef, err := layout.NewReference(layoutDir, "export")
+	if err != nil {
+		return fmt.Errorf("unable to generate local oci reference: %v", err)
+	}
+	policy := &signature.Policy{Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()}}
+	policyCtx, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return fmt.Errorf("unable to create policy context: %v", err)
+	}
+	_, err = copy.Image(ctx, policyCtx, dst, srcRef, &copy.Options{ReportWriter: os.Stdout})
+	return err
+}
@@ -1,8 +1,6 @@
 package overlay
 
 import (
-	"bytes"
-	"encoding/gob"
 	"os"
 	"strconv"
 	"time"
@@ -10,6 +8,7 @@ import (
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/kernel"
 	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
 )
 
 /*
@@ -80,15 +79,19 @@ func InitStruct(overlayName string, nodeData node.Node, allNodes []node.Node) (T
 	dt := time.Now()
 	tstruct.BuildTime = dt.Format("01-02-2006 15:04:05 MST")
 	tstruct.BuildTimeUnix = strconv.FormatInt(dt.Unix(), 10)
-	tstruct.Node.Tags = map[string]string{}
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	dec := gob.NewDecoder(&buf)
-	if err := enc.Encode(nodeData); err != nil {
+	// the secrets of the node are decrypted in its own copy, those of
+	// AllNodes stay encrypted
+	thisNode, err := nodeData.Copy()
+	if err != nil {
 		return tstruct, err
 	}
-	if err := dec.Decode(&tstruct); err != nil {
+	if err := secret.Reveal(&thisNode); err != nil {
 		return tstruct, err
 	}
+	if thisNode.Tags == nil {
+		thisNode.Tags = map[string]string{}
+	}
+	tstruct.Node = thisNode
+	tstruct.ThisNode = &thisNode
 	return tstruct, nil
 }
@@ -0,0 +1,28 @@
+package overlay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_InitStruct(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+
+	nodeData := node.NewNode("n1")
+	nodeData.Tags = map[string]string{"rack": "1"}
+	tstruct, err := InitStruct("x", nodeData, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "x", tstruct.Overlay)
+	assert.Equal(t, "n1", tstruct.Id)
+	assert.Equal(t, "n1", tstruct.ThisNode.Id())
+	assert.Equal(t, "1", tstruct.ThisNode.Tags["rack"])
+
+	// the template data is a copy of the node
+	tstruct.ThisNode.Tags["rack"] = "2"
+	assert.Equal(t, "1", nodeData.Tags["rack"])
+}
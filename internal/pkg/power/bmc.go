@@ -0,0 +1,81 @@
+package power
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/secret"
+)
+
+// BMC backends which can be set per node or profile
+const (
+	BackendIPMI    = "ipmi"
+	BackendLANPlus = "lanplus"
+	BackendRedfish = "redfish"
+)
+
+// Boot devices of BootDev
+const (
+	BootPXE  = "pxe"
+	BootDisk = "disk"
+	BootBIOS = "bios"
+)
+
+// BootDevices are the valid devices of BootDev
+var BootDevices = []string{BootPXE, BootDisk, BootBIOS}
+
+/*
+BMC is the interface to the baseboard management controller of a node.
+The operations return their output, which is also kept together with
+the error for Result, so that results can be collected from a batch.
+*/
+type BMC interface {
+	PowerOn() (string, error)
+	PowerOff() (string, error)
+	PowerCycle() (string, error)
+	PowerReset() (string, error)
+	PowerSoft() (string, error)
+	PowerStatus() (string, error)
+	// BootDev sets the device the node boots from next, one of
+	// BootDevices, for every boot if persistent and in UEFI mode if uefi
+	BootDev(device string, persistent, uefi bool) (string, error)
+	// SetPassword changes the password of the user the BMC is accessed
+	// with
+	SetPassword(password string) (string, error)
+	SDRList() (string, error)
+	SensorList() (string, error)
+	Console() error
+	// OpenConsole connects to the serial console for non-interactive
+	// use, like logging
+	OpenConsole() (io.ReadWriteCloser, error)
+	Result() (string, error)
+	// Address returns the address of the BMC for messages
+	Address() string
+	// SetTimeout limits the time the following operations may take
+	SetTimeout(timeout time.Duration)
+}
+
+/*
+New returns the BMC backend configured for the node: the ipmitool
+templates by default, or the native IPMI or Redfish client. With showOnly the
+operations only return what would be done. Encrypted values of the
+configuration are decrypted.
+*/
+func New(conf node.IpmiConf, showOnly bool) (BMC, error) {
+	conf.Tags = maps.Clone(conf.Tags)
+	if err := secret.Reveal(&conf); err != nil {
+		return nil, err
+	}
+	switch conf.Backend {
+	case "", BackendIPMI:
+		return &IPMI{IpmiConf: conf, ShowOnly: showOnly}, nil
+	case BackendLANPlus:
+		return &LANPlus{IpmiConf: conf, ShowOnly: showOnly}, nil
+	case BackendRedfish:
+		return &Redfish{IpmiConf: conf, ShowOnly: showOnly}, nil
+	}
+	return nil, fmt.Errorf("unknown BMC backend: %s", conf.Backend)
+}
@@ -0,0 +1,61 @@
+package power
+
+import (
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+/*
+commandConsole is a console which is provided by a command, like
+ipmitool sol activate or ssh: the output of the command is read and
+input is written to the command.
+*/
+type commandConsole struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	output *io.PipeReader
+}
+
+// startConsole starts the console command. Reading the console returns
+// the exit error of the command once its output is consumed.
+func startConsole(cmd *exec.Cmd) (io.ReadWriteCloser, error) {
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	// the whole process group of the console command is killed on close
+	// so that no child keeps the output open
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.WaitDelay = time.Second
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := cmd.Wait()
+		if err == nil {
+			err = io.EOF
+		}
+		writer.CloseWithError(err)
+	}()
+	return &commandConsole{cmd: cmd, stdin: stdin, output: reader}, nil
+}
+
+func (console *commandConsole) Read(p []byte) (int, error) {
+	return console.output.Read(p)
+}
+
+func (console *commandConsole) Write(p []byte) (int, error) {
+	return console.stdin.Write(p)
+}
+
+// Close kills the console command
+func (console *commandConsole) Close() error {
+	_ = syscall.Kill(-console.cmd.Process.Pid, syscall.SIGKILL)
+	console.stdin.Close()
+	return console.output.Close()
+}
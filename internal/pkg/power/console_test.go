@@ -0,0 +1,28 @@
+package power
+
+import (
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CommandConsole(t *testing.T) {
+	console, err := startConsole(exec.Command("/bin/sh", "-c", "echo hello; cat"))
+	require.NoError(t, err)
+	_, err = console.Write([]byte("ping\n"))
+	assert.NoError(t, err)
+	buf := make([]byte, 11)
+	_, err = io.ReadFull(console, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\nping\n", string(buf))
+	assert.NoError(t, console.Close())
+
+	console, err = startConsole(exec.Command("/bin/sh", "-c", "echo bye; exit 3"))
+	require.NoError(t, err)
+	out, err := io.ReadAll(console)
+	assert.Equal(t, "bye\n", string(out))
+	assert.ErrorContains(t, err, "exit status 3")
+}
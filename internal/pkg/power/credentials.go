@@ -0,0 +1,158 @@
+package power
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/batch"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+// Limits of the length of generated passwords. IPMI v2.0 passwords
+// have at most 20 characters.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 20
+)
+
+// passwordChars are the characters of generated passwords: letters and
+// digits without the ones which are easily confused, so that passwords
+// need no quoting in BMC templates
+const passwordChars = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+// NewPassword returns a random password with length characters
+func NewPassword(length int) (string, error) {
+	if length < MinPasswordLength || length > MaxPasswordLength {
+		return "", fmt.Errorf("password length must be between %d and %d", MinPasswordLength, MaxPasswordLength)
+	}
+	max := big.NewInt(int64(len(passwordChars)))
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordChars[n.Int64()]
+	}
+	return string(password), nil
+}
+
+/*
+SetPasswords changes the password of the BMC user of every node to the
+password for the node in passwords, logged in with the current
+credentials. The new password is verified by logging in with it, so
+that it may only be stored for the nodes whose result has the state ok.
+A change which failed is verified as well, as the BMC may have applied
+it without responding in time. Only the verification is retried.
+*/
+func SetPasswords(nodes []node.Node, passwords map[string]string, opts Options) []Result {
+	results := make([]Result, len(nodes))
+	fanout := opts.Fanout
+	if fanout < 1 {
+		fanout = 1
+	}
+	batchpool := batch.New(fanout)
+	for i := range nodes {
+		result := &results[i]
+		conf, ok := prepare(result, nodes[i], opts)
+		if !ok {
+			continue
+		}
+		password, ok := passwords[result.Node]
+		if !ok {
+			result.State = StateError
+			result.Reason = "no new password"
+			continue
+		}
+		batchpool.Submit(func() {
+			setPassword(result, conf, password, opts)
+		})
+	}
+	batchpool.Run()
+	return results
+}
+
+// setPassword changes and verifies the password of a single BMC
+func setPassword(result *Result, conf node.IpmiConf, password string, opts Options) {
+	set := Operation{Call: func(bmc BMC) (string, error) {
+		return bmc.SetPassword(password)
+	}}
+	out, err := call(conf, set, opts)
+	result.Attempts = 1
+	result.Output = out
+	if opts.ShowOnly {
+		result.State = StateUnknown
+		return
+	}
+	verify := conf
+	verify.Password = password
+	retries := opts.Retries
+	if err != nil {
+		retries = 0
+	}
+	var verifyErr error
+	for attempt := 0; ; attempt++ {
+		_, verifyErr = call(verify, OperationStatus, opts)
+		if verifyErr == nil || attempt >= retries {
+			break
+		}
+		result.Attempts++
+		time.Sleep(retryDelay)
+	}
+	switch {
+	case verifyErr == nil:
+		result.State = StateOK
+		if err != nil {
+			result.Reason = fmt.Sprintf("new password works although the change failed: %s", err)
+		}
+	case err != nil:
+		result.State = StateError
+		result.Reason = fmt.Sprintf("could not set password: %s", err)
+		if out != "" {
+			result.Reason = fmt.Sprintf("could not set password: %s", out)
+		}
+	default:
+		result.State = StateError
+		result.Reason = fmt.Sprintf("new password not accepted: %s", verifyErr)
+	}
+}
+
+/*
+AuditRecord is an entry of the audit log of changes to BMC credentials.
+It never contains a password.
+*/
+type AuditRecord struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Action  string    `json:"action"`
+	Node    string    `json:"node"`
+	Address string    `json:"address,omitempty"`
+	Account string    `json:"account,omitempty"`
+	State   string    `json:"state"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// Audit appends the records to the audit log file, one JSON object per
+// line
+func Audit(file string, records ...AuditRecord) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return err
+	}
+	log, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(log)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			log.Close()
+			return err
+		}
+	}
+	return log.Close()
+}
@@ -0,0 +1,100 @@
+package power
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/ipmitest"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+func Test_NewPassword(t *testing.T) {
+	password, err := NewPassword(16)
+	require.NoError(t, err)
+	assert.Len(t, password, 16)
+	for _, c := range password {
+		assert.True(t, strings.ContainsRune(passwordChars, c))
+	}
+	other, err := NewPassword(16)
+	require.NoError(t, err)
+	assert.NotEqual(t, password, other)
+
+	_, err = NewPassword(MinPasswordLength - 1)
+	assert.Error(t, err)
+	_, err = NewPassword(MaxPasswordLength + 1)
+	assert.Error(t, err)
+}
+
+func Test_SetPasswords(t *testing.T) {
+	retryDelay = 0
+	sim := ipmitest.New(t)
+	lanplus := lanplusConf(sim)
+	mock := &mockRedfish{sessions: true, powerState: "On"}
+	redfish := newMockRedfish(t, mock)
+	wrong := lanplusConf(ipmitest.New(t))
+	wrong.Password = "wrong"
+	template := func(content string) string {
+		file := path.Join(t.TempDir(), "test.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		return file
+	}
+	// the change fails, but was applied
+	applied := template(`{{ if eq .Cmd "UserList" }}echo 2 admin{{ else if eq .Cmd "SetPassword" }}false{{ else }}echo Chassis Power is on{{ end }}`)
+	// the change succeeds, but the BMC still only accepts the old password
+	rejected := template(`{{ if eq .Cmd "UserList" }}echo 2 admin{{ else if eq .Cmd "SetPassword" }}echo ok{{ else if eq .Password "old" }}echo Chassis Power is on{{ else }}sh -c "echo denied; exit 1"{{ end }}`)
+	nodes := []node.Node{
+		newTestNode("n1", lanplus),
+		newTestNode("n2", redfish),
+		newTestNode("n3", wrong),
+		newTestNode("n4", node.IpmiConf{Ipaddr: net.ParseIP("10.0.0.4"), Template: applied, UserName: "admin"}),
+		newTestNode("n5", node.IpmiConf{Ipaddr: net.ParseIP("10.0.0.5"), Template: rejected, UserName: "admin", Password: "old"}),
+		newTestNode("n6", node.IpmiConf{}),
+		newTestNode("n7", lanplus),
+	}
+	passwords := map[string]string{"n1": "lanplus", "n2": "redfish", "n3": "wrong", "n4": "applied", "n5": "rejected"}
+
+	results := SetPasswords(nodes, passwords, Options{Fanout: 1, Timeout: 5 * time.Second, Retries: 1})
+	require.Len(t, results, 7)
+	assert.Equal(t, Result{Node: "n1", Address: lanplus.Ipaddr.String(), State: StateOK, Output: "Set User Password command successful (user 2)", Attempts: 1}, results[0])
+	client, err := ipmi.Dial(sim.Address(), "admin", "lanplus", 5*time.Second)
+	require.NoError(t, err)
+	client.Close()
+	assert.Equal(t, StateOK, results[1].State)
+	assert.Equal(t, "redfish", mock.password)
+	assert.Equal(t, StateError, results[2].State)
+	assert.Contains(t, results[2].Reason, "could not set password: could not authenticate")
+	assert.Equal(t, StateOK, results[3].State)
+	assert.Contains(t, results[3].Reason, "new password works although the change failed")
+	assert.Equal(t, StateError, results[4].State)
+	assert.Equal(t, 2, results[4].Attempts)
+	assert.Contains(t, results[4].Reason, "new password not accepted")
+	assert.Equal(t, "no IPMI IP address", results[5].Reason)
+	assert.Equal(t, "no new password", results[6].Reason)
+
+	results = SetPasswords(nodes[1:2], passwords, Options{Fanout: 1, ShowOnly: true})
+	assert.Equal(t, StateUnknown, results[0].State)
+	assert.Contains(t, results[0].Output, "PATCH https://")
+}
+
+func Test_Audit(t *testing.T) {
+	file := path.Join(t.TempDir(), "log", "audit.log")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, Audit(file, AuditRecord{Time: now, User: "root", Action: "rotate", Node: "n1", State: StateOK}))
+	require.NoError(t, Audit(file, AuditRecord{Time: now, User: "root", Action: "rotate", Node: "n2", State: StateError, Reason: "failed"}))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"time":"2024-01-02T03:04:05Z","user":"root","action":"rotate","node":"n1","state":"ok"}`, lines[0])
+	var record AuditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "failed", record.Reason)
+}
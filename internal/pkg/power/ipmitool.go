@@ -2,13 +2,17 @@ package power
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/node"
@@ -24,16 +28,30 @@ type IPMI struct {
 	node.IpmiConf
 	ShowOnly bool
 	Cmd      string
-	result   IPMIResult
+	// arguments of the BootDev command
+	BootDevice string
+	Persistent bool
+	UEFI       bool
+	// arguments of the SetPassword command, the id of the user is
+	// looked up with the UserList command
+	NewPassword string
+	UserId      string
+	result      IPMIResult
+	timeout     time.Duration
 }
 
 func (ipmi *IPMI) Result() (string, error) {
 	return ipmi.result.out, ipmi.result.err
 }
 
-func (ipmi *IPMI) getStr() (cmdStr string, err error) {
+/*
+getStr renders the template for ipmi.Cmd. shown is the same command
+with the passwords masked, which is logged and shown instead of the
+command.
+*/
+func (ipmi *IPMI) getStr() (cmdStr string, shown string, err error) {
 	if ipmi.Template == "" {
-		return "", fmt.Errorf("no ipmi/bmc template specified")
+		return "", "", fmt.Errorf("no ipmi/bmc template specified")
 	}
 	if !strings.HasPrefix(ipmi.Template, "/") {
 		conf := warewulfconf.Get()
@@ -41,48 +59,168 @@ func (ipmi *IPMI) getStr() (cmdStr string, err error) {
 	}
 	fbuf, err := os.ReadFile(ipmi.Template)
 	if err != nil {
-		return "", fmt.Errorf("couldn't find the template which defines the ipmi/bmc command: %s", err)
+		return "", "", fmt.Errorf("couldn't find the template which defines the ipmi/bmc command: %s", err)
 	}
-	cmdTmpl, err := template.New("bmc command").Parse(string(fbuf))
+	cmdTmpl, err := template.New("bmc command").Funcs(template.FuncMap{"quote": shellQuote}).Parse(string(fbuf))
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+	if cmdStr, err = renderCommand(cmdTmpl, *ipmi); err != nil {
+		return "", "", err
 	}
+	masked := *ipmi
+	masked.Password = maskPassword(ipmi.Password)
+	masked.NewPassword = maskPassword(ipmi.NewPassword)
+	if shown, err = renderCommand(cmdTmpl, masked); err != nil {
+		return "", "", err
+	}
+	wwlog.Debug("bmc string is: %s", shown)
+	return cmdStr, shown, nil
+}
+
+// renderCommand renders the bmc template with the given values
+func renderCommand(cmdTmpl *template.Template, ipmi IPMI) (string, error) {
 	var tbuffer bytes.Buffer
-	err = cmdTmpl.Execute(&tbuffer, *ipmi)
-	if err != nil {
+	if err := cmdTmpl.Execute(&tbuffer, ipmi); err != nil {
 		return "", err
 	}
 	rg := regexp.MustCompile(`(\r\n?|\n){2,}`)
-	cmdStr = rg.ReplaceAllString(tbuffer.String(), " ")
-	wwlog.Debug("bmc string is: %s", strings.TrimSpace(cmdStr))
-	return strings.TrimSpace(cmdStr), nil
+	return strings.TrimSpace(rg.ReplaceAllString(tbuffer.String(), " ")), nil
+}
 
+// maskPassword replaces every character of a password, so that a
+// template which depends on its length renders the same command
+func maskPassword(password string) string {
+	return strings.Repeat("*", len(password))
 }
 
 func (ipmi *IPMI) Command() ([]byte, error) {
-	cmdStr, err := ipmi.getStr()
+	cmdStr, shown, err := ipmi.getStr()
 	if err != nil {
 		return []byte{}, err
 	}
 	if ipmi.ShowOnly {
-		return []byte(cmdStr), nil
+		return []byte(shown), nil
+	}
+	ctx := context.Background()
+	if ipmi.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ipmi.timeout)
+		defer cancel()
+	}
+	args, err := splitArgs(cmdStr)
+	if err != nil {
+		return []byte{}, err
+	}
+	ipmiCmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// don't wait for children of the command which keep the output open
+	ipmiCmd.WaitDelay = time.Second
+	out, err := ipmiCmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return out, fmt.Errorf("timed out after %s", ipmi.timeout)
 	}
-	ipmiCmd := exec.Command(cmdStr)
-	return ipmiCmd.CombinedOutput()
+	return out, err
 }
 
 func (ipmi *IPMI) InteractiveCommand() (err error) {
-	cmdStr, err := ipmi.getStr()
+	ipmiCmd, err := ipmi.command()
 	if err != nil {
 		return err
 	}
-	ipmiCmd := exec.Command(cmdStr)
 	ipmiCmd.Stdout = os.Stdout
 	ipmiCmd.Stdin = os.Stdin
 	ipmiCmd.Stderr = os.Stderr
 	return ipmiCmd.Run()
 }
 
+// command returns the command for ipmi.Cmd
+func (ipmi *IPMI) command() (*exec.Cmd, error) {
+	cmdStr, _, err := ipmi.getStr()
+	if err != nil {
+		return nil, err
+	}
+	args, err := splitArgs(cmdStr)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(args[0], args[1:]...), nil
+}
+
+/*
+splitArgs splits a rendered template into the arguments of a command
+like a shell does: arguments are separated by white space, single quotes
+keep their content literally, and within double quotes and outside of
+quotes a backslash escapes the next character. Nothing else is
+interpreted, so no shell runs the command and values in the template
+can't inject shell commands.
+*/
+func splitArgs(cmdStr string) (args []string, err error) {
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range cmdStr {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' && r != '$' && r != '`' {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in bmc command")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty bmc command")
+	}
+	return args, nil
+}
+
+// plainArg matches the values which shellQuote returns unquoted
+var plainArg = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_~-]+$`)
+
+// shellQuote quotes a value for a bmc template, so that it is a single
+// argument of the command. Values which need no quotes are returned as
+// they are.
+func shellQuote(value string) string {
+	if plainArg.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
 func (ipmi *IPMI) IPMIInteractiveCommand(cmd string) error {
 	ipmi.Cmd = cmd
 	return ipmi.InteractiveCommand()
@@ -125,6 +263,49 @@ func (ipmi *IPMI) PowerStatus() (string, error) {
 	return ipmi.IPMICommand("PowerStatus")
 }
 
+func (ipmi *IPMI) BootDev(device string, persistent, uefi bool) (string, error) {
+	ipmi.BootDevice = device
+	ipmi.Persistent = persistent
+	ipmi.UEFI = uefi
+	return ipmi.IPMICommand("BootDev")
+}
+
+/*
+SetPassword looks up the id of the user with the UserList command and
+sets its password with the SetPassword command. With ShowOnly, the id
+isn't looked up.
+*/
+func (ipmi *IPMI) SetPassword(password string) (string, error) {
+	ipmi.NewPassword = password
+	if ipmi.ShowOnly {
+		ipmi.UserId = "<id>"
+		return ipmi.IPMICommand("SetPassword")
+	}
+	users, err := ipmi.IPMICommand("UserList")
+	if err != nil {
+		return users, fmt.Errorf("could not list the users of the BMC: %w", err)
+	}
+	if ipmi.UserId = userId(users, ipmi.UserName); ipmi.UserId == "" {
+		ipmi.result = IPMIResult{err: fmt.Errorf("user %s not found on the BMC", ipmi.UserName)}
+		return ipmi.Result()
+	}
+	return ipmi.IPMICommand("SetPassword")
+}
+
+// userId returns the id of the named user in the output of ipmitool
+// user list, whose lines start with the id and the name of a user
+func userId(users string, name string) string {
+	for _, line := range strings.Split(users, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == name {
+			if _, err := strconv.Atoi(fields[0]); err == nil {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
 func (ipmi *IPMI) SDRList() (string, error) {
 	return ipmi.IPMICommand("SDRList")
 }
@@ -136,3 +317,20 @@ func (ipmi *IPMI) SensorList() (string, error) {
 func (ipmi *IPMI) Console() error {
 	return ipmi.IPMIInteractiveCommand("Console")
 }
+
+func (ipmi *IPMI) OpenConsole() (io.ReadWriteCloser, error) {
+	ipmi.Cmd = "Console"
+	cmd, err := ipmi.command()
+	if err != nil {
+		return nil, err
+	}
+	return startConsole(cmd)
+}
+
+func (ipmi *IPMI) Address() string {
+	return ipmi.Ipaddr.String()
+}
+
+func (ipmi *IPMI) SetTimeout(timeout time.Duration) {
+	ipmi.timeout = timeout
+}
@@ -0,0 +1,81 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func Test_splitArgs(t *testing.T) {
+	tests := map[string]struct {
+		cmd  string
+		args []string
+		err  bool
+	}{
+		"plain":            {cmd: "ipmitool -H 10.0.0.1  chassis power on", args: []string{"ipmitool", "-H", "10.0.0.1", "chassis", "power", "on"}},
+		"single quotes":    {cmd: `echo 'a b;$(id)' 'it'\''s'`, args: []string{"echo", "a b;$(id)", "it's"}},
+		"double quotes":    {cmd: `echo "a \"b\" \n"`, args: []string{"echo", `a "b" \n`}},
+		"escape":           {cmd: `echo a\ b`, args: []string{"echo", "a b"}},
+		"empty argument":   {cmd: `echo ''`, args: []string{"echo", ""}},
+		"unterminated":     {cmd: `echo 'a`, err: true},
+		"empty":            {cmd: " ", err: true},
+		"shell operators":  {cmd: "echo a;b && c", args: []string{"echo", "a;b", "&&", "c"}},
+		"newline separate": {cmd: "echo a\nb", args: []string{"echo", "a", "b"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			args, err := splitArgs(tt.cmd)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func Test_IPMICommandNoShell(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("bmc/echo.tmpl", `echo -U {{ quote .UserName }} -P {{ .Password }}`)
+
+	ipmi := &IPMI{IpmiConf: node.IpmiConf{
+		Template: env.GetPath("bmc/echo.tmpl"),
+		UserName: "it's me",
+		Password: "x;touch$IFS" + env.GetPath("pwned"),
+	}}
+	out, err := ipmi.PowerStatus()
+	require.NoError(t, err)
+	assert.Equal(t, "-U it's me -P x;touch$IFS"+env.GetPath("pwned"), out)
+	assert.NoFileExists(t, env.GetPath("pwned"))
+}
+
+func Test_IPMISetPassword(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	env.WriteFile("bmc/echo.tmpl", `{{ if eq .Cmd "UserList" }}printf "1\n2 admin true\n3 other true\n"`+
+		`{{ else }}echo -P {{ quote .Password }} user set password {{ quote .UserId }} {{ quote .NewPassword }}{{ end }}`)
+
+	ipmi := &IPMI{IpmiConf: node.IpmiConf{
+		Template: env.GetPath("bmc/echo.tmpl"),
+		UserName: "admin",
+		Password: "old",
+	}}
+	out, err := ipmi.SetPassword("new password")
+	require.NoError(t, err)
+	assert.Equal(t, "-P old user set password 2 new password", out)
+
+	ipmi.ShowOnly = true
+	out, err = ipmi.SetPassword("new password")
+	require.NoError(t, err)
+	assert.Equal(t, "echo -P '***' user set password '<id>' '************'", out)
+
+	ipmi.ShowOnly = false
+	ipmi.UserName = "missing"
+	_, err = ipmi.SetPassword("new password")
+	assert.EqualError(t, err, "user missing not found on the BMC")
+}
@@ -0,0 +1,301 @@
+package power
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/ipmi"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"golang.org/x/term"
+)
+
+// lanplusBootDevices maps the boot devices of BootDev to IPMI
+var lanplusBootDevices = map[string]byte{
+	BootPXE:  ipmi.BootDevicePXE,
+	BootDisk: ipmi.BootDeviceDisk,
+	BootBIOS: ipmi.BootDeviceBIOS,
+}
+
+/*
+LANPlus is a BMC backend which talks IPMI v2.0 over RMCP+ to the BMC
+itself instead of running ipmitool, so that no process is started per
+operation and the password isn't passed on a command line. The output
+matches the output of the ipmitool template.
+*/
+type LANPlus struct {
+	node.IpmiConf
+	ShowOnly bool
+	result   IPMIResult
+	timeout  time.Duration
+}
+
+func (lan *LANPlus) Result() (string, error) {
+	return lan.result.out, lan.result.err
+}
+
+func (lan *LANPlus) Address() string {
+	return lan.Ipaddr.String()
+}
+
+func (lan *LANPlus) SetTimeout(timeout time.Duration) {
+	lan.timeout = timeout
+}
+
+// address returns the address of the BMC, on port 623 unless another
+// port is configured
+func (lan *LANPlus) address() string {
+	port := lan.Port
+	if port == "" {
+		port = "623"
+	}
+	return net.JoinHostPort(lan.Ipaddr.String(), port)
+}
+
+// run opens a session, runs the operation and keeps its result. With
+// ShowOnly only the description of the operation is returned.
+func (lan *LANPlus) run(description string, operation func(client *ipmi.Client) (string, error)) (string, error) {
+	if lan.ShowOnly {
+		lan.result = IPMIResult{out: fmt.Sprintf("lanplus %s@%s %s", lan.UserName, lan.address(), description)}
+		return lan.Result()
+	}
+	client, err := ipmi.Dial(lan.address(), lan.UserName, lan.Password, lan.timeout)
+	if err != nil {
+		lan.result = IPMIResult{err: err}
+		return lan.Result()
+	}
+	defer client.Close()
+	out, err := operation(client)
+	lan.result = IPMIResult{out: out, err: err}
+	return lan.Result()
+}
+
+// control runs a chassis control action
+func (lan *LANPlus) control(description string, action byte, out string) (string, error) {
+	return lan.run(description, func(client *ipmi.Client) (string, error) {
+		if err := client.ChassisControl(action); err != nil {
+			return "", err
+		}
+		return out, nil
+	})
+}
+
+func (lan *LANPlus) PowerOn() (string, error) {
+	return lan.control("chassis power on", ipmi.ChassisPowerUp, "Chassis Power Control: Up/On")
+}
+
+func (lan *LANPlus) PowerOff() (string, error) {
+	return lan.control("chassis power off", ipmi.ChassisPowerDown, "Chassis Power Control: Down/Off")
+}
+
+func (lan *LANPlus) PowerCycle() (string, error) {
+	return lan.control("chassis power cycle", ipmi.ChassisPowerCycle, "Chassis Power Control: Cycle")
+}
+
+func (lan *LANPlus) PowerReset() (string, error) {
+	return lan.control("chassis power reset", ipmi.ChassisHardReset, "Chassis Power Control: Reset")
+}
+
+func (lan *LANPlus) PowerSoft() (string, error) {
+	return lan.control("chassis power soft", ipmi.ChassisSoftOff, "Chassis Power Control: Soft")
+}
+
+func (lan *LANPlus) PowerStatus() (string, error) {
+	return lan.run("chassis power status", func(client *ipmi.Client) (string, error) {
+		on, err := client.PowerOn()
+		if err != nil {
+			return "", err
+		}
+		if on {
+			return "Chassis Power is on", nil
+		}
+		return "Chassis Power is off", nil
+	})
+}
+
+func (lan *LANPlus) BootDev(device string, persistent, uefi bool) (string, error) {
+	code, ok := lanplusBootDevices[device]
+	if !ok {
+		lan.result = IPMIResult{err: fmt.Errorf("unknown boot device: %s", device)}
+		return lan.Result()
+	}
+	description := "chassis bootdev " + device
+	if persistent {
+		description += " persistent"
+	}
+	if uefi {
+		description += " efiboot"
+	}
+	return lan.run(description, func(client *ipmi.Client) (string, error) {
+		if err := client.SetBootDevice(code, persistent, uefi); err != nil {
+			return "", err
+		}
+		return "Set Boot Device to " + device, nil
+	})
+}
+
+// SetPassword changes the password of the user the BMC is accessed with
+func (lan *LANPlus) SetPassword(password string) (string, error) {
+	return lan.run("user set password "+lan.UserName, func(client *ipmi.Client) (string, error) {
+		id, err := client.UserID(lan.UserName)
+		if err != nil {
+			return "", err
+		}
+		if err := client.SetUserPassword(id, password); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Set User Password command successful (user %d)", id), nil
+	})
+}
+
+// formatValue formats a converted reading or threshold like ipmitool
+// sensor list
+func formatValue(value *float64) string {
+	if value == nil {
+		return "na"
+	}
+	return strconv.FormatFloat(*value, 'f', 3, 64)
+}
+
+// SDRList returns the readings of the sensors like ipmitool sdr list
+func (lan *LANPlus) SDRList() (string, error) {
+	return lan.run("sdr list", func(client *ipmi.Client) (string, error) {
+		sensors, err := client.Sensors()
+		if err != nil {
+			return "", err
+		}
+		var lines []string
+		for _, sensor := range sensors {
+			reading, err := client.SensorReading(sensor)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", sensor.Name, err)
+			}
+			value := "no reading"
+			switch {
+			case reading.Value != nil:
+				value = strconv.FormatFloat(*reading.Value, 'f', -1, 64) + " " + sensor.Unit
+			case reading.Available:
+				value = fmt.Sprintf("0x%02x", reading.States&0xff)
+			}
+			lines = append(lines, fmt.Sprintf("%-16s | %-17s | %s", sensor.Name, value, reading.Status(sensor)))
+		}
+		return strings.Join(lines, "\n"), nil
+	})
+}
+
+// SensorList returns the readings with their thresholds like ipmitool
+// sensor list
+func (lan *LANPlus) SensorList() (string, error) {
+	return lan.run("sensor list", func(client *ipmi.Client) (string, error) {
+		sensors, err := client.Sensors()
+		if err != nil {
+			return "", err
+		}
+		var lines []string
+		for _, sensor := range sensors {
+			reading, err := client.SensorReading(sensor)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", sensor.Name, err)
+			}
+			value, unit, status := formatValue(reading.Value), sensor.Unit, reading.Status(sensor)
+			if !sensor.Analog {
+				value, unit, status = fmt.Sprintf("0x%x", reading.Raw), "discrete", fmt.Sprintf("0x%04x", reading.States)
+			} else if !reading.Available {
+				status = "na"
+			}
+			thresholds, err := client.SensorThresholds(sensor)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", sensor.Name, err)
+			}
+			lines = append(lines, fmt.Sprintf("%-16s | %-10s | %-10s | %-6s | %-9s | %-9s | %-9s | %-9s | %-9s | %s",
+				sensor.Name, value, unit, status,
+				formatValue(thresholds.LowerNonRecoverable), formatValue(thresholds.LowerCritical),
+				formatValue(thresholds.LowerNonCritical), formatValue(thresholds.UpperNonCritical),
+				formatValue(thresholds.UpperCritical), formatValue(thresholds.UpperNonRecoverable)))
+		}
+		return strings.Join(lines, "\n"), nil
+	})
+}
+
+// OpenConsole activates serial over LAN
+func (lan *LANPlus) OpenConsole() (io.ReadWriteCloser, error) {
+	client, err := ipmi.Dial(lan.address(), lan.UserName, lan.Password, lan.timeout)
+	if err != nil {
+		return nil, err
+	}
+	sol, err := client.ActivateSOL()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return sol, nil
+}
+
+/*
+Console connects the terminal to serial over LAN until the escape
+character (~ by default) is followed by a period.
+*/
+func (lan *LANPlus) Console() error {
+	escape := byte('~')
+	if lan.EscapeChar != "" {
+		escape = lan.EscapeChar[0]
+	}
+	sol, err := lan.OpenConsole()
+	if err != nil {
+		return err
+	}
+	defer sol.Close()
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+	}
+	fmt.Fprintf(os.Stderr, "[SOL session operational. Use %c. to quit]\r\n", escape)
+
+	output := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(os.Stdout, sol)
+		output <- err
+	}()
+	input := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 256)
+		var last byte
+		for {
+			n, err := os.Stdin.Read(buf)
+			for i := 0; i < n; i++ {
+				if last == escape && buf[i] == '.' {
+					if i > 1 {
+						_, _ = sol.Write(buf[:i-1])
+					}
+					input <- nil
+					return
+				}
+				last = buf[i]
+			}
+			if n > 0 {
+				if _, err := sol.Write(buf[:n]); err != nil {
+					input <- err
+					return
+				}
+			}
+			if err != nil {
+				input <- nil
+				return
+			}
+		}
+	}()
+	select {
+	case err = <-output:
+	case err = <-input:
+	}
+	fmt.Fprint(os.Stderr, "\r\n[terminated]\r\n")
+	return err
+}
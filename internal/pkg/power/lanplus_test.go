@@ -0,0 +1,120 @@
+package power
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi"
+	"github.com/warewulf/warewulf/internal/pkg/ipmi/ipmitest"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+// lanplusConf returns the configuration of the lanplus backend for a
+// simulated BMC
+func lanplusConf(sim *ipmitest.Simulator) node.IpmiConf {
+	return node.IpmiConf{
+		Ipaddr:   net.ParseIP(sim.Host()),
+		Port:     sim.Port(),
+		UserName: ipmitest.Username,
+		Password: ipmitest.Password,
+		Backend:  BackendLANPlus,
+	}
+}
+
+func Test_LANPlusPower(t *testing.T) {
+	sim := ipmitest.New(t)
+	conf := lanplusConf(sim)
+	bmc, err := New(conf, false)
+	assert.NoError(t, err)
+
+	out, err := bmc.PowerStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, "Chassis Power is off", out)
+	state, _ := ParseState(out)
+	assert.Equal(t, StateOff, state)
+
+	out, err = bmc.PowerOn()
+	assert.NoError(t, err)
+	assert.Equal(t, "Chassis Power Control: Up/On", out)
+	assert.True(t, sim.Power())
+	out, err = bmc.PowerStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, "Chassis Power is on", out)
+
+	for _, operation := range []func() (string, error){bmc.PowerCycle, bmc.PowerReset, bmc.PowerSoft, bmc.PowerOff} {
+		_, err := operation()
+		assert.NoError(t, err)
+	}
+	assert.False(t, sim.Power())
+	assert.Equal(t, 0, sim.Sessions())
+
+	out, err = bmc.BootDev(BootDisk, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Set Boot Device to disk", out)
+	assert.Equal(t, []byte{0xc0, ipmi.BootDeviceDisk, 0x00, 0x00, 0x00}, sim.BootFlags())
+	_, err = bmc.BootDev("floppy", false, false)
+	assert.Error(t, err)
+
+	wrongPassword := conf
+	wrongPassword.Password = "wrong"
+	bmc, err = New(wrongPassword, false)
+	assert.NoError(t, err)
+	_, err = bmc.PowerOn()
+	assert.ErrorContains(t, err, "invalid password")
+	_, err = bmc.Result()
+	assert.Error(t, err)
+}
+
+func Test_LANPlusSensors(t *testing.T) {
+	sim := ipmitest.New(t)
+	conf := lanplusConf(sim)
+	sim.AddSensor(ipmitest.Sensor{Name: "CPU Temp", Number: 1, Unit: 1, M: 1, Raw: 92, States: 0x08,
+		ThresholdMask: 0x3b, Thresholds: [6]byte{90, 5, 0, 90, 95, 100}})
+	sim.AddSensor(ipmitest.Sensor{Name: "Fan1", Number: 2, Unit: 18, M: 100, Unavailable: true})
+	sim.AddSensor(ipmitest.Sensor{Name: "Intrusion", Number: 3, Discrete: true, States: 0x00})
+	bmc, err := New(conf, false)
+	assert.NoError(t, err)
+
+	out, err := bmc.SDRList()
+	assert.NoError(t, err)
+	assert.Equal(t, `CPU Temp         | 92 degrees C      | nc
+Fan1             | no reading        | ns
+Intrusion        | 0x00              | ok`, out)
+
+	out, err = bmc.SensorList()
+	assert.NoError(t, err)
+	assert.Equal(t, `CPU Temp         | 92.000     | degrees C  | nc     | na        | 5.000     | 90.000    | 90.000    | 95.000    | 100.000
+Fan1             | na         | RPM        | na     | na        | na        | na        | na        | na        | na
+Intrusion        | 0x0        | discrete   | 0x8000 | na        | na        | na        | na        | na        | na`, out)
+}
+
+func Test_LANPlusConsole(t *testing.T) {
+	sim := ipmitest.New(t)
+	conf := lanplusConf(sim)
+	bmc, err := New(conf, false)
+	assert.NoError(t, err)
+	console, err := bmc.OpenConsole()
+	require.NoError(t, err)
+	_, err = console.Write([]byte("ping\n"))
+	assert.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(console, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping\n", string(buf))
+	assert.NoError(t, console.Close())
+	assert.Equal(t, 0, sim.Sessions())
+}
+
+func Test_LANPlusShowOnly(t *testing.T) {
+	bmc, err := New(node.IpmiConf{Ipaddr: net.ParseIP("10.10.10.10"), UserName: "admin", Backend: BackendLANPlus}, true)
+	assert.NoError(t, err)
+	out, err := bmc.PowerOn()
+	assert.NoError(t, err)
+	assert.Equal(t, "lanplus admin@10.10.10.10:623 chassis power on", out)
+	out, err = bmc.BootDev(BootPXE, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "lanplus admin@10.10.10.10:623 chassis bootdev pxe persistent efiboot", out)
+}
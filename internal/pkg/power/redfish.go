@@ -0,0 +1,527 @@
+package power
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/wwlog"
+)
+
+// redfishTimeout is the timeout of a single request to a Redfish service
+const redfishTimeout = 30 * time.Second
+
+/*
+Redfish is a BMC backend which talks to the Redfish service of the BMC
+over HTTPS. A session is created for every operation and deleted
+afterwards; services without a session service are accessed with basic
+authentication.
+*/
+type Redfish struct {
+	node.IpmiConf
+	ShowOnly bool
+	result   IPMIResult
+	client   *http.Client
+	token    string
+	session  string
+	timeout  time.Duration
+}
+
+type redfishLink struct {
+	ID string `json:"@odata.id"`
+}
+
+type redfishStatus struct {
+	State  string
+	Health string
+}
+
+type redfishSystem struct {
+	ID         string `json:"@odata.id"`
+	PowerState string
+	Actions    struct {
+		Reset struct {
+			Target string `json:"target"`
+		} `json:"#ComputerSystem.Reset"`
+	}
+	Links struct {
+		Chassis []redfishLink
+	}
+	SerialConsole struct {
+		SSH struct {
+			ServiceEnabled bool
+			Port           int
+		}
+	}
+}
+
+type redfishChassis struct {
+	Thermal redfishLink
+	Power   redfishLink
+}
+
+type redfishReading struct {
+	Name           string
+	ReadingCelsius *float64
+	Reading        *float64
+	ReadingUnits   string
+	ReadingVolts   *float64
+	Status         redfishStatus
+}
+
+type redfishThermal struct {
+	Temperatures []redfishReading
+	Fans         []redfishReading
+}
+
+type redfishPower struct {
+	Voltages     []redfishReading
+	PowerControl []struct {
+		Name               string
+		PowerConsumedWatts *float64
+	}
+}
+
+func (redfish *Redfish) Result() (string, error) {
+	return redfish.result.out, redfish.result.err
+}
+
+func (redfish *Redfish) Address() string {
+	return redfish.Ipaddr.String()
+}
+
+// SetTimeout limits every request to the Redfish service to timeout
+// instead of the default of 30 seconds
+func (redfish *Redfish) SetTimeout(timeout time.Duration) {
+	redfish.timeout = timeout
+}
+
+// baseURL returns the URL of the Redfish service, on port 443 unless
+// the Redfish port is configured. The IPMI port isn't used, as it is
+// the port of the IPMI service.
+func (redfish *Redfish) baseURL() string {
+	port := redfish.RedfishPort
+	if port == "" {
+		port = "443"
+	}
+	return "https://" + net.JoinHostPort(redfish.Ipaddr.String(), port)
+}
+
+// url returns the absolute URL for an @odata.id or a Location header
+func (redfish *Redfish) url(path string) string {
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		return path
+	}
+	return redfish.baseURL() + path
+}
+
+// request sends a request to the Redfish service and decodes the JSON
+// response into result, if it isn't nil
+func (redfish *Redfish) request(method, path string, body, result interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, redfish.url(path), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if redfish.token != "" {
+		req.Header.Set("X-Auth-Token", redfish.token)
+	} else {
+		req.SetBasicAuth(redfish.UserName, redfish.Password)
+	}
+	wwlog.Debug("redfish: %s %s", method, req.URL)
+	resp, err := redfish.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode >= 300 {
+		var redfishErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &redfishErr) == nil && redfishErr.Error.Message != "" {
+			return resp, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, redfishErr.Error.Message)
+		}
+		return resp, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if result != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return resp, fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// login creates a session, or falls back to basic authentication if
+// the service has no session service
+func (redfish *Redfish) login() error {
+	timeout := redfishTimeout
+	if redfish.timeout > 0 {
+		timeout = redfish.timeout
+	}
+	redfish.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: redfish.Insecure.Bool()}, //nolint:gosec
+		},
+	}
+	credentials := map[string]string{"UserName": redfish.UserName, "Password": redfish.Password}
+	resp, err := redfish.request(http.MethodPost, "/redfish/v1/SessionService/Sessions", credentials, nil)
+	if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed) {
+		wwlog.Debug("redfish: no session service on %s, using basic authentication", redfish.Address())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not log in: %w", err)
+	}
+	redfish.token = resp.Header.Get("X-Auth-Token")
+	redfish.session = resp.Header.Get("Location")
+	if redfish.token == "" {
+		return fmt.Errorf("could not log in: no session token returned")
+	}
+	return nil
+}
+
+// logout deletes the session created by login
+func (redfish *Redfish) logout() {
+	if redfish.session != "" {
+		if _, err := redfish.request(http.MethodDelete, redfish.session, nil, nil); err != nil {
+			wwlog.Debug("redfish: could not delete session: %s", err)
+		}
+	}
+	redfish.token = ""
+	redfish.session = ""
+}
+
+// run executes an operation within a session and keeps its result
+func (redfish *Redfish) run(operation func() (string, error)) (string, error) {
+	out, err := func() (string, error) {
+		if err := redfish.login(); err != nil {
+			return "", err
+		}
+		defer redfish.logout()
+		return operation()
+	}()
+	redfish.result.out = strings.TrimSpace(out)
+	redfish.result.err = err
+	return redfish.result.out, redfish.result.err
+}
+
+// system returns the first computer system of the service
+func (redfish *Redfish) system() (system redfishSystem, err error) {
+	var systems struct {
+		Members []redfishLink
+	}
+	if _, err = redfish.request(http.MethodGet, "/redfish/v1/Systems", nil, &systems); err != nil {
+		return system, err
+	}
+	if len(systems.Members) == 0 {
+		return system, fmt.Errorf("no computer system found")
+	}
+	_, err = redfish.request(http.MethodGet, systems.Members[0].ID, nil, &system)
+	if system.ID == "" {
+		system.ID = systems.Members[0].ID
+	}
+	return system, err
+}
+
+// reset sends a ComputerSystem.Reset action with the given reset type
+func (redfish *Redfish) reset(resetType string) (string, error) {
+	if redfish.ShowOnly {
+		out := fmt.Sprintf("POST %s/redfish/v1/Systems/<system>/Actions/ComputerSystem.Reset {\"ResetType\":%q}", redfish.baseURL(), resetType)
+		redfish.result.out = out
+		return out, nil
+	}
+	return redfish.run(func() (string, error) {
+		system, err := redfish.system()
+		if err != nil {
+			return "", err
+		}
+		target := system.Actions.Reset.Target
+		if target == "" {
+			target = system.ID + "/Actions/ComputerSystem.Reset"
+		}
+		if _, err := redfish.request(http.MethodPost, target, map[string]string{"ResetType": resetType}, nil); err != nil {
+			return "", err
+		}
+		return "Power control: " + resetType, nil
+	})
+}
+
+func (redfish *Redfish) PowerOn() (string, error) {
+	return redfish.reset("On")
+}
+
+func (redfish *Redfish) PowerOff() (string, error) {
+	return redfish.reset("ForceOff")
+}
+
+func (redfish *Redfish) PowerCycle() (string, error) {
+	return redfish.reset("PowerCycle")
+}
+
+func (redfish *Redfish) PowerReset() (string, error) {
+	return redfish.reset("ForceRestart")
+}
+
+func (redfish *Redfish) PowerSoft() (string, error) {
+	return redfish.reset("GracefulShutdown")
+}
+
+func (redfish *Redfish) PowerStatus() (string, error) {
+	if redfish.ShowOnly {
+		out := fmt.Sprintf("GET %s/redfish/v1/Systems/<system> PowerState", redfish.baseURL())
+		redfish.result.out = out
+		return out, nil
+	}
+	return redfish.run(func() (string, error) {
+		system, err := redfish.system()
+		if err != nil {
+			return "", err
+		}
+		if system.PowerState == "" {
+			return "", fmt.Errorf("no power state reported")
+		}
+		return "Power is " + strings.ToLower(system.PowerState), nil
+	})
+}
+
+// redfishBootTargets maps the devices of BootDev to the
+// BootSourceOverrideTarget of Redfish
+var redfishBootTargets = map[string]string{
+	BootPXE:  "Pxe",
+	BootDisk: "Hdd",
+	BootBIOS: "BiosSetup",
+}
+
+/*
+BootDev sets the boot source override of the system. The boot mode is
+only set to UEFI if uefi is true, it isn't changed otherwise.
+*/
+func (redfish *Redfish) BootDev(device string, persistent, uefi bool) (string, error) {
+	target, ok := redfishBootTargets[device]
+	if !ok {
+		return "", fmt.Errorf("invalid boot device %s, must be one of %s", device, strings.Join(BootDevices, ", "))
+	}
+	boot := map[string]string{
+		"BootSourceOverrideTarget":  target,
+		"BootSourceOverrideEnabled": "Once",
+	}
+	if persistent {
+		boot["BootSourceOverrideEnabled"] = "Continuous"
+	}
+	if uefi {
+		boot["BootSourceOverrideMode"] = "UEFI"
+	}
+	body := map[string]interface{}{"Boot": boot}
+	if redfish.ShowOnly {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		out := fmt.Sprintf("PATCH %s/redfish/v1/Systems/<system> %s", redfish.baseURL(), data)
+		redfish.result.out = out
+		return out, nil
+	}
+	return redfish.run(func() (string, error) {
+		system, err := redfish.system()
+		if err != nil {
+			return "", err
+		}
+		if _, err := redfish.request(http.MethodPatch, system.ID, body, nil); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Boot device: %s (%s)", target, boot["BootSourceOverrideEnabled"]), nil
+	})
+}
+
+/*
+SetPassword changes the password of the account the service is accessed
+with, which is looked up by its user name in the account service.
+*/
+func (redfish *Redfish) SetPassword(password string) (string, error) {
+	body := map[string]string{"Password": password}
+	if redfish.ShowOnly {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		out := fmt.Sprintf("PATCH %s/redfish/v1/AccountService/Accounts/<%s> %s", redfish.baseURL(), redfish.UserName, data)
+		redfish.result.out = out
+		return out, nil
+	}
+	return redfish.run(func() (string, error) {
+		var accounts struct {
+			Members []redfishLink
+		}
+		if _, err := redfish.request(http.MethodGet, "/redfish/v1/AccountService/Accounts", nil, &accounts); err != nil {
+			return "", err
+		}
+		for _, member := range accounts.Members {
+			var account struct {
+				UserName string
+			}
+			if _, err := redfish.request(http.MethodGet, member.ID, nil, &account); err != nil {
+				return "", err
+			}
+			if account.UserName != redfish.UserName {
+				continue
+			}
+			if _, err := redfish.request(http.MethodPatch, member.ID, body, nil); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Password of %s changed", redfish.UserName), nil
+		}
+		return "", fmt.Errorf("account %s not found", redfish.UserName)
+	})
+}
+
+// SDRList returns the same readings as SensorList, Redfish doesn't
+// distinguish them
+func (redfish *Redfish) SDRList() (string, error) {
+	return redfish.SensorList()
+}
+
+/*
+SensorList returns the temperatures, fans, voltages and power
+consumption of the first chassis of the system, one reading per line
+in the format of ipmitool: name | reading | units | status.
+*/
+func (redfish *Redfish) SensorList() (string, error) {
+	if redfish.ShowOnly {
+		out := fmt.Sprintf("GET %s/redfish/v1/Chassis/<chassis>/Thermal %s/redfish/v1/Chassis/<chassis>/Power", redfish.baseURL(), redfish.baseURL())
+		redfish.result.out = out
+		return out, nil
+	}
+	return redfish.run(func() (string, error) {
+		system, err := redfish.system()
+		if err != nil {
+			return "", err
+		}
+		if len(system.Links.Chassis) == 0 {
+			return "", fmt.Errorf("no chassis found")
+		}
+		var chassis redfishChassis
+		if _, err := redfish.request(http.MethodGet, system.Links.Chassis[0].ID, nil, &chassis); err != nil {
+			return "", err
+		}
+		var lines []string
+		if chassis.Thermal.ID != "" {
+			var thermal redfishThermal
+			if _, err := redfish.request(http.MethodGet, chassis.Thermal.ID, nil, &thermal); err != nil {
+				return "", err
+			}
+			for _, reading := range thermal.Temperatures {
+				lines = append(lines, sensorLine(reading.Name, reading.ReadingCelsius, "degrees C", reading.Status))
+			}
+			for _, reading := range thermal.Fans {
+				units := reading.ReadingUnits
+				if units == "" {
+					units = "RPM"
+				}
+				lines = append(lines, sensorLine(reading.Name, reading.Reading, units, reading.Status))
+			}
+		}
+		if chassis.Power.ID != "" {
+			var power redfishPower
+			if _, err := redfish.request(http.MethodGet, chassis.Power.ID, nil, &power); err != nil {
+				return "", err
+			}
+			for _, reading := range power.Voltages {
+				lines = append(lines, sensorLine(reading.Name, reading.ReadingVolts, "Volts", reading.Status))
+			}
+			for _, control := range power.PowerControl {
+				lines = append(lines, sensorLine(control.Name, control.PowerConsumedWatts, "Watts", redfishStatus{}))
+			}
+		}
+		return strings.Join(lines, "\n"), nil
+	})
+}
+
+// sensorLine formats a single sensor reading
+func sensorLine(name string, reading *float64, units string, status redfishStatus) string {
+	value := "na"
+	if reading != nil {
+		value = strconv.FormatFloat(*reading, 'f', -1, 64)
+	}
+	health := "ok"
+	if status.State != "" && status.State != "Enabled" {
+		health = "ns"
+	} else if status.Health != "" {
+		health = strings.ToLower(status.Health)
+	}
+	return fmt.Sprintf("%-24s | %-10s | %-10s | %s", name, value, units, health)
+}
+
+/*
+Console connects to the serial console of the system over SSH, if the
+Redfish service announces it.
+*/
+func (redfish *Redfish) Console() error {
+	sshCmd, err := redfish.consoleCommand()
+	if err != nil {
+		return err
+	}
+	sshCmd.Stdout = os.Stdout
+	sshCmd.Stdin = os.Stdin
+	sshCmd.Stderr = os.Stderr
+	return sshCmd.Run()
+}
+
+// OpenConsole connects to the serial console of the system over SSH
+func (redfish *Redfish) OpenConsole() (io.ReadWriteCloser, error) {
+	sshCmd, err := redfish.consoleCommand()
+	if err != nil {
+		return nil, err
+	}
+	return startConsole(sshCmd)
+}
+
+// consoleCommand returns the SSH command which connects to the serial
+// console of the system
+func (redfish *Redfish) consoleCommand() (*exec.Cmd, error) {
+	system, err := func() (redfishSystem, error) {
+		if err := redfish.login(); err != nil {
+			return redfishSystem{}, err
+		}
+		defer redfish.logout()
+		return redfish.system()
+	}()
+	if err != nil {
+		return nil, err
+	}
+	if !system.SerialConsole.SSH.ServiceEnabled {
+		return nil, fmt.Errorf("%s: serial console over SSH is not enabled", redfish.Address())
+	}
+	port := system.SerialConsole.SSH.Port
+	if port == 0 {
+		port = 22
+	}
+	return exec.Command("ssh", "-p", strconv.Itoa(port), "-l", redfish.UserName, redfish.Ipaddr.String()), nil
+}
@@ -0,0 +1,250 @@
+package power
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+// mockRedfish is a minimal Redfish service with a single system and
+// chassis
+type mockRedfish struct {
+	sync.Mutex
+	sessions   bool // whether the session service is available
+	powerState string
+	resets     []string
+	boot       map[string]string
+	logins     int
+	logouts    int
+	delay      time.Duration // delay of every response
+	failures   int           // number of system listings which fail
+	password   string        // password of admin, secret if empty
+}
+
+// secret returns the password of admin
+func (mock *mockRedfish) secret() string {
+	if mock.password == "" {
+		return "secret"
+	}
+	return mock.password
+}
+
+func (mock *mockRedfish) authorized(r *http.Request) bool {
+	if mock.sessions {
+		return r.Header.Get("X-Auth-Token") == "token"
+	}
+	user, pass, ok := r.BasicAuth()
+	return ok && user == "admin" && pass == mock.secret()
+}
+
+func (mock *mockRedfish) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(mock.delay)
+	mock.Lock()
+	defer mock.Unlock()
+	reply := func(body string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+	if r.URL.Path == "/redfish/v1/SessionService/Sessions" && r.Method == http.MethodPost {
+		if !mock.sessions {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var credentials map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&credentials)
+		if credentials["UserName"] != "admin" || credentials["Password"] != mock.secret() {
+			w.WriteHeader(http.StatusUnauthorized)
+			reply(`{"error": {"message": "invalid credentials"}}`)
+			return
+		}
+		mock.logins++
+		w.Header().Set("X-Auth-Token", "token")
+		w.Header().Set("Location", "/redfish/v1/SessionService/Sessions/1")
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	if !mock.authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.Method + " " + r.URL.Path {
+	case "DELETE /redfish/v1/SessionService/Sessions/1":
+		mock.logouts++
+		w.WriteHeader(http.StatusNoContent)
+	case "GET /redfish/v1/Systems":
+		if mock.failures > 0 {
+			mock.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		reply(`{"Members": [{"@odata.id": "/redfish/v1/Systems/1"}]}`)
+	case "GET /redfish/v1/Systems/1":
+		reply(`{
+  "@odata.id": "/redfish/v1/Systems/1",
+  "PowerState": "` + mock.powerState + `",
+  "Actions": {"#ComputerSystem.Reset": {"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"}},
+  "Links": {"Chassis": [{"@odata.id": "/redfish/v1/Chassis/1"}]}
+}`)
+	case "PATCH /redfish/v1/Systems/1":
+		var body struct {
+			Boot map[string]string
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mock.boot = body.Boot
+		w.WriteHeader(http.StatusNoContent)
+	case "POST /redfish/v1/Systems/1/Actions/ComputerSystem.Reset":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mock.resets = append(mock.resets, body["ResetType"])
+		w.WriteHeader(http.StatusNoContent)
+	case "GET /redfish/v1/AccountService/Accounts":
+		reply(`{"Members": [{"@odata.id": "/redfish/v1/AccountService/Accounts/1"}, {"@odata.id": "/redfish/v1/AccountService/Accounts/2"}]}`)
+	case "GET /redfish/v1/AccountService/Accounts/1":
+		reply(`{"UserName": "operator"}`)
+	case "GET /redfish/v1/AccountService/Accounts/2":
+		reply(`{"UserName": "admin"}`)
+	case "PATCH /redfish/v1/AccountService/Accounts/2":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mock.password = body["Password"]
+		w.WriteHeader(http.StatusNoContent)
+	case "GET /redfish/v1/Chassis/1":
+		reply(`{"Thermal": {"@odata.id": "/redfish/v1/Chassis/1/Thermal"}, "Power": {"@odata.id": "/redfish/v1/Chassis/1/Power"}}`)
+	case "GET /redfish/v1/Chassis/1/Thermal":
+		reply(`{
+  "Temperatures": [{"Name": "CPU1 Temp", "ReadingCelsius": 45, "Status": {"State": "Enabled", "Health": "OK"}}],
+  "Fans": [{"Name": "Fan1", "Reading": 4200, "ReadingUnits": "RPM", "Status": {"State": "Absent"}}]
+}`)
+	case "GET /redfish/v1/Chassis/1/Power":
+		reply(`{
+  "Voltages": [{"Name": "12V", "ReadingVolts": 12.1, "Status": {"State": "Enabled", "Health": "Warning"}}],
+  "PowerControl": [{"Name": "System Power", "PowerConsumedWatts": 230}]
+}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newMockRedfish starts a mock Redfish service and returns the BMC
+// configuration to access it
+func newMockRedfish(t *testing.T, mock *mockRedfish) node.IpmiConf {
+	server := httptest.NewTLSServer(mock)
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	host, port, err := net.SplitHostPort(serverURL.Host)
+	assert.NoError(t, err)
+	return node.IpmiConf{
+		Ipaddr:      net.ParseIP(host),
+		Port:        "623",
+		RedfishPort: port,
+		UserName:    "admin",
+		Password:    "secret",
+		Backend:     BackendRedfish,
+		Insecure:    "true",
+	}
+}
+
+func Test_RedfishPower(t *testing.T) {
+	for _, sessions := range []bool{true, false} {
+		mock := &mockRedfish{sessions: sessions, powerState: "On"}
+		conf := newMockRedfish(t, mock)
+		bmc, err := New(conf, false)
+		assert.NoError(t, err)
+
+		out, err := bmc.PowerStatus()
+		assert.NoError(t, err)
+		assert.Equal(t, "Power is on", out)
+
+		for _, operation := range []func() (string, error){bmc.PowerOn, bmc.PowerOff, bmc.PowerCycle, bmc.PowerReset, bmc.PowerSoft} {
+			_, err := operation()
+			assert.NoError(t, err)
+		}
+		out, err = bmc.Result()
+		assert.NoError(t, err)
+		assert.Equal(t, "Power control: GracefulShutdown", out)
+		assert.Equal(t, []string{"On", "ForceOff", "PowerCycle", "ForceRestart", "GracefulShutdown"}, mock.resets)
+		if sessions {
+			assert.Equal(t, 6, mock.logins)
+			assert.Equal(t, mock.logins, mock.logouts)
+		} else {
+			assert.Equal(t, 0, mock.logins)
+		}
+	}
+}
+
+func Test_RedfishBootDev(t *testing.T) {
+	mock := &mockRedfish{sessions: true, powerState: "On"}
+	bmc, err := New(newMockRedfish(t, mock), false)
+	assert.NoError(t, err)
+
+	out, err := bmc.BootDev(BootPXE, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Boot device: Pxe (Once)", out)
+	assert.Equal(t, map[string]string{"BootSourceOverrideTarget": "Pxe", "BootSourceOverrideEnabled": "Once"}, mock.boot)
+
+	out, err = bmc.BootDev(BootBIOS, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Boot device: BiosSetup (Continuous)", out)
+	assert.Equal(t, map[string]string{
+		"BootSourceOverrideTarget":  "BiosSetup",
+		"BootSourceOverrideEnabled": "Continuous",
+		"BootSourceOverrideMode":    "UEFI"}, mock.boot)
+
+	_, err = bmc.BootDev("floppy", false, false)
+	assert.Error(t, err)
+}
+
+func Test_RedfishSensorList(t *testing.T) {
+	mock := &mockRedfish{sessions: true, powerState: "Off"}
+	bmc, err := New(newMockRedfish(t, mock), false)
+	assert.NoError(t, err)
+	out, err := bmc.SensorList()
+	assert.NoError(t, err)
+	assert.Equal(t, `CPU1 Temp                | 45         | degrees C  | ok
+Fan1                     | 4200       | RPM        | ns
+12V                      | 12.1       | Volts      | warning
+System Power             | 230        | Watts      | ok`, out)
+}
+
+func Test_RedfishErrors(t *testing.T) {
+	mock := &mockRedfish{sessions: true, powerState: "On"}
+	conf := newMockRedfish(t, mock)
+
+	wrongPassword := conf
+	wrongPassword.Password = "wrong"
+	bmc, err := New(wrongPassword, false)
+	assert.NoError(t, err)
+	_, err = bmc.PowerOn()
+	assert.ErrorContains(t, err, "invalid credentials")
+	assert.Empty(t, mock.resets)
+
+	verified := conf
+	verified.Insecure = ""
+	bmc, err = New(verified, false)
+	assert.NoError(t, err)
+	_, err = bmc.PowerOn()
+	assert.ErrorContains(t, err, "certificate")
+
+	_, err = New(node.IpmiConf{Backend: "unknown"}, false)
+	assert.Error(t, err)
+}
+
+func Test_RedfishShowOnly(t *testing.T) {
+	bmc, err := New(node.IpmiConf{Ipaddr: net.ParseIP("10.10.10.10"), Backend: BackendRedfish}, true)
+	assert.NoError(t, err)
+	out, err := bmc.PowerOn()
+	assert.NoError(t, err)
+	assert.Equal(t, `POST https://10.10.10.10:443/redfish/v1/Systems/<system>/Actions/ComputerSystem.Reset {"ResetType":"On"}`, out)
+	out, err = bmc.BootDev(BootDisk, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, `PATCH https://10.10.10.10:443/redfish/v1/Systems/<system> {"Boot":{"BootSourceOverrideEnabled":"Continuous","BootSourceOverrideTarget":"Hdd"}}`, out)
+}
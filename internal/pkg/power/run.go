@@ -0,0 +1,259 @@
+package power
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/warewulf/warewulf/internal/pkg/batch"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/util"
+)
+
+// Normalized power states of a Result
+const (
+	StateOn      = "on"
+	StateOff     = "off"
+	StateUnknown = "unknown"
+	StateError   = "error"
+	// StateOK is the state of operations which don't change the power
+	StateOK = "ok"
+)
+
+// retryDelay is the time to wait before an operation is retried
+var retryDelay = time.Second
+
+// powerStateRE matches the power state in the output of ipmitool
+// ("Chassis Power is on") and of the Redfish backend ("Power is on")
+var powerStateRE = regexp.MustCompile(`(?i)\bpower is (on|off)\b`)
+
+/*
+Operation is an operation which can be run on many nodes with Run.
+State is the state a node is expected to be in after the operation
+succeeded, unless Parse is set to read the state from the output of the
+operation.
+*/
+type Operation struct {
+	Call  func(BMC) (string, error)
+	State string
+	Parse func(out string) (state, reason string)
+}
+
+// ChangesPower returns true if the operation changes the power state
+func (operation Operation) ChangesPower() bool {
+	return operation.State == StateOn || operation.State == StateOff
+}
+
+// The power operations of wwctl power
+var (
+	OperationOn     = Operation{Call: BMC.PowerOn, State: StateOn}
+	OperationOff    = Operation{Call: BMC.PowerOff, State: StateOff}
+	OperationCycle  = Operation{Call: BMC.PowerCycle, State: StateOn}
+	OperationReset  = Operation{Call: BMC.PowerReset, State: StateOn}
+	OperationSoft   = Operation{Call: BMC.PowerSoft, State: StateOff}
+	OperationStatus = Operation{Call: BMC.PowerStatus, Parse: ParseState}
+)
+
+// OperationBootDev returns the operation to set the boot device, see
+// BMC.BootDev
+func OperationBootDev(device string, persistent, uefi bool) (Operation, error) {
+	if !util.InSlice(BootDevices, device) {
+		return Operation{}, fmt.Errorf("invalid boot device %s, must be one of %s", device, strings.Join(BootDevices, ", "))
+	}
+	return Operation{
+		Call: func(bmc BMC) (string, error) {
+			return bmc.BootDev(device, persistent, uefi)
+		},
+		State: StateOK,
+	}, nil
+}
+
+// Options of Run
+type Options struct {
+	// Fanout is the number of BMCs which are accessed in parallel
+	Fanout int
+	// Timeout is the time a single call to a BMC may take, no limit
+	// if zero
+	Timeout time.Duration
+	// Retries is the number of times a failed call is repeated
+	Retries int
+	// ShowOnly only returns what would be done
+	ShowOnly bool
+}
+
+// Result is the normalized result of a power operation on a node
+type Result struct {
+	Node     string `json:"node"`
+	Address  string `json:"address,omitempty"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Attempts int    `json:"attempts"`
+}
+
+// Failed returns true if the operation failed on the node
+func (result Result) Failed() bool {
+	return result.State == StateError
+}
+
+/*
+Run runs the operation on the BMCs of the given nodes, with at most
+opts.Fanout calls at the same time. Every call is limited to
+opts.Timeout and failed calls are retried opts.Retries times, so that a
+single unresponsive BMC doesn't stall the others. The results are
+returned in the order of the nodes.
+*/
+func Run(nodes []node.Node, operation Operation, opts Options) []Result {
+	results := make([]Result, len(nodes))
+	fanout := opts.Fanout
+	if fanout < 1 {
+		fanout = 1
+	}
+	batchpool := batch.New(fanout)
+	for i := range nodes {
+		result := &results[i]
+		conf, ok := prepare(result, nodes[i], opts)
+		if !ok {
+			continue
+		}
+		batchpool.Submit(func() {
+			runNode(result, conf, operation, opts)
+		})
+	}
+	batchpool.Run()
+	return results
+}
+
+// prepare initializes the result of the node and returns its BMC
+// configuration, or false if the result already failed because the BMC
+// isn't configured
+func prepare(result *Result, n node.Node, opts Options) (node.IpmiConf, bool) {
+	result.Node = n.Id()
+	conf := node.IpmiConf{}
+	if n.Ipmi != nil {
+		conf = *n.Ipmi
+	}
+	if conf.Ipaddr == nil || conf.Ipaddr.IsUnspecified() {
+		result.State = StateError
+		result.Reason = "no IPMI IP address"
+		return conf, false
+	}
+	result.Address = conf.Ipaddr.String()
+	if _, err := New(conf, opts.ShowOnly); err != nil {
+		result.State = StateError
+		result.Reason = err.Error()
+		return conf, false
+	}
+	return conf, true
+}
+
+// runNode runs the operation on a single BMC, with retries
+func runNode(result *Result, conf node.IpmiConf, operation Operation, opts Options) {
+	var out string
+	var err error
+	for result.Attempts = 1; ; result.Attempts++ {
+		out, err = call(conf, operation, opts)
+		if err == nil || result.Attempts > opts.Retries {
+			break
+		}
+		time.Sleep(retryDelay)
+	}
+	result.Output = out
+	switch {
+	case err != nil:
+		result.State = StateError
+		result.Reason = err.Error()
+		if out != "" {
+			result.Reason = out
+		}
+	case opts.ShowOnly:
+		result.State = StateUnknown
+	case operation.Parse != nil:
+		result.State, result.Reason = operation.Parse(out)
+	default:
+		result.State = operation.State
+	}
+}
+
+/*
+call runs the operation on a new BMC client, so that a call which timed
+out can't interfere with its retry. The backends stop their own work at
+the timeout as well.
+*/
+func call(conf node.IpmiConf, operation Operation, opts Options) (string, error) {
+	bmc, err := New(conf, opts.ShowOnly)
+	if err != nil {
+		return "", err
+	}
+	if opts.Timeout <= 0 {
+		return operation.Call(bmc)
+	}
+	bmc.SetTimeout(opts.Timeout)
+	type reply struct {
+		out string
+		err error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		out, err := operation.Call(bmc)
+		done <- reply{out, err}
+	}()
+	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.out, r.err
+	case <-timer.C:
+		return "", fmt.Errorf("timed out after %s", opts.Timeout)
+	}
+}
+
+// ParseState returns the normalized power state in the output of a
+// power status call, or unknown and the reason
+func ParseState(out string) (state, reason string) {
+	match := powerStateRE.FindStringSubmatch(out)
+	if match == nil {
+		if out == "" {
+			return StateUnknown, "no power state reported"
+		}
+		return StateUnknown, "unrecognized power state: " + out
+	}
+	return strings.ToLower(match[1]), ""
+}
+
+/*
+RunError is returned for results with failed nodes. Its exit code tells
+a partial failure (1) from the failure of all nodes (255).
+*/
+type RunError struct {
+	Failed int
+	Total  int
+}
+
+func (err *RunError) Error() string {
+	return fmt.Sprintf("power operation failed on %d of %d nodes", err.Failed, err.Total)
+}
+
+// ExitCode returns 1 if only some of the nodes failed, 255 otherwise
+func (err *RunError) ExitCode() int {
+	if err.Failed < err.Total {
+		return 1
+	}
+	return 255
+}
+
+// Err returns a RunError if the operation failed on any node, nil
+// otherwise
+func Err(results []Result) error {
+	failed := 0
+	for _, result := range results {
+		if result.Failed() {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return &RunError{Failed: failed, Total: len(results)}
+}
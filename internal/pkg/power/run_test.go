@@ -0,0 +1,92 @@
+package power
+
+import (
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+// newTestNode returns a node with the given BMC configuration
+func newTestNode(id string, conf node.IpmiConf) node.Node {
+	n := node.NewNode(id)
+	n.Ipmi = &conf
+	return n
+}
+
+func Test_Run(t *testing.T) {
+	retryDelay = 0
+	on := newMockRedfish(t, &mockRedfish{sessions: true, powerState: "On"})
+	off := newMockRedfish(t, &mockRedfish{sessions: true, powerState: "Off"})
+	hung := newMockRedfish(t, &mockRedfish{sessions: true, powerState: "On", delay: 2 * time.Second})
+	flaky := newMockRedfish(t, &mockRedfish{sessions: true, powerState: "On", failures: 1})
+	nodes := []node.Node{
+		newTestNode("n1", on),
+		newTestNode("n2", off),
+		newTestNode("n3", hung),
+		newTestNode("n4", flaky),
+		newTestNode("n5", node.IpmiConf{}),
+		newTestNode("n6", node.IpmiConf{Ipaddr: net.ParseIP("10.0.0.6"), Backend: "unknown"}),
+	}
+
+	start := time.Now()
+	results := Run(nodes, OperationStatus, Options{Fanout: 10, Timeout: 500 * time.Millisecond, Retries: 1})
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Len(t, results, 6)
+	assert.Equal(t, Result{Node: "n1", Address: on.Ipaddr.String(), State: StateOn, Output: "Power is on", Attempts: 1}, results[0])
+	assert.Equal(t, StateOff, results[1].State)
+	assert.Equal(t, StateError, results[2].State)
+	assert.Equal(t, "timed out after 500ms", results[2].Reason)
+	assert.Equal(t, 2, results[2].Attempts)
+	assert.Equal(t, StateOn, results[3].State)
+	assert.Equal(t, 2, results[3].Attempts)
+	assert.Equal(t, Result{Node: "n5", State: StateError, Reason: "no IPMI IP address"}, results[4])
+	assert.Equal(t, "unknown BMC backend: unknown", results[5].Reason)
+
+	err := Err(results)
+	assert.EqualError(t, err, "power operation failed on 3 of 6 nodes")
+	assert.Equal(t, 1, err.(*RunError).ExitCode())
+	assert.Equal(t, 255, Err(results[2:3]).(*RunError).ExitCode())
+	assert.NoError(t, Err(results[:2]))
+
+	results = Run(nodes[:1], OperationOff, Options{Fanout: 1})
+	assert.Equal(t, StateOff, results[0].State)
+	assert.Equal(t, "Power control: ForceOff", results[0].Output)
+
+	results = Run(nodes[:1], OperationOn, Options{Fanout: 1, ShowOnly: true})
+	assert.Equal(t, StateUnknown, results[0].State)
+	assert.Contains(t, results[0].Output, "ComputerSystem.Reset")
+}
+
+func Test_RunIPMITimeout(t *testing.T) {
+	template := path.Join(t.TempDir(), "test.tmpl")
+	assert.NoError(t, os.WriteFile(template, []byte(`{{ if eq .Cmd "PowerStatus" }}echo Chassis Power is off{{ else }}sleep 10{{ end }}`), 0644))
+	nodes := []node.Node{newTestNode("n1", node.IpmiConf{Ipaddr: net.ParseIP("10.0.0.1"), Template: template})}
+
+	results := Run(nodes, OperationStatus, Options{Fanout: 1, Timeout: 5 * time.Second})
+	assert.Equal(t, StateOff, results[0].State)
+	assert.Equal(t, "Chassis Power is off", results[0].Output)
+
+	start := time.Now()
+	results = Run(nodes, OperationOn, Options{Fanout: 1, Timeout: 200 * time.Millisecond})
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, StateError, results[0].State)
+	assert.Equal(t, "timed out after 200ms", results[0].Reason)
+}
+
+func Test_ParseState(t *testing.T) {
+	for out, expected := range map[string][2]string{
+		"Chassis Power is on":  {StateOn, ""},
+		"Chassis Power is off": {StateOff, ""},
+		"Power is on":          {StateOn, ""},
+		"":                     {StateUnknown, "no power state reported"},
+		"Power is standby":     {StateUnknown, "unrecognized power state: Power is standby"},
+	} {
+		state, reason := ParseState(out)
+		assert.Equal(t, expected, [2]string{state, reason}, out)
+	}
+}
@@ -0,0 +1,65 @@
+package power
+
+import (
+	"sort"
+
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+/*
+Sequence describes how nodes are split into waves which are powered one
+after the other. Nodes are grouped by the value of GroupBy, a field
+given like the key of a node selector, e.g. tag.rack or cluster.
+*/
+type Sequence struct {
+	GroupBy string
+	// GroupSize is the maximum number of nodes of a group in a wave,
+	// no limit if zero
+	GroupSize int
+	// WaveSize is the maximum number of nodes in a wave, no limit if
+	// zero
+	WaveSize int
+}
+
+/*
+Waves splits the nodes into waves according to the sequence. The groups
+take turns in filling the waves, so that the nodes of every group are
+spread over the waves as evenly as the limits allow. Nodes which don't
+have a value for GroupBy form a group of their own.
+*/
+func Waves(nodes []node.Node, seq Sequence) (waves [][]node.Node) {
+	groups := make(map[string][]node.Node)
+	for _, n := range nodes {
+		key := ""
+		if seq.GroupBy != "" {
+			key = n.SelectorValue(seq.GroupBy)
+		}
+		groups[key] = append(groups[key], n)
+	}
+	var keys []string
+	for key := range groups {
+		keys = append(keys, key)
+		sort.Slice(groups[key], func(i, j int) bool { return groups[key][i].Id() < groups[key][j].Id() })
+	}
+	sort.Strings(keys)
+
+	remaining := len(nodes)
+	for start := 0; remaining > 0; start++ {
+		var wave []node.Node
+		for i := range keys {
+			key := keys[(start+i)%len(keys)]
+			take := len(groups[key])
+			if seq.GroupSize > 0 && take > seq.GroupSize {
+				take = seq.GroupSize
+			}
+			if seq.WaveSize > 0 && len(wave)+take > seq.WaveSize {
+				take = seq.WaveSize - len(wave)
+			}
+			wave = append(wave, groups[key][:take]...)
+			groups[key] = groups[key][take:]
+		}
+		remaining -= len(wave)
+		waves = append(waves, wave)
+	}
+	return waves
+}
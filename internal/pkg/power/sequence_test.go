@@ -0,0 +1,67 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+)
+
+func Test_Waves(t *testing.T) {
+	registry, err := node.Parse([]byte(`
+nodes:
+  a1:
+    tags:
+      rack: a
+  a2:
+    tags:
+      rack: a
+  a3:
+    tags:
+      rack: a
+  b1:
+    tags:
+      rack: b
+  b2:
+    tags:
+      rack: b
+  c1: {}`))
+	assert.NoError(t, err)
+	nodes, err := registry.FindAllNodes()
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		seq   Sequence
+		waves [][]string
+	}{
+		"no limits": {
+			seq:   Sequence{},
+			waves: [][]string{{"a1", "a2", "a3", "b1", "b2", "c1"}},
+		},
+		"wave size": {
+			seq:   Sequence{WaveSize: 4},
+			waves: [][]string{{"a1", "a2", "a3", "b1"}, {"b2", "c1"}},
+		},
+		"group size": {
+			seq:   Sequence{GroupBy: "tag.rack", GroupSize: 1},
+			waves: [][]string{{"c1", "a1", "b1"}, {"a2", "b2"}, {"a3"}},
+		},
+		"group and wave size": {
+			seq:   Sequence{GroupBy: "tag.rack", GroupSize: 2, WaveSize: 3},
+			waves: [][]string{{"c1", "a1", "a2"}, {"a3", "b1", "b2"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var waves [][]string
+			for _, wave := range Waves(nodes, tt.seq) {
+				var names []string
+				for _, n := range wave {
+					names = append(names, n.Id())
+				}
+				waves = append(waves, names)
+			}
+			assert.Equal(t, tt.waves, waves)
+		})
+	}
+}
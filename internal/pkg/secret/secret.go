@@ -0,0 +1,260 @@
+/*
+Package secret encrypts values like BMC passwords and munge keys which
+are stored in nodes.conf. An encrypted value is an ordinary string of
+the form
+
+	wwsecret:v1:<key id>:<base64 nonce and ciphertext>
+
+which is encrypted with AES-256-GCM by a key of the keyring on the
+server. Encrypted values are only decrypted where they are needed, like
+in the templates of the node itself and in the BMC backends, and are
+masked in listings.
+*/
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Prefix starts every encrypted value
+const Prefix = "wwsecret:v1:"
+
+// Mask replaces encrypted values in listings
+const Mask = "********"
+
+// keySize is the size of the AES-256 keys
+const keySize = 32
+
+var ErrNoKey = errors.New("no secret key")
+
+// IsSecret returns true if the value is encrypted
+func IsSecret(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// MaskValue returns Mask for an encrypted value and the value itself
+// otherwise
+func MaskValue(value string) string {
+	if IsSecret(value) {
+		return Mask
+	}
+	return value
+}
+
+type key struct {
+	id   string
+	data []byte
+}
+
+/*
+Keyring holds the keys which decrypt the secrets. New values are
+encrypted with the newest key, older keys are kept while the secrets
+are encrypted again after a key rotation.
+*/
+type Keyring struct {
+	keys []key
+}
+
+/*
+LoadKeyring reads the keyring from the given file, which holds one key
+per line as its ID and the base64 encoded key, the newest key last.
+ErrNoKey is returned if the file doesn't exist.
+*/
+func LoadKeyring(file string) (*Keyring, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoKey, file)
+	} else if err != nil {
+		return nil, err
+	}
+	keyring := &Keyring{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: invalid key", file, i+1)
+		}
+		k, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(k) != keySize {
+			return nil, fmt.Errorf("%s:%d: invalid key", file, i+1)
+		}
+		keyring.keys = append(keyring.keys, key{id: fields[0], data: k})
+	}
+	if len(keyring.keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoKey, file)
+	}
+	return keyring, nil
+}
+
+/*
+Save writes the keyring to the given file, readable only by its owner.
+The file is replaced atomically, so that a keyring is never lost half
+written.
+*/
+func (keyring *Keyring) Save(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	var content strings.Builder
+	content.WriteString("# Warewulf secret keys, the newest key last. Losing this file loses all secrets.\n")
+	for _, k := range keyring.keys {
+		fmt.Fprintf(&content, "%s %s\n", k.id, base64.StdEncoding.EncodeToString(k.data))
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// AddKey adds a new random key, which encrypts all following values
+func (keyring *Keyring) AddKey() error {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	data := make([]byte, keySize)
+	if _, err := rand.Read(data); err != nil {
+		return err
+	}
+	keyring.keys = append(keyring.keys, key{id: hex.EncodeToString(id), data: data})
+	return nil
+}
+
+/*
+Prune removes all keys but the newest one which don't encrypt a value in
+any of the given contents, e.g. earlier revisions of nodes.conf, and
+returns the IDs of the removed keys.
+*/
+func (keyring *Keyring) Prune(contents ...string) (removed []string) {
+	if len(keyring.keys) == 0 {
+		return nil
+	}
+	var kept []key
+	for _, k := range keyring.keys[:len(keyring.keys)-1] {
+		used := false
+		for _, content := range contents {
+			if strings.Contains(content, Prefix+k.id+":") {
+				used = true
+				break
+			}
+		}
+		if used {
+			kept = append(kept, k)
+		} else {
+			removed = append(removed, k.id)
+		}
+	}
+	keyring.keys = append(kept, keyring.keys[len(keyring.keys)-1])
+	return removed
+}
+
+// Current returns the ID of the key which encrypts new values
+func (keyring *Keyring) Current() string {
+	if len(keyring.keys) == 0 {
+		return ""
+	}
+	return keyring.keys[len(keyring.keys)-1].id
+}
+
+// aead returns the cipher of a key
+func (k key) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.data)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypts the value with the newest key. Encrypted values are
+// returned unchanged.
+func (keyring *Keyring) Encrypt(value string) (string, error) {
+	if IsSecret(value) {
+		return value, nil
+	}
+	if len(keyring.keys) == 0 {
+		return "", ErrNoKey
+	}
+	k := keyring.keys[len(keyring.keys)-1]
+	aead, err := k.aead()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := aead.Seal(nonce, nonce, []byte(value), []byte(k.id))
+	return Prefix + k.id + ":" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts an encrypted value. Other values are returned
+// unchanged.
+func (keyring *Keyring) Decrypt(value string) (string, error) {
+	if !IsSecret(value) {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, Prefix), ":")
+	if !ok {
+		return "", fmt.Errorf("invalid secret")
+	}
+	for _, k := range keyring.keys {
+		if k.id != id {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("invalid secret: %w", err)
+		}
+		aead, err := k.aead()
+		if err != nil {
+			return "", err
+		}
+		if len(data) < aead.NonceSize() {
+			return "", fmt.Errorf("invalid secret: too short")
+		}
+		plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(id))
+		if err != nil {
+			return "", fmt.Errorf("could not decrypt secret: %w", err)
+		}
+		return string(plain), nil
+	}
+	return "", fmt.Errorf("%w: secret is encrypted with the unknown key %s", ErrNoKey, id)
+}
+
+// Reencrypt encrypts a value again with the newest key, if it is
+// encrypted with another key
+func (keyring *Keyring) Reencrypt(value string) (string, error) {
+	if !IsSecret(value) || strings.HasPrefix(value, Prefix+keyring.Current()+":") {
+		return value, nil
+	}
+	plain, err := keyring.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return keyring.Encrypt(plain)
+}
@@ -0,0 +1,121 @@
+package secret
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warewulf/warewulf/internal/pkg/node"
+	"github.com/warewulf/warewulf/internal/pkg/testenv"
+)
+
+func newKeyring(t *testing.T) *Keyring {
+	keyring := &Keyring{}
+	require.NoError(t, keyring.AddKey())
+	return keyring
+}
+
+func Test_Encrypt(t *testing.T) {
+	keyring := newKeyring(t)
+	encrypted, err := keyring.Encrypt("munge key")
+	require.NoError(t, err)
+	assert.True(t, IsSecret(encrypted))
+	assert.True(t, strings.HasPrefix(encrypted, Prefix+keyring.Current()+":"))
+	assert.NotContains(t, encrypted, "munge")
+	again, err := keyring.Encrypt("munge key")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "random nonce")
+	unchanged, err := keyring.Encrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, encrypted, unchanged)
+
+	plain, err := keyring.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "munge key", plain)
+	plain, err = keyring.Decrypt("clear")
+	require.NoError(t, err)
+	assert.Equal(t, "clear", plain)
+
+	_, err = keyring.Decrypt(encrypted[:len(encrypted)-4] + "AAA=")
+	assert.ErrorContains(t, err, "could not decrypt")
+	_, err = newKeyring(t).Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrNoKey)
+
+	assert.Equal(t, Mask, MaskValue(encrypted))
+	assert.Equal(t, "clear", MaskValue("clear"))
+}
+
+func Test_Keyring(t *testing.T) {
+	file := path.Join(t.TempDir(), "warewulf", "secret.key")
+	_, err := LoadKeyring(file)
+	assert.ErrorIs(t, err, ErrNoKey)
+
+	keyring := newKeyring(t)
+	encrypted, err := keyring.Encrypt("secret")
+	require.NoError(t, err)
+	require.NoError(t, keyring.Save(file))
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := LoadKeyring(file)
+	require.NoError(t, err)
+	old := loaded.Current()
+	require.NoError(t, loaded.AddKey())
+	assert.NotEqual(t, old, loaded.Current())
+	reencrypted, err := loaded.Reencrypt(encrypted)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, Prefix+loaded.Current()+":"))
+	assert.Empty(t, loaded.Prune("old: "+encrypted), "the old key is still used")
+	plain, err := loaded.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", plain)
+	assert.Equal(t, []string{old}, loaded.Prune(reencrypted))
+	_, err = loaded.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrNoKey)
+	plain, err = loaded.Decrypt(reencrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", plain)
+
+	require.NoError(t, os.WriteFile(file, []byte("abc notakey\n"), 0o600))
+	_, err = LoadKeyring(file)
+	assert.ErrorContains(t, err, "invalid key")
+}
+
+func Test_Reveal(t *testing.T) {
+	env := testenv.New(t)
+	defer env.RemoveAll()
+	keyring := newKeyring(t)
+	require.NoError(t, keyring.Save(KeyFile()))
+	password, err := keyring.Encrypt("admin")
+	require.NoError(t, err)
+	munge, err := keyring.Encrypt("munge key")
+	require.NoError(t, err)
+
+	n := node.NewNode("n1")
+	n.Ipmi = &node.IpmiConf{Password: password}
+	n.Tags = map[string]string{"munge": munge, "rack": "r1"}
+	n.NetDevs = map[string]*node.NetDev{"default": {Tags: map[string]string{"key": munge}}}
+	masked := n
+	MaskAll(&masked)
+	assert.Equal(t, Mask, masked.Ipmi.Password)
+
+	n = node.NewNode("n1")
+	n.Ipmi = &node.IpmiConf{Password: password}
+	n.Tags = map[string]string{"munge": munge, "rack": "r1"}
+	n.NetDevs = map[string]*node.NetDev{"default": {Tags: map[string]string{"key": munge}}}
+	require.NoError(t, Reveal(&n))
+	assert.Equal(t, "admin", n.Ipmi.Password)
+	assert.Equal(t, map[string]string{"munge": "munge key", "rack": "r1"}, n.Tags)
+	assert.Equal(t, "munge key", n.NetDevs["default"].Tags["key"])
+
+	require.NoError(t, os.Remove(KeyFile()))
+	clear := node.NewNode("n2")
+	clear.Tags = map[string]string{"rack": "r1"}
+	assert.NoError(t, Reveal(&clear), "no key needed without secrets")
+	n.Tags["munge"] = munge
+	assert.ErrorIs(t, Reveal(&n), ErrNoKey)
+}
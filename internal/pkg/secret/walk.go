@@ -0,0 +1,102 @@
+package secret
+
+import (
+	"reflect"
+
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
+)
+
+// KeyFile returns the file of the keyring of the server
+func KeyFile() string {
+	return warewulfconf.Get().Paths.SecretKey()
+}
+
+/*
+Walk replaces every encrypted value in the exported fields, maps and
+slices of v, which must be a pointer, by the result of fn.
+*/
+func Walk(v interface{}, fn func(value string) (string, error)) error {
+	return walk(reflect.ValueOf(v), fn)
+}
+
+func walk(value reflect.Value, fn func(string) (string, error)) error {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return walk(value.Elem(), fn)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := walk(value.Field(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := walk(value.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			element := iter.Value()
+			if element.Kind() == reflect.Pointer || element.Kind() == reflect.Interface {
+				if err := walk(element, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			// map elements aren't addressable, so a copy is changed
+			// and stored again
+			changed := reflect.New(element.Type()).Elem()
+			changed.Set(element)
+			if err := walk(changed, fn); err != nil {
+				return err
+			}
+			if !changed.Equal(element) {
+				value.SetMapIndex(iter.Key(), changed)
+			}
+		}
+	case reflect.String:
+		if !IsSecret(value.String()) || !value.CanSet() {
+			return nil
+		}
+		str, err := fn(value.String())
+		if err != nil {
+			return err
+		}
+		value.SetString(str)
+	}
+	return nil
+}
+
+/*
+Reveal decrypts all encrypted values in v, which must be a pointer,
+with the keyring of the server. The keyring is only read if there are
+encrypted values. Like Walk it changes maps and pointed to values in
+place, so v must not share them with values which must stay encrypted.
+*/
+func Reveal(v interface{}) error {
+	var keyring *Keyring
+	return Walk(v, func(value string) (str string, err error) {
+		if keyring == nil {
+			if keyring, err = LoadKeyring(KeyFile()); err != nil {
+				return "", err
+			}
+		}
+		return keyring.Decrypt(value)
+	})
+}
+
+// MaskAll replaces all encrypted values in v, which must be a pointer,
+// with Mask
+func MaskAll(v interface{}) {
+	_ = Walk(v, func(string) (string, error) {
+		return Mask, nil
+	})
+}
@@ -0,0 +1,130 @@
+package sensor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// historyFile returns the file in which the readings of the node are
+// stored, one JSON document per line
+func historyFile(historyDir, node string) string {
+	return path.Join(historyDir, node+".json")
+}
+
+// lockFile returns the file which is locked while the history of the
+// node is updated. The history file itself is replaced on every update,
+// so it can't carry the lock.
+func lockFile(historyDir, node string) string {
+	return path.Join(historyDir, "."+node+".lock")
+}
+
+/*
+Record adds the readings to the history below historyDir. Readings
+older than retention are removed at the same time, unless retention is
+zero.
+*/
+func Record(historyDir string, readings []Reading, retention time.Duration) error {
+	if err := os.MkdirAll(historyDir, 0o750); err != nil {
+		return err
+	}
+	byNode := make(map[string][]Reading)
+	for _, reading := range readings {
+		byNode[reading.Node] = append(byNode[reading.Node], reading)
+	}
+	for node, added := range byNode {
+		var since time.Time
+		if retention > 0 {
+			since = time.Now().Add(-retention)
+		}
+		if err := recordNode(historyDir, node, added, since); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordNode adds the readings to the history of the node and drops the
+// readings before since. The history is locked, so that concurrent
+// updates don't lose readings.
+func recordNode(historyDir, node string, added []Reading, since time.Time) error {
+	lock, err := os.OpenFile(lockFile(historyDir, node), os.O_RDWR|os.O_CREATE, 0o640)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("could not lock history of %s: %w", node, err)
+	}
+	defer func() {
+		_ = unix.Flock(int(lock.Fd()), unix.LOCK_UN)
+	}()
+	kept, err := History(historyDir, node, since)
+	if err != nil {
+		return err
+	}
+	return writeHistory(historyDir, node, append(kept, added...))
+}
+
+// writeHistory replaces the history of the node with the readings
+func writeHistory(historyDir, node string, readings []Reading) error {
+	file := historyFile(historyDir, node)
+	tmp, err := os.CreateTemp(historyDir, "."+node+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, reading := range readings {
+		if err := encoder.Encode(reading); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+/*
+History returns the recorded readings of the node since the given time,
+sorted by time. A node without history has no readings.
+*/
+func History(historyDir, node string, since time.Time) (readings []Reading, err error) {
+	file, err := os.Open(historyFile(historyDir, node))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var reading Reading
+		if err := json.Unmarshal(scanner.Bytes(), &reading); err != nil {
+			return nil, err
+		}
+		if !reading.Time.Before(since) {
+			readings = append(readings, reading)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Time.Before(readings[j].Time) })
+	return readings, nil
+}
@@ -0,0 +1,52 @@
+package sensor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_History(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	old := Reading{Node: "n1", Time: now.Add(-48 * time.Hour), Name: "CPU Temp", Value: float(40), Status: "ok"}
+	recent := Reading{Node: "n1", Time: now.Add(-30 * time.Minute), Name: "CPU Temp", Value: float(60), Status: "ok"}
+	current := Reading{Node: "n1", Time: now, Name: "CPU Temp", Value: float(85), Status: "ok"}
+	other := Reading{Node: "n2", Time: now, Name: "CPU Temp", Value: float(50), Status: "ok"}
+
+	assert.NoError(t, Record(dir, []Reading{old, recent}, 0))
+	assert.NoError(t, Record(dir, []Reading{current, other}, 24*time.Hour))
+
+	readings, err := History(dir, "n1", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []Reading{recent, current}, readings)
+
+	readings, err = History(dir, "n1", now.Add(-time.Hour).Add(31*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []Reading{current}, readings)
+
+	readings, err = History(dir, "n3", time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, readings)
+}
+
+func Test_HistoryConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reading := Reading{Node: "n1", Time: now.Add(time.Duration(i) * time.Second), Name: "CPU Temp", Value: float(float64(i)), Status: "ok"}
+			assert.NoError(t, Record(dir, []Reading{reading}, 0))
+		}(i)
+	}
+	wg.Wait()
+
+	readings, err := History(dir, "n1", time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, readings, 20)
+}
@@ -0,0 +1,188 @@
+// Package sensor parses the sensor readings of BMCs, evaluates them
+// against their thresholds and keeps a history of past readings.
+package sensor
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alarm levels of a reading
+const (
+	AlarmWarning  = "warning"
+	AlarmCritical = "critical"
+)
+
+// Thresholds of a sensor as reported by the BMC, nil if not set
+type Thresholds struct {
+	LowerNonRecoverable *float64 `json:"lnr,omitempty"`
+	LowerCritical       *float64 `json:"lcr,omitempty"`
+	LowerNonCritical    *float64 `json:"lnc,omitempty"`
+	UpperNonCritical    *float64 `json:"unc,omitempty"`
+	UpperCritical       *float64 `json:"ucr,omitempty"`
+	UpperNonRecoverable *float64 `json:"unr,omitempty"`
+}
+
+// empty returns true if no threshold is set
+func (thresholds Thresholds) empty() bool {
+	return thresholds == Thresholds{}
+}
+
+/*
+Reading is a single sensor reading of a node. Value is nil for sensors
+without a numeric reading, whose raw value is kept in Raw.
+*/
+type Reading struct {
+	Node       string      `json:"node"`
+	Time       time.Time   `json:"time"`
+	Name       string      `json:"name"`
+	Value      *float64    `json:"value,omitempty"`
+	Raw        string      `json:"raw,omitempty"`
+	Unit       string      `json:"unit,omitempty"`
+	Status     string      `json:"status"`
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+	Alarm      string      `json:"alarm,omitempty"`
+}
+
+// statusAlarms maps the status of ipmitool and Redfish health values to
+// alarm levels
+var statusAlarms = map[string]string{
+	"nc":       AlarmWarning,
+	"lnc":      AlarmWarning,
+	"unc":      AlarmWarning,
+	"warning":  AlarmWarning,
+	"cr":       AlarmCritical,
+	"lcr":      AlarmCritical,
+	"ucr":      AlarmCritical,
+	"nr":       AlarmCritical,
+	"lnr":      AlarmCritical,
+	"unr":      AlarmCritical,
+	"critical": AlarmCritical,
+}
+
+/*
+Parse parses the sensor output of a BMC into readings of the node, one
+line per sensor with the fields separated by |. The formats of
+ipmitool sdr list (name, reading, status), of the Redfish backend
+(name, value, unit, status) and of ipmitool sensor list (name, value,
+unit, status and the thresholds lnr, lcr, lnc, unc, ucr, unr) are
+understood. Lines in other formats are skipped.
+*/
+func Parse(node string, now time.Time, out string) (readings []Reading) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "|")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		reading := Reading{Node: node, Time: now, Name: fields[0]}
+		switch len(fields) {
+		case 3:
+			value, unit, _ := strings.Cut(fields[1], " ")
+			reading.setValue(value)
+			if reading.Value != nil {
+				reading.Unit = unit
+			} else {
+				reading.Raw = fields[1]
+			}
+			reading.Status = strings.ToLower(fields[2])
+		case 4, 10:
+			reading.setValue(fields[1])
+			reading.Unit = fields[2]
+			reading.Status = strings.ToLower(fields[3])
+			if len(fields) == 10 {
+				thresholds := Thresholds{
+					LowerNonRecoverable: parseValue(fields[4]),
+					LowerCritical:       parseValue(fields[5]),
+					LowerNonCritical:    parseValue(fields[6]),
+					UpperNonCritical:    parseValue(fields[7]),
+					UpperCritical:       parseValue(fields[8]),
+					UpperNonRecoverable: parseValue(fields[9]),
+				}
+				if !thresholds.empty() {
+					reading.Thresholds = &thresholds
+				}
+			}
+		default:
+			continue
+		}
+		if reading.Name == "" {
+			continue
+		}
+		reading.Evaluate()
+		readings = append(readings, reading)
+	}
+	return readings
+}
+
+// setValue sets the value of the reading, or its raw value if it isn't
+// numeric
+func (reading *Reading) setValue(value string) {
+	reading.Value = parseValue(value)
+	if reading.Value == nil && value != "na" && value != "" {
+		reading.Raw = value
+	}
+}
+
+// parseValue returns the number in value, or nil if it isn't one
+func parseValue(value string) *float64 {
+	if strings.HasPrefix(value, "0x") {
+		return nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+/*
+Evaluate sets and returns the alarm level of the reading: critical or
+warning if the status reported by the BMC says so, or if the value
+reached one of the thresholds; empty otherwise. Sensors which aren't
+present (status ns) never raise an alarm.
+*/
+func (reading *Reading) Evaluate() string {
+	reading.Alarm = ""
+	if reading.Status == "ns" {
+		return ""
+	}
+	reading.Alarm = statusAlarms[reading.Status]
+	if reading.Value == nil || reading.Thresholds == nil || reading.Alarm == AlarmCritical {
+		return reading.Alarm
+	}
+	value := *reading.Value
+	lower := func(threshold *float64) bool { return threshold != nil && value <= *threshold }
+	upper := func(threshold *float64) bool { return threshold != nil && value >= *threshold }
+	thresholds := reading.Thresholds
+	switch {
+	case lower(thresholds.LowerNonRecoverable), lower(thresholds.LowerCritical),
+		upper(thresholds.UpperCritical), upper(thresholds.UpperNonRecoverable):
+		reading.Alarm = AlarmCritical
+	case lower(thresholds.LowerNonCritical), upper(thresholds.UpperNonCritical):
+		reading.Alarm = AlarmWarning
+	}
+	return reading.Alarm
+}
+
+// Alarms returns the readings which raise an alarm
+func Alarms(readings []Reading) (alarms []Reading) {
+	for _, reading := range readings {
+		if reading.Alarm != "" {
+			alarms = append(alarms, reading)
+		}
+	}
+	return alarms
+}
+
+// FormatValue returns the value of the reading as text, its raw value
+// or na
+func (reading Reading) FormatValue() string {
+	if reading.Value != nil {
+		return strconv.FormatFloat(*reading.Value, 'f', -1, 64)
+	}
+	if reading.Raw != "" {
+		return reading.Raw
+	}
+	return "na"
+}
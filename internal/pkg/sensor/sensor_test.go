@@ -0,0 +1,81 @@
+package sensor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func Test_Parse(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+	tests := map[string]struct {
+		out      string
+		readings []Reading
+	}{
+		"sdr list": {
+			out: `CPU Temp         | 45 degrees C      | ok
+Fan1             | no reading        | ns
+PS Status        | 0x01              | cr`,
+			readings: []Reading{
+				{Node: "n1", Time: now, Name: "CPU Temp", Value: float(45), Unit: "degrees C", Status: "ok"},
+				{Node: "n1", Time: now, Name: "Fan1", Raw: "no reading", Status: "ns"},
+				{Node: "n1", Time: now, Name: "PS Status", Raw: "0x01", Status: "cr", Alarm: AlarmCritical},
+			},
+		},
+		"sensor list": {
+			out: `CPU Temp         | 92.000     | degrees C  | ok    | na        | 5.000     | 10.000    | 90.000    | 95.000    | 100.000
+Fan1             | 600.000    | RPM        | ok    | na        | 300.000   | 500.000   | na        | na        | na
+Fan2             | na         | RPM        | ns    | na        | 300.000   | 500.000   | na        | na        | na
+Intrusion        | 0x0        | discrete   | 0x0080| na        | na        | na        | na        | na        | na`,
+			readings: []Reading{
+				{Node: "n1", Time: now, Name: "CPU Temp", Value: float(92), Unit: "degrees C", Status: "ok",
+					Thresholds: &Thresholds{LowerCritical: float(5), LowerNonCritical: float(10), UpperNonCritical: float(90), UpperCritical: float(95), UpperNonRecoverable: float(100)},
+					Alarm:      AlarmWarning},
+				{Node: "n1", Time: now, Name: "Fan1", Value: float(600), Unit: "RPM", Status: "ok",
+					Thresholds: &Thresholds{LowerCritical: float(300), LowerNonCritical: float(500)}},
+				{Node: "n1", Time: now, Name: "Fan2", Unit: "RPM", Status: "ns",
+					Thresholds: &Thresholds{LowerCritical: float(300), LowerNonCritical: float(500)}},
+				{Node: "n1", Time: now, Name: "Intrusion", Raw: "0x0", Unit: "discrete", Status: "0x0080"},
+			},
+		},
+		"redfish": {
+			out: `CPU1 Temp                | 45         | degrees C  | ok
+12V                      | 12.1       | Volts      | warning`,
+			readings: []Reading{
+				{Node: "n1", Time: now, Name: "CPU1 Temp", Value: float(45), Unit: "degrees C", Status: "ok"},
+				{Node: "n1", Time: now, Name: "12V", Value: float(12.1), Unit: "Volts", Status: "warning", Alarm: AlarmWarning},
+			},
+		},
+		"other output": {
+			out:      "Error: Unable to establish IPMI v2 / RMCP+ session",
+			readings: nil,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.readings, Parse("n1", now, tt.out))
+		})
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	thresholds := &Thresholds{LowerCritical: float(5), LowerNonCritical: float(10), UpperNonCritical: float(80), UpperCritical: float(90)}
+	for value, alarm := range map[float64]string{
+		50: "",
+		80: AlarmWarning,
+		91: AlarmCritical,
+		10: AlarmWarning,
+		2:  AlarmCritical,
+	} {
+		reading := Reading{Value: float(value), Status: "ok", Thresholds: thresholds}
+		assert.Equal(t, alarm, reading.Evaluate(), value)
+	}
+	reading := Reading{Value: float(50), Status: "ucr", Thresholds: thresholds}
+	assert.Equal(t, AlarmCritical, reading.Evaluate())
+	assert.Len(t, Alarms([]Reading{reading, {Status: "ok"}}), 1)
+}
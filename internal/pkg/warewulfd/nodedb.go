@@ -85,8 +85,17 @@ func GetNodeOrSetDiscoverable(hwaddr string) (node.Node, error) {
 	if err != nil {
 		return node, err
 	}
+	err = db.yml.AllocateAddresses(node.Id())
+	if err != nil {
+		return node, fmt.Errorf("%s (failed to allocate addresses) %w", hwaddr, err)
+	}
 	err = db.yml.Persist()
 	if err != nil {
+		// nodes.conf may have been changed by someone else, discard the
+		// cached database so that the next request works on the current version
+		if reloadErr := loadNodeDB(); reloadErr != nil {
+			wwlog.Error("%s (failed to reload configuration) %s", hwaddr, reloadErr)
+		}
 		return node, fmt.Errorf("%s (failed to persist node configuration) %w", hwaddr, err)
 	}
 	err = loadNodeDB()
@@ -29,6 +29,7 @@ type templateVars struct {
 	Id            string
 	Cluster       string
 	ContainerName string
+	ImageFormat   string
 	Hwaddr        string
 	Ipaddr        string
 	Port          string
@@ -88,7 +89,26 @@ func ProvisionSend(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if !remoteNode.Valid() {
+	if remoteNode.Valid() && !remoteNode.Bootable() {
+		state := remoteNode.LifecycleState()
+		wwlog.Denied("node %s is %s, refusing to provision: %s", remoteNode.Id(), state, remoteNode.StateReason)
+		stage_file = path.Join(conf.Paths.Sysconfdir, "warewulf/ipxe/hold.ipxe")
+		if rinfo.stage != "ipxe" || !util.IsFile(stage_file) {
+			w.WriteHeader(http.StatusForbidden)
+			updateStatus(remoteNode.Id(), status_stage, strings.ToUpper(state), rinfo.ipaddr)
+			return
+		}
+		message := fmt.Sprintf("This node is %s", state)
+		if remoteNode.StateReason != "" {
+			message += ": " + remoteNode.StateReason
+		}
+		tmpl_data = &templateVars{
+			Id:       remoteNode.Id(),
+			Hostname: remoteNode.Id(),
+			Hwaddr:   rinfo.hwaddr,
+			Message:  message,
+			WaitTime: "300"}
+	} else if !remoteNode.Valid() {
 		wwlog.Error("%s (unknown/unconfigured node)", rinfo.hwaddr)
 		if rinfo.stage == "ipxe" {
 			stage_file = path.Join(conf.Paths.Sysconfdir, "/warewulf/ipxe/unconfigured.ipxe")
@@ -107,6 +127,7 @@ func ProvisionSend(w http.ResponseWriter, req *http.Request) {
 			Hostname:      remoteNode.Id(),
 			Hwaddr:        rinfo.hwaddr,
 			ContainerName: remoteNode.ContainerName,
+			ImageFormat:   container.Format(remoteNode.ContainerName),
 			KernelArgs:    remoteNode.Kernel.Args,
 			KernelVersion: remoteNode.Kernel.Version,
 			NetDevs:       remoteNode.NetDevs,
@@ -124,7 +145,7 @@ func ProvisionSend(w http.ResponseWriter, req *http.Request) {
 
 	} else if rinfo.stage == "container" {
 		if remoteNode.ContainerName != "" {
-			stage_file = container.ImageFile(remoteNode.ContainerName)
+			stage_file = container.FormatImageFile(remoteNode.ContainerName, container.Format(remoteNode.ContainerName))
 		} else {
 			wwlog.Warn("No container set for node %s", remoteNode.Id())
 		}
@@ -183,6 +204,7 @@ func ProvisionSend(w http.ResponseWriter, req *http.Request) {
 				Hostname:      remoteNode.Id(),
 				Hwaddr:        rinfo.hwaddr,
 				ContainerName: remoteNode.ContainerName,
+				ImageFormat:   container.Format(remoteNode.ContainerName),
 				KernelArgs:    remoteNode.Kernel.Args,
 				KernelVersion: remoteNode.Kernel.Version,
 				NetDevs:       remoteNode.NetDevs,
@@ -32,6 +32,14 @@ var provisionSendTests = []struct {
 	{"find initramfs", "/provision/00:00:00:ff:ff:ff?stage=initramfs", "", 200, "10.10.10.10:9873"},
 	{"ipxe test with NetDevs and KernelVersion", "/provision/00:00:00:00:00:ff?stage=ipxe", "1.1.1 ifname=net:00:00:00:00:00:ff ", 200, "10.10.10.12:9873"},
 	{"find grub.cfg", "/efiboot/grub.cfg", "dracut", 200, "10.10.10.11:9873"},
+	{"hold retired node", "/provision/00:00:00:00:00:aa?stage=ipxe", "This node is retired: decommissioned", 200, "10.10.10.13:9873"},
+	{"refuse kernel to retired node", "/provision/00:00:00:00:00:aa?stage=kernel", "", 403, "10.10.10.13:9873"},
+	{"refuse system overlay to quarantined node", "/overlay-system/00:00:00:00:00:bb", "", 403, "10.10.10.14:9873"},
+	{"boot node in maintenance", "/provision/00:00:00:00:00:cc?stage=ipxe", "1.1.1", 200, "10.10.10.15:9873"},
+	{"cpio container image", "/provision/00:00:00:ff:ff:ff?stage=container&compress=gz", "cpio image", 200, "10.10.10.10:9873"},
+	{"squashfs container image", "/provision/00:00:00:00:00:dd?stage=container", "squashfs image", 200, "10.10.10.16:9873"},
+	{"no compressed squashfs image", "/provision/00:00:00:00:00:dd?stage=container&compress=gz", "", 404, "10.10.10.16:9873"},
+	{"ipxe with image format", "/provision/00:00:00:00:00:dd?stage=ipxe", "wwinit.format=squashfs", 200, "10.10.10.16:9873"},
 }
 
 func Test_ProvisionSend(t *testing.T) {
@@ -62,13 +70,44 @@ nodes:
         device: net
     ipxe template: test
     kernel:
-      version: 1.1.1`)
+      version: 1.1.1
+  n4:
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:aa
+    state: retired
+    state reason: decommissioned
+  n5:
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:bb
+    profiles:
+    - default
+    state: quarantined
+  n6:
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:cc
+    ipxe template: test
+    kernel:
+      version: 1.1.1
+    state: maintenance
+  n7:
+    network devices:
+      default:
+        hwaddr: 00:00:00:00:00:dd
+    container name: squash
+    ipxe template: format`)
 
 	// create a  arp file as for grub we look up the ip address through the arp cache
 	env.WriteFile("/var/tmp/arpcache", `IP address       HW type     Flags       HW address            Mask     Device
 10.10.10.10    0x1         0x2         00:00:00:ff:ff:ff     *        dummy
 10.10.10.11    0x1         0x2         00:00:00:00:ff:ff     *        dummy
-10.10.10.12    0x1         0x2         00:00:00:00:00:ff     *        dummy`)
+10.10.10.12    0x1         0x2         00:00:00:00:00:ff     *        dummy
+10.10.10.13    0x1         0x2         00:00:00:00:00:aa     *        dummy
+10.10.10.14    0x1         0x2         00:00:00:00:00:bb     *        dummy
+10.10.10.15    0x1         0x2         00:00:00:00:00:cc     *        dummy
+10.10.10.16    0x1         0x2         00:00:00:00:00:dd     *        dummy`)
 	prevArpFile := arpFile
 	arpFile = env.GetPath("/var/tmp/arpcache")
 	defer func() {
@@ -79,6 +118,13 @@ nodes:
 	env.CreateFile("/var/lib/warewulf/chroots/suse/rootfs/usr/share/efi/x86_64/grub.efi")
 	env.CreateFile("/var/lib/warewulf/chroots/suse/rootfs/boot/initramfs-1.1.0.img")
 	env.WriteFile("/etc/warewulf/ipxe/test.ipxe", "{{.KernelVersion}}{{range $devname, $netdev := .NetDevs}}{{if and $netdev.Hwaddr $netdev.Device}} ifname={{$netdev.Device}}:{{$netdev.Hwaddr}} {{end}}{{end}}")
+	env.WriteFile("/etc/warewulf/ipxe/hold.ipxe", "{{.Message}}")
+	env.WriteFile("/etc/warewulf/ipxe/format.ipxe", "wwinit.format={{.ImageFormat}}")
+	env.CreateFile("/var/lib/warewulf/chroots/squash/rootfs/etc/hostname")
+	env.WriteFile("/var/lib/warewulf/chroots/squash/format", "squashfs\n")
+	env.WriteFile("/srv/warewulf/container/suse.img.gz", "cpio image")
+	env.WriteFile("/srv/warewulf/container/suse.img", "")
+	env.WriteFile("/srv/warewulf/container/squash.squashfs", "squashfs image")
 	env.WriteFile("/etc/warewulf/grub/grub.cfg.ww", "{{ .Tags.GrubMenuEntry }}")
 
 	dbErr := LoadNodeDB()
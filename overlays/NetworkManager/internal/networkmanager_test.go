@@ -46,6 +46,12 @@ func Test_networkmanagerOverlay(t *testing.T) {
 			args:       []string{"--render", "node1", "NetworkManager", "etc/NetworkManager/system-connections/ww4-managed.ww"},
 			log:        networkmanager_managed_with_vlan,
 		},
+		{
+			name:       "NetworkManager:ww4-managed.ww with bond",
+			nodes_conf: "nodes.conf-bond",
+			args:       []string{"--render", "node1", "NetworkManager", "etc/NetworkManager/system-connections/ww4-managed.ww"},
+			log:        networkmanager_bond,
+		},
 	}
 
 	for _, tt := range tests {
@@ -186,3 +192,111 @@ addr-gen-mode=stable-privacy
 method=ignore
 never-default=true
 `
+
+const networkmanager_bond string = `backupFile: true
+writeFile: true
+Filename: warewulf-bond0.conf
+# This file is autogenerated by warewulf
+
+[connection]
+id=bond0
+interface-name=bond0
+type=bond
+autoconnect=true
+
+[bond]
+lacp_rate=fast
+miimon=100
+mode=802.3ad
+
+[ipv4]
+address=<nil>
+method=manual
+
+[ipv6]
+addr-gen-mode=stable-privacy
+method=ignore
+never-default=true
+backupFile: true
+writeFile: true
+Filename: warewulf-br0.conf
+# This file is autogenerated by warewulf
+
+[connection]
+id=br0
+interface-name=br0
+type=bridge
+autoconnect=true
+
+[ipv4]
+address=192.168.3.21/24
+gateway=192.168.3.1
+method=manual
+
+[ipv6]
+addr-gen-mode=stable-privacy
+method=ignore
+never-default=true
+backupFile: true
+writeFile: true
+Filename: warewulf-eth0.conf
+# This file is autogenerated by warewulf
+
+[connection]
+id=eth0
+interface-name=enp1s0
+slave-type=bond
+master=bond0
+type=ethernet
+autoconnect=true
+
+[ethernet]
+mac-address=e6:92:39:49:7b:03
+
+[ipv6]
+addr-gen-mode=stable-privacy
+method=ignore
+never-default=true
+backupFile: true
+writeFile: true
+Filename: warewulf-eth1.conf
+# This file is autogenerated by warewulf
+
+[connection]
+id=eth1
+interface-name=enp2s0
+slave-type=bond
+master=bond0
+type=ethernet
+autoconnect=true
+
+[ethernet]
+mac-address=9a:77:29:73:14:f1
+
+[ipv6]
+addr-gen-mode=stable-privacy
+method=ignore
+never-default=true
+backupFile: true
+writeFile: true
+Filename: warewulf-vlan100.conf
+# This file is autogenerated by warewulf
+
+[connection]
+id=vlan100
+interface-name=bond0.100
+slave-type=bridge
+master=br0
+type=vlan
+autoconnect=true
+
+[vlan]
+interface-name=bond0.100
+parent=bond0
+id=100
+
+[ipv6]
+addr-gen-mode=stable-privacy
+method=ignore
+never-default=true
+`
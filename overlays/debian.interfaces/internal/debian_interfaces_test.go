@@ -27,6 +27,12 @@ func Test_wickedOverlay(t *testing.T) {
 			args:       []string{"--render", "node1", "debian.interfaces", "etc/network/interfaces.d/default.ww"},
 			log:        debian_interfaces,
 		},
+		{
+			name:       "debian.interfaces with bond",
+			nodes_conf: "nodes.conf-bond",
+			args:       []string{"--render", "node1", "debian.interfaces", "etc/network/interfaces.d/default.ww"},
+			log:        debian_interfaces_bond,
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,3 +77,56 @@ iface wwnet1 inet static
   mtu 9000
   up ip route add 192.168.1.0/24 via 192.168.3.254 dev wwnet1
 `
+
+const debian_interfaces_bond string = `backupFile: true
+writeFile: true
+Filename: bond0
+# This file is autogenerated by warewulf
+auto bond0
+allow-hotplug bond0
+iface bond0 inet manual
+  
+  bond-slaves enp1s0 enp2s0
+  bond-lacp-rate fast
+  bond-miimon 100
+  bond-mode 802.3ad
+backupFile: true
+writeFile: true
+Filename: br0
+# This file is autogenerated by warewulf
+auto br0
+allow-hotplug br0
+iface br0 inet static
+  address 192.168.3.21
+  netmask 255.255.255.0
+  gateway 192.168.3.1
+  
+  bridge_ports bond0.100
+backupFile: true
+writeFile: true
+Filename: eth0
+# This file is autogenerated by warewulf
+auto enp1s0
+allow-hotplug enp1s0
+iface enp1s0 inet manual
+  bond-master bond0
+  
+backupFile: true
+writeFile: true
+Filename: eth1
+# This file is autogenerated by warewulf
+auto enp2s0
+allow-hotplug enp2s0
+iface enp2s0 inet manual
+  bond-master bond0
+  
+backupFile: true
+writeFile: true
+Filename: vlan100
+# This file is autogenerated by warewulf
+auto bond0.100
+allow-hotplug bond0.100
+iface bond0.100 inet manual
+  
+  vlan-raw-device bond0
+`
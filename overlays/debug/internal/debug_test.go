@@ -109,7 +109,7 @@ data from other structures.
   - Netmask: 255.255.255.0
   - Gateway: 192.168.3.1
   - MTU: 
-  - Primary: false
+  - Primary: true
   - Tags: 
 - NetDevs[secondary]:
   - Type: 
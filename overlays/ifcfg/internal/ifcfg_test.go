@@ -41,6 +41,12 @@ func Test_ifcfgOverlay(t *testing.T) {
 			args:       []string{"--render", "node1", "ifcfg", "etc/sysconfig/network-scripts/ifcfg.ww"},
 			log:        ifcfg_vlan,
 		},
+		{
+			name:       "ifcfg:ifcfg.ww (bond)",
+			nodes_conf: "nodes.conf-bond",
+			args:       []string{"--render", "node1", "ifcfg", "etc/sysconfig/network-scripts/ifcfg.ww"},
+			log:        ifcfg_bond,
+		},
 		{
 			name:       "ifcfg:route.ww",
 			nodes_conf: "nodes.conf-vlan",
@@ -158,3 +164,79 @@ writeFile: true
 Filename: route-untagged.conf
 # This file is autogenerated by warewulf
 `
+
+const ifcfg_bond string = `backupFile: true
+writeFile: true
+Filename: ifcfg-bond0.conf
+# This file is autogenerated by warewulf
+
+TYPE=Bond
+BONDING_MASTER=yes
+BONDING_OPTS="lacp_rate=fast miimon=100 mode=802.3ad"
+DEVICE=bond0
+NAME=bond0
+BOOTPROTO=static
+DEVTIMEOUT=10
+ONBOOT=true
+IPV6INIT=yes
+IPV6_AUTOCONF=yes
+IPV6_DEFROUTE=yes
+IPV6_FAILURE_FATAL=no
+backupFile: true
+writeFile: true
+Filename: ifcfg-br0.conf
+# This file is autogenerated by warewulf
+
+TYPE=Bridge
+DEVICE=br0
+NAME=br0
+BOOTPROTO=static
+DEVTIMEOUT=10
+IPADDR=192.168.3.21
+NETMASK=255.255.255.0
+GATEWAY=192.168.3.1
+ONBOOT=true
+IPV6INIT=yes
+IPV6_AUTOCONF=yes
+IPV6_DEFROUTE=yes
+IPV6_FAILURE_FATAL=no
+backupFile: true
+writeFile: true
+Filename: ifcfg-eth0.conf
+# This file is autogenerated by warewulf
+
+TYPE=ethernet
+DEVICE=enp1s0
+NAME=eth0
+BOOTPROTO=none
+MASTER=bond0
+SLAVE=yes
+HWADDR=e6:92:39:49:7b:03
+ONBOOT=true
+backupFile: true
+writeFile: true
+Filename: ifcfg-eth1.conf
+# This file is autogenerated by warewulf
+
+TYPE=ethernet
+DEVICE=enp2s0
+NAME=eth1
+BOOTPROTO=none
+MASTER=bond0
+SLAVE=yes
+HWADDR=9a:77:29:73:14:f1
+ONBOOT=true
+backupFile: true
+writeFile: true
+Filename: ifcfg-vlan100.conf
+# This file is autogenerated by warewulf
+
+VLAN=yes
+VLAN_ID=100
+PHYSDEV=bond0
+DEVICE=bond0.100
+NAME=vlan100
+BOOTPROTO=none
+BRIDGE=br0
+ONBOOT=true
+`
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/warewulf/warewulf/internal/app/wwctl/overlay/show"
+	warewulfconf "github.com/warewulf/warewulf/internal/pkg/config"
 	"github.com/warewulf/warewulf/internal/pkg/testenv"
 	"github.com/warewulf/warewulf/internal/pkg/wwlog"
 )
@@ -13,23 +14,47 @@ import (
 func Test_netplanOverlay(t *testing.T) {
 	env := testenv.New(t)
 	defer env.RemoveAll()
-	env.ImportFile("etc/warewulf/nodes.conf", "nodes.conf")
 	env.ImportFile("var/lib/warewulf/overlays/netplan/rootfs/etc/netplan/01-netcfg.yaml.ww", "../rootfs/etc/netplan/01-netcfg.yaml.ww")
+	conf := warewulfconf.Get()
+	conf.Networks = map[string]*warewulfconf.NetworkConf{
+		"cluster": {
+			Network: "10.0.0.0/16",
+			Gateway: "10.0.0.1",
+			MTU:     "9000",
+			DNS:     []string{"10.0.0.2", "10.0.0.3"},
+		},
+	}
+	defer func() { conf.Networks = nil }()
 
 	tests := []struct {
-		name string
-		args []string
-		log  string
+		name       string
+		nodes_conf string
+		args       []string
+		log        string
 	}{
 		{
-			name: "netplan",
-			args: []string{"--render", "node1", "netplan", "etc/netplan/01-netcfg.yaml.ww"},
-			log:  netplan,
+			name:       "netplan",
+			nodes_conf: "nodes.conf",
+			args:       []string{"--render", "node1", "netplan", "etc/netplan/01-netcfg.yaml.ww"},
+			log:        netplan,
+		},
+		{
+			name:       "netplan with named network",
+			nodes_conf: "nodes.conf",
+			args:       []string{"--render", "node2", "netplan", "etc/netplan/01-netcfg.yaml.ww"},
+			log:        netplan_network,
+		},
+		{
+			name:       "netplan with bond",
+			nodes_conf: "nodes.conf-bond",
+			args:       []string{"--render", "node1", "netplan", "etc/netplan/01-netcfg.yaml.ww"},
+			log:        netplan_bond,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			env.ImportFile("etc/warewulf/nodes.conf", tt.nodes_conf)
 			cmd := show.GetCommand()
 			cmd.SetArgs(tt.args)
 			stdout := bytes.NewBufferString("")
@@ -59,7 +84,16 @@ network:
         addresses:
            - 192.168.3.21/24
         mtu: 1500
-backupFile: true
+        routes:
+           - to: default
+             via: 192.168.3.1
+     wwnet1:
+        addresses:
+           - 192.168.3.22/24
+        mtu: 9000
+`
+
+const netplan_network string = `backupFile: true
 writeFile: true
 Filename: 01-netcfg.yaml
 # This file is autogenerated by warewulf
@@ -67,8 +101,51 @@ network:
   version: 2
   renderer: networkd
   ethernets:
-     wwnet1:
+     wwnet0:
         addresses:
-           - 192.168.3.22/24
+           - 10.0.1.2/16
         mtu: 9000
+        routes:
+           - to: default
+             via: 10.0.0.1
+        nameservers:
+           addresses:
+              - 10.0.0.2
+              - 10.0.0.3
+`
+
+const netplan_bond string = `backupFile: true
+writeFile: true
+Filename: 01-netcfg.yaml
+# This file is autogenerated by warewulf
+network:
+  version: 2
+  renderer: networkd
+  ethernets:
+     enp1s0:
+        dhcp4: false
+     enp2s0:
+        dhcp4: false
+  bonds:
+     bond0:
+        interfaces:
+           - enp1s0
+           - enp2s0
+        parameters:
+           lacp-rate: fast
+           mii-monitor-interval: 100
+           mode: 802.3ad
+  vlans:
+     bond0.100:
+        id: 100
+        link: bond0
+  bridges:
+     br0:
+        interfaces:
+           - bond0.100
+        addresses:
+           - 192.168.3.21/24
+        routes:
+           - to: default
+             via: 192.168.3.1
 `
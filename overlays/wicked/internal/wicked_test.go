@@ -33,6 +33,12 @@ func Test_wickedOverlay(t *testing.T) {
 			args:       []string{"--render", "node1", "wicked", "etc/wicked/ifconfig/ifcfg.xml.ww"},
 			log:        wicked_vlans,
 		},
+		{
+			name:       "wicked-bonds",
+			nodes_conf: "nodes.conf-bond",
+			args:       []string{"--render", "node1", "wicked", "etc/wicked/ifconfig/ifcfg.xml.ww"},
+			log:        wicked_bonds,
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,3 +201,146 @@ This file is autogenerated by warewulf
   </ipv6>
 </interface>
 `
+
+const wicked_bonds string = `backupFile: true
+writeFile: true
+Filename: ifcfg-bond0.xml
+<!--
+This file is autogenerated by warewulf
+-->
+<interface origin="static generated warewulf config">
+  <name>bond0</name>
+  <link-type>bond</link-type>
+  <bond>
+    <mode>802.3ad</mode>
+    <miimon>
+      <frequency>100</frequency>
+    </miimon>
+    <lacp-rate>fast</lacp-rate>
+    <slaves>
+      <slave>
+        <device>enp1s0</device>
+      </slave>
+      <slave>
+        <device>enp2s0</device>
+      </slave>
+    </slaves>
+  </bond>
+  <control>
+    <mode>boot</mode>
+  </control>
+  <firewall/>
+  <link/>
+  <ipv4>
+    <enabled>true</enabled>
+    <arp-verify>true</arp-verify>
+  </ipv4>
+  <ipv4:static>
+    <address>
+      <local><nil></local>
+    </address>
+  </ipv4:static>
+  <ipv6>
+    <enabled>true</enabled>
+    <privacy>prefer-public</privacy>
+    <accept-redirects>false</accept-redirects>
+  </ipv6>
+</interface>
+backupFile: true
+writeFile: true
+Filename: ifcfg-br0.xml
+<!--
+This file is autogenerated by warewulf
+-->
+<interface origin="static generated warewulf config">
+  <name>br0</name>
+  <link-type>bridge</link-type>
+  <bridge>
+    <ports>
+      <port>
+        <device>bond0.100</device>
+      </port>
+    </ports>
+  </bridge>
+  <control>
+    <mode>boot</mode>
+  </control>
+  <firewall/>
+  <link/>
+  <ipv4>
+    <enabled>true</enabled>
+    <arp-verify>true</arp-verify>
+  </ipv4>
+  <ipv4:static>
+    <address>
+      <local>192.168.3.21/24</local>
+    </address>
+    <route>
+      <nexthop>
+        <gateway>192.168.3.1</gateway>
+      </nexthop>
+    </route>
+  </ipv4:static>
+  <ipv6>
+    <enabled>true</enabled>
+    <privacy>prefer-public</privacy>
+    <accept-redirects>false</accept-redirects>
+  </ipv6>
+</interface>
+backupFile: true
+writeFile: true
+Filename: ifcfg-eth0.xml
+<!--
+This file is autogenerated by warewulf
+-->
+<interface origin="static generated warewulf config">
+  <name>enp1s0</name>
+  <link-type>ethernet</link-type>
+  <control>
+    <mode>boot</mode>
+  </control>
+  <firewall/>
+  <link>
+    <master>bond0</master>
+  </link>
+</interface>
+backupFile: true
+writeFile: true
+Filename: ifcfg-eth1.xml
+<!--
+This file is autogenerated by warewulf
+-->
+<interface origin="static generated warewulf config">
+  <name>enp2s0</name>
+  <link-type>ethernet</link-type>
+  <control>
+    <mode>boot</mode>
+  </control>
+  <firewall/>
+  <link>
+    <master>bond0</master>
+  </link>
+</interface>
+backupFile: true
+writeFile: true
+Filename: ifcfg-vlan100.xml
+<!--
+This file is autogenerated by warewulf
+-->
+<interface origin="static generated warewulf config">
+  <name>bond0.100</name>
+  <link-type>vlan</link-type>
+  <vlan>
+    <device>bond0</device>
+    <tag>100</tag>
+    <protocol>ieee802-1Q</protocol>
+  </vlan>
+  <control>
+    <mode>boot</mode>
+  </control>
+  <firewall/>
+  <link>
+    <master>br0</master>
+  </link>
+</interface>
+`